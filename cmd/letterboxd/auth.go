@@ -0,0 +1,211 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/solean/letterboxd-tui/internal/browser"
+	"github.com/solean/letterboxd-tui/internal/config"
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+	"github.com/solean/letterboxd-tui/internal/logging"
+	"github.com/solean/letterboxd-tui/internal/secrets"
+)
+
+func runAuth(args []string, stdout, stderr io.Writer) int {
+	usage := func() {
+		fmt.Fprintln(stderr, "usage: letterboxd auth import -browser firefox|chromium [-profile <path>] [-account <name>]")
+		fmt.Fprintln(stderr, "       letterboxd auth status [-account <name>]")
+	}
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+	switch args[0] {
+	case "import":
+		return runAuthImport(args[1:], stdout, stderr)
+	case "status":
+		return runAuthStatus(args[1:], stdout, stderr)
+	case "-h", "-help", "--help":
+		usage()
+		return 0
+	}
+	fmt.Fprintf(stderr, "unknown auth command %q\n", args[0])
+	usage()
+	return 2
+}
+
+func runAuthImport(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("auth import", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var browserFlag string
+	var profileFlag string
+	var accountFlag string
+	var printFlag bool
+	fs.StringVar(&browserFlag, "browser", "", "Browser to read cookies from: firefox or chromium (also chrome, brave)")
+	fs.StringVar(&profileFlag, "profile", "", "Browser profile directory or cookie database (default: the browser's default profile)")
+	fs.StringVar(&accountFlag, "account", "", "Named account from the config to save the cookie to")
+	fs.BoolVar(&printFlag, "print", false, "Print the cookie header instead of saving it")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: letterboxd auth import -browser firefox|chromium [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 || browserFlag == "" {
+		fs.Usage()
+		return 2
+	}
+	if err := setupLogging("", ""); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	cookies, path, err := browser.Import(browserFlag, profileFlag)
+	if err != nil {
+		logging.LogError("auth import", err)
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	header, err := browser.Header(cookies, time.Now())
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", path, err)
+		return 1
+	}
+	if printFlag {
+		fmt.Fprintln(stdout, header)
+		return 0
+	}
+	fmt.Fprintf(stderr, "Read %d letterboxd.com cookies from %s\n", len(cookies), path)
+	if clearance, ok := browser.Clearance(cookies); !ok {
+		fmt.Fprintln(stderr, "No cf_clearance cookie. If Letterboxd shows a Cloudflare challenge, open letterboxd.com in the browser and import again.")
+	} else if !clearance.Expires.IsZero() {
+		fmt.Fprintf(stderr, "cf_clearance expires %s\n", clearance.Expires.Local().Format("2006-01-02 15:04"))
+	}
+
+	state, err := resolveStartup("", accountFlag, false)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if state.config.CookieCmd != "" {
+		fmt.Fprintln(stderr, "warning: cookie_cmd is set in the config, so the app reads the cookie from it instead")
+	}
+	store, err := openSecrets(state.config.CookieStore, "", isInteractiveTTY())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	account, _ := state.config.FindAccount(state.account)
+	where := state.configPath
+	if store != nil {
+		if err := store.Set(account.Name, header); err != nil {
+			logging.LogError("auth import", err)
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		account.Cookie = ""
+		where = "the " + state.config.CookieStore + " cookie store"
+		if state.config.CookieStore == secrets.BackendFile {
+			where, _ = config.SecretsPath()
+		}
+	} else {
+		account.Cookie = header
+	}
+	if err := config.Update(func(cfg *config.Config) { cfg.PutAccount(account) }); err != nil {
+		logging.LogError("config save", err)
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Saved the cookie for account %s to %s\n", account.Name, where)
+	return 0
+}
+
+// runAuthStatus checks the saved cookie with Letterboxd, exiting 1 when it
+// no longer signs in as the configured member.
+func runAuthStatus(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("auth status", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var accountFlag string
+	var cookieCmdFlag string
+	var debugFlag bool
+	fs.StringVar(&accountFlag, "account", "", "Named account from the config to check")
+	fs.StringVar(&cookieCmdFlag, "cookie-cmd", "", "Shell command that prints the cookie (overrides cookie_cmd in the config)")
+	fs.BoolVar(&debugFlag, "debug", false, "Include debug details in errors")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: letterboxd auth status [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if err := setupLogging("", ""); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	state, err := resolveStartup("", accountFlag, false)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	cookieCmd := cookieCommand(cookieCmdFlag, state.config)
+	source := state.configPath
+	var store secrets.Store
+	if cookieCmd != "" {
+		source = "cookie_cmd"
+	} else if store, err = openSecrets(state.config.CookieStore, "", isInteractiveTTY()); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	} else if store != nil {
+		source = "the " + state.config.CookieStore + " cookie store"
+	}
+	if err := state.loadCookie(store, cookieCmd, false); err != nil {
+		fmt.Fprintln(stderr, "cookie_cmd:", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "Account:  %s\n", state.account)
+	if state.needUsername {
+		fmt.Fprintln(stdout, "Username: none; run letterboxd -setup")
+		return 1
+	}
+	fmt.Fprintf(stdout, "Username: %s\n", state.username)
+	if state.needCookie {
+		fmt.Fprintln(stdout, "Cookie:   none with com.xk72.webparts.csrf; run letterboxd auth import or letterboxd -setup")
+		return 1
+	}
+	fmt.Fprintf(stdout, "Cookie:   from %s\n", source)
+	if issued, ok := letterboxd.ClearanceIssued(state.cookie); ok {
+		fmt.Fprintf(stdout, "cf_clearance: issued %s\n", issued.Local().Format("2006-01-02 15:04"))
+	} else if !strings.Contains(state.cookie, "cf_clearance=") {
+		fmt.Fprintln(stdout, "cf_clearance: missing")
+	}
+
+	client := letterboxd.NewClient(nil, state.cookie)
+	client.Debug = debugFlag || envBool("LETTERBOXD_DEBUG")
+	err = client.CheckSession(state.username)
+	switch {
+	case err == nil:
+		fmt.Fprintf(stdout, "Session:  signed in as %s\n", state.username)
+		return 0
+	case errors.Is(err, letterboxd.ErrSignedOut):
+		fmt.Fprintln(stdout, "Session:  signed out; the cookie has expired")
+	case errors.Is(err, letterboxd.ErrWrongUser):
+		fmt.Fprintf(stdout, "Session:  %v\n", err)
+	case letterboxd.ClearanceExpired(err):
+		fmt.Fprintln(stdout, "Session:  Cloudflare challenge; cf_clearance is missing or expired")
+	default:
+		logging.LogError("auth status", err)
+		fmt.Fprintln(stderr, "unable to check the session:", err)
+	}
+	return 1
+}
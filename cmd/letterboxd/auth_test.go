@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/solean/letterboxd-tui/internal/config"
+)
+
+func TestRunAuthImportSavesCookie(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+	if err := config.Save(config.Config{Username: "jo", Theme: "light"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	profile := filepath.Join("..", "..", "internal", "browser", "testdata", "firefox")
+	var stdout, stderr bytes.Buffer
+	if code := runAuth([]string{"import", "-browser", "firefox", "-profile", profile}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected success, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "cf_clearance expires") {
+		t.Fatalf("expected the clearance expiry, got %q", stderr.String())
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if !strings.Contains(cfg.Cookie, "com.xk72.webparts.csrf=ffcsrf") || cfg.Theme != "light" || cfg.Username != "jo" {
+		t.Fatalf("expected the imported cookie beside the rest of the config, got %+v", cfg)
+	}
+
+	stdout.Reset()
+	if code := runAuth([]string{"import", "--browser", "firefox", "--profile", profile, "-print"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected success, got %d", code)
+	}
+	if strings.TrimSpace(stdout.String()) != cfg.Cookie {
+		t.Fatalf("expected -print to show the header, got %q", stdout.String())
+	}
+
+	stderr.Reset()
+	if code := runAuth([]string{"import", "-browser", "firefox", "-profile", dir}, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "no cookie database") {
+		t.Fatalf("expected a profile without cookies to fail, got %d %q", code, stderr.String())
+	}
+	if code := runAuth([]string{"import"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("expected usage without -browser, got %d", code)
+	}
+}
+
+func TestRunAuthStatusWithoutCookie(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+	if err := config.Save(config.Config{Username: "jo", Cookie: "letterboxd.user.CURRENT=x"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runAuth([]string{"status"}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected a cookie without CSRF to fail, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Username: jo") || !strings.Contains(stdout.String(), "letterboxd auth import") {
+		t.Fatalf("expected the account and a hint, got %q", stdout.String())
+	}
+	if code := runAuth([]string{"status", "-account", "nobody"}, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "unknown account") {
+		t.Fatalf("expected an unknown account to fail, got %d %q", code, stderr.String())
+	}
+}
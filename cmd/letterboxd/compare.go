@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+	"github.com/solean/letterboxd-tui/internal/logging"
+	"github.com/solean/letterboxd-tui/internal/ui"
+)
+
+func runCompare(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var noCookieFlag bool
+	var debugFlag bool
+	fs.BoolVar(&noCookieFlag, "no-cookie", false, "Run without a stored cookie")
+	fs.BoolVar(&debugFlag, "debug", false, "Show debug errors (stack traces, HTTP details)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: letterboxd compare [flags] <user-a> <user-b>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	userA := strings.TrimSpace(fs.Arg(0))
+	userB := strings.TrimSpace(fs.Arg(1))
+	if fs.NArg() != 2 || userA == "" || userB == "" {
+		fs.Usage()
+		return 2
+	}
+	if strings.EqualFold(userA, userB) {
+		fmt.Fprintln(stderr, "compare needs two different members")
+		return 2
+	}
+	if err := setupLogging("", ""); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	cookie := ""
+	if !noCookieFlag {
+		// The cookie only helps here, so a store that needs a passphrase
+		// without one set is skipped.
+		if state, err := resolveStartup("", "", false); err == nil {
+			if store, err := openSecrets(state.config.CookieStore, "", false); err == nil {
+				if state.loadCookie(store, cookieCommand("", state.config), false) == nil {
+					cookie = state.cookie
+				}
+			}
+		}
+	}
+	client := letterboxd.NewClient(nil, cookie)
+	client.Debug = debugFlag || envBool("LETTERBOXD_DEBUG")
+
+	fmt.Fprintf(stderr, "Comparing @%s and @%s…\n", userA, userB)
+	cmp, err := client.Compare(userA, userB)
+	if err != nil {
+		logging.LogError("compare", err)
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintln(stdout, ui.ComparisonReport(cmp, outputWidth()))
+	return 0
+}
+
+func outputWidth() int {
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	return 80
+}
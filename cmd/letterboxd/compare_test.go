@@ -0,0 +1,24 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunCompareUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runCompare([]string{"jane"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("expected usage exit code, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "usage: letterboxd compare") {
+		t.Fatalf("expected usage output, got %q", stderr.String())
+	}
+	stderr.Reset()
+	if code := runCompare([]string{"jane", "Jane"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("expected error for identical users, got %d", code)
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("unexpected stdout: %q", stdout.String())
+	}
+}
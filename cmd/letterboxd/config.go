@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/solean/letterboxd-tui/internal/config"
+	"github.com/solean/letterboxd-tui/internal/logging"
+	"github.com/solean/letterboxd-tui/internal/secrets"
+	"github.com/solean/letterboxd-tui/internal/ui"
+)
+
+const configUsage = `usage: letterboxd config get [<key>]
+       letterboxd config set <key> <value>
+       letterboxd config edit
+       letterboxd config path`
+
+func runConfig(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, configUsage)
+		return 2
+	}
+	path, err := config.Path()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	switch cmd, rest := args[0], args[1:]; {
+	case cmd == "path" && len(rest) == 0:
+		fmt.Fprintln(stdout, path)
+		return 0
+	case cmd == "get" && len(rest) <= 1:
+		cfg, err := loadConfig()
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		if len(rest) == 0 {
+			printConfig(stdout, cfg)
+			return 0
+		}
+		value, err := cfg.Get(rest[0])
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		fmt.Fprintln(stdout, value)
+		return 0
+	case cmd == "set" && len(rest) == 2:
+		cfg, err := loadConfig()
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		if account, ok := config.CookieAccount(rest[0]); ok && !cfg.CookiesInFile() {
+			return setStoredCookie(cfg, account, rest[1], stderr)
+		}
+		if err := cfg.Set(rest[0], rest[1]); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		if err := checkConfig(cfg); err != nil {
+			fmt.Fprintln(stderr, "invalid config:", err)
+			return 1
+		}
+		if err := config.Save(cfg); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		return 0
+	case cmd == "edit" && len(rest) == 0:
+		return editConfig(path, stdout, stderr)
+	case cmd == "-h" || cmd == "-help" || cmd == "--help":
+		fmt.Fprintln(stderr, configUsage)
+		return 0
+	}
+	fmt.Fprintln(stderr, configUsage)
+	return 2
+}
+
+// setStoredCookie writes a cookie given to `config set` to the cookie store
+// instead of the config file. An empty cookie deletes the stored one.
+func setStoredCookie(cfg config.Config, name, cookie string, stderr io.Writer) int {
+	account, ok := cfg.FindAccount(name)
+	if !ok {
+		fmt.Fprintf(stderr, "no account named %q (add it with letterboxd -setup -account %s)\n", name, name)
+		return 1
+	}
+	store, err := openSecrets(cfg.CookieStore, "", true)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if cookie = strings.TrimSpace(cookie); cookie == "" {
+		err = store.Delete(account.Name)
+		if errors.Is(err, secrets.ErrNotFound) {
+			err = nil
+		}
+	} else {
+		err = store.Set(account.Name, cookie)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// loadConfig is the saved config, or an empty one before the first save.
+func loadConfig() (config.Config, error) {
+	cfg, err := config.Load()
+	if errors.Is(err, os.ErrNotExist) {
+		return config.Config{Version: config.CurrentVersion}, nil
+	}
+	return cfg, err
+}
+
+// checkConfig checks the settings other packages read, the way startup
+// reads them.
+func checkConfig(cfg config.Config) error {
+	if _, _, err := ui.ParseGraphics(cfg.Posters); err != nil {
+		return err
+	}
+	if _, err := secrets.ParseBackend(cfg.CookieStore); err != nil {
+		return fmt.Errorf("cookie_store: %w", err)
+	}
+	if _, err := logging.ParseLevel(cfg.LogLevel); err != nil {
+		return fmt.Errorf("log_level: %w", err)
+	}
+	if _, err := parseLogFormat(cfg.LogFormat); err != nil {
+		return fmt.Errorf("log_format: %w", err)
+	}
+	themesDir, _ := config.ThemesDir()
+	if _, err := ui.LoadThemes(themesDir, cfg.Theme); err != nil {
+		return err
+	}
+	return ui.CheckKeys(cfg.Keys)
+}
+
+func printConfig(w io.Writer, cfg config.Config) {
+	for _, field := range config.Fields {
+		value, _ := cfg.Get(field.Key)
+		if field.Key == "cookie" && value != "" {
+			value = "(set)"
+		}
+		fmt.Fprintf(w, "%s = %s\n", field.Key, value)
+	}
+	for _, account := range cfg.Accounts {
+		fmt.Fprintf(w, "accounts.%s.username = %s\n", account.Name, account.Username)
+	}
+	actions := make([]string, 0, len(cfg.Keys))
+	for action := range cfg.Keys {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	for _, action := range actions {
+		fmt.Fprintf(w, "keys.%s = %s\n", action, strings.Join(cfg.Keys[action], ","))
+	}
+	for _, key := range cfg.Unknown() {
+		value, _ := cfg.Get(key)
+		fmt.Fprintf(w, "%s = %s (unknown to this version, kept)\n", key, value)
+	}
+}
+
+// editConfig opens a copy of the config in $VISUAL or $EDITOR and puts it
+// in place only once it reads back cleanly.
+func editConfig(path string, stdout, stderr io.Writer) int {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		data, err = json.MarshalIndent(config.Config{Version: config.CurrentVersion}, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "config-*.json")
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	for {
+		if err := runEditor(tmp.Name(), stdout, stderr); err != nil {
+			fmt.Fprintln(stderr, "editor:", err)
+			return 1
+		}
+		edited, err := os.ReadFile(tmp.Name())
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		cfg, err := config.Parse(edited)
+		if err == nil {
+			err = checkConfig(cfg)
+		}
+		if err == nil {
+			if err := os.Rename(tmp.Name(), path); err != nil {
+				fmt.Fprintln(stderr, err)
+				return 1
+			}
+			return 0
+		}
+		fmt.Fprintln(stderr, "invalid config:", err)
+		if !isInteractiveTTY() || !confirm(stderr, "Edit again?") {
+			fmt.Fprintln(stderr, "Left", path, "unchanged.")
+			return 1
+		}
+	}
+}
+
+func runEditor(path string, stdout, stderr io.Writer) error {
+	editor := strings.TrimSpace(os.Getenv("VISUAL"))
+	if editor == "" {
+		editor = strings.TrimSpace(os.Getenv("EDITOR"))
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+	// Editors like "code --wait" come with their own arguments.
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+func confirm(w io.Writer, question string) bool {
+	fmt.Fprintf(w, "%s [Y/n] ", question)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return false
+	}
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "" || answer == "y" || answer == "yes"
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/solean/letterboxd-tui/internal/config"
+	"github.com/solean/letterboxd-tui/internal/secrets"
+)
+
+func writeConfigFile(t *testing.T, data string) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+	path, err := config.Path()
+	if err != nil {
+		t.Fatalf("path: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return path
+}
+
+func TestConfigMigratesAndKeepsUnknownKeys(t *testing.T) {
+	path := writeConfigFile(t, `{"username": "jo", "cookie": "", "future": {"on": true}}`)
+
+	var stdout, stderr bytes.Buffer
+	if code := runConfig([]string{"set", "posters", "blocks"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected set to work, got %d: %s", code, stderr.String())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	text := string(data)
+	if !strings.Contains(text, `"version": 1`) || !strings.Contains(text, `"posters": "blocks"`) || !strings.Contains(text, `"future": {`) {
+		t.Fatalf("expected the version, the new value and the unknown key, got %s", text)
+	}
+
+	for key, want := range map[string]string{"username": "jo", "posters": "blocks", "theme": "auto", "cookie_store": "config", "future": `{"on":true}`} {
+		stdout.Reset()
+		if code := runConfig([]string{"get", key}, &stdout, &stderr); code != 0 || strings.TrimSpace(stdout.String()) != want {
+			t.Fatalf("get %s: expected %q, got %d %q", key, want, code, stdout.String())
+		}
+	}
+	stdout.Reset()
+	if code := runConfig([]string{"path"}, &stdout, &stderr); code != 0 || strings.TrimSpace(stdout.String()) != path {
+		t.Fatalf("expected the config path, got %q", stdout.String())
+	}
+}
+
+func TestConfigSetRejectsBadValues(t *testing.T) {
+	writeConfigFile(t, `{"version": 1, "username": "jo"}`)
+
+	cases := map[string][]string{
+		"posters: unknown mode":        {"set", "posters", "ascii"},
+		"cookie_store: unknown":        {"set", "cookie_store", "vault"},
+		"keys: unknown action":         {"set", "keys.fly", "x"},
+		"nickname: unknown key":        {"set", "nickname", "x"},
+		"theme: unknown theme":         {"set", "theme", "neon"},
+		"log_level: unknown log level": {"set", "log_level", "loud"},
+		`no account named "zed"`:       {"get", "accounts.zed.username"},
+	}
+	for want, args := range cases {
+		var stdout, stderr bytes.Buffer
+		if code := runConfig(args, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), want) {
+			t.Fatalf("%v: expected %q, got %d %q", args, want, code, stderr.String())
+		}
+	}
+	cfg, err := config.Load()
+	if err != nil || cfg.Posters != "" || cfg.Theme != "" || cfg.Keys != nil {
+		t.Fatalf("expected nothing saved, got %+v %v", cfg, err)
+	}
+}
+
+func TestConfigLoadPointsAtBadKey(t *testing.T) {
+	path := writeConfigFile(t, `{"username": "jo", "accounts": [{"name": 3}]}`)
+	if _, err := config.Load(); err == nil || !strings.Contains(err.Error(), path+": accounts.0.name: want a string, got number") {
+		t.Fatalf("expected the key in the error, got %v", err)
+	}
+	writeConfigFile(t, "{\n  \"username\": \"jo\",\n}\n")
+	if _, err := config.Load(); err == nil || !strings.Contains(err.Error(), ":3:1: ") {
+		t.Fatalf("expected the line in the error, got %v", err)
+	}
+	writeConfigFile(t, `{"version": 99}`)
+	if _, err := config.Load(); err == nil || !strings.Contains(err.Error(), "newer letterboxd") {
+		t.Fatalf("expected a newer schema to be refused, got %v", err)
+	}
+}
+
+func TestConfigEditKeepsFileUntilValid(t *testing.T) {
+	path := writeConfigFile(t, `{"version": 1, "username": "jo"}`)
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "sed -i -e s/jo/jane/ -e s/1,/1,\"posters\":\"ascii\",/")
+
+	var stdout, stderr bytes.Buffer
+	if code := runConfig([]string{"edit"}, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "posters: unknown mode") {
+		t.Fatalf("expected the bad edit to be refused, got %d %q", code, stderr.String())
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), `"jo"`) {
+		t.Fatalf("expected the config unchanged, got %s", data)
+	}
+
+	t.Setenv("EDITOR", "sed -i -e s/jo/jane/")
+	if code := runConfig([]string{"edit"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected the edit to be kept, got %d %q", code, stderr.String())
+	}
+	if cfg, err := config.Load(); err != nil || cfg.Username != "jane" {
+		t.Fatalf("expected the edited username, got %+v %v", cfg, err)
+	}
+	matches, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "config-*.json"))
+	if len(matches) != 0 {
+		t.Fatalf("expected no temporary files left, got %v", matches)
+	}
+}
+
+func TestConfigSetCookieGoesToCookieStore(t *testing.T) {
+	path := writeConfigFile(t, `{"version": 1, "username": "jo", "cookie_store": "file", "accounts": [{"name": "club", "username": "filmclub"}]}`)
+	t.Setenv("LETTERBOXD_PASSPHRASE", "hunter2")
+
+	var stdout, stderr bytes.Buffer
+	for _, args := range [][]string{{"set", "cookie", "a=b"}, {"set", "accounts.club.cookie", "c=d"}} {
+		if code := runConfig(args, &stdout, &stderr); code != 0 {
+			t.Fatalf("%v: expected the cookie stored, got %d %q", args, code, stderr.String())
+		}
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "a=b") || strings.Contains(string(data), "c=d") {
+		t.Fatalf("expected no cookie in the config file, got %s", data)
+	}
+	store, err := openSecrets("file", "", false)
+	if err != nil {
+		t.Fatalf("open secrets: %v", err)
+	}
+	for account, want := range map[string]string{config.DefaultAccount: "a=b", "club": "c=d"} {
+		if got, err := secrets.Lookup(store, account); err != nil || got != want {
+			t.Fatalf("expected %s's cookie %q in the store, got %q %v", account, want, got, err)
+		}
+	}
+	if code := runConfig([]string{"set", "accounts.zed.cookie", "e=f"}, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), `no account named "zed"`) {
+		t.Fatalf("expected an unknown account refused, got %d %q", code, stderr.String())
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/solean/letterboxd-tui/internal/config"
+	"github.com/solean/letterboxd-tui/internal/logging"
+)
+
+// setupLogging starts the log with the level and format from the flags,
+// falling back to the config's. A config that doesn't load is left for the
+// command to report.
+func setupLogging(levelFlag, formatFlag string) error {
+	cfg, _ := config.Load()
+	levelName := strings.TrimSpace(levelFlag)
+	if levelName == "" {
+		levelName = cfg.LogLevel
+	}
+	level, err := logging.ParseLevel(levelName)
+	if err != nil {
+		return err
+	}
+	format := strings.TrimSpace(formatFlag)
+	if format == "" {
+		format = cfg.LogFormat
+	}
+	asJSON, err := parseLogFormat(format)
+	if err != nil {
+		return err
+	}
+	return logging.Setup(logging.Options{Level: level, JSON: asJSON})
+}
+
+func parseLogFormat(name string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "text":
+		return false, nil
+	case "json":
+		return true, nil
+	}
+	return false, fmt.Errorf("unknown log format %q (want text or json)", name)
+}
@@ -13,21 +13,48 @@ import (
 	"github.com/solean/letterboxd-tui/internal/config"
 	"github.com/solean/letterboxd-tui/internal/letterboxd"
 	"github.com/solean/letterboxd-tui/internal/logging"
+	"github.com/solean/letterboxd-tui/internal/secrets"
+	"github.com/solean/letterboxd-tui/internal/store"
 	"github.com/solean/letterboxd-tui/internal/ui"
 	"github.com/solean/letterboxd-tui/internal/version"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "compare" {
+		os.Exit(runCompare(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "sync" {
+		os.Exit(runSync(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "auth" {
+		os.Exit(runAuth(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfig(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	var userFlag string
+	var accountFlag string
 	var setupFlag bool
 	var noCookieFlag bool
 	var versionFlag bool
 	var debugFlag bool
+	var themeFlag string
+	var noMouseFlag bool
+	var cookieCmdFlag string
+	var logLevelFlag string
+	var logFormatFlag string
 	flag.StringVar(&userFlag, "user", "", "Letterboxd username (override config)")
+	flag.StringVar(&accountFlag, "account", "", "Named account from the config to use (with -setup, adds it)")
 	flag.BoolVar(&setupFlag, "setup", false, "Run first-time setup")
 	flag.BoolVar(&noCookieFlag, "no-cookie", false, "Run without a stored cookie")
+	flag.StringVar(&cookieCmdFlag, "cookie-cmd", "", "Command that prints the cookie, e.g. \"pass show letterboxd\" (override config)")
 	flag.BoolVar(&versionFlag, "version", false, "Print version and exit")
 	flag.BoolVar(&debugFlag, "debug", false, "Show debug errors (stack traces, HTTP details)")
+	flag.StringVar(&logLevelFlag, "log-level", "", "Least severe level written to the log: debug, info, warn or error (override config)")
+	flag.StringVar(&logFormatFlag, "log-format", "", "Log format: text or json (override config)")
+	flag.BoolVar(&noMouseFlag, "no-mouse", false, "Leave the mouse to the terminal (e.g. for selecting text)")
+	flag.StringVar(&themeFlag, "theme", "", "Color theme: auto, dark, light, high-contrast, mono or a user theme (override config)")
 	interactive := isInteractiveTTY()
 	if !interactive && wantsHelp(os.Args[1:]) {
 		flag.Usage()
@@ -44,13 +71,35 @@ func main() {
 		flag.Usage()
 		os.Exit(2)
 	}
+	if err := setupLogging(logLevelFlag, logFormatFlag); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	logging.Logger().Info("start", "version", version.String())
 
-	state, err := resolveStartup(strings.TrimSpace(userFlag))
+	state, err := resolveStartup(strings.TrimSpace(userFlag), accountFlag, setupFlag)
 	if err != nil {
 		logging.LogError("startup", err)
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
+	// Cookies used to live only in the config file; asking where to keep
+	// them once moves them out of it.
+	cookieCmd := cookieCommand(cookieCmdFlag, state.config)
+	needStorage := cookieCmd == "" && (setupFlag || strings.TrimSpace(state.config.CookieStore) == "")
+	var secretStore secrets.Store
+	if !needStorage {
+		if secretStore, err = openSecrets(state.config.CookieStore, "", true); err != nil {
+			logging.LogError("secrets open", err)
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if err := state.loadCookie(secretStore, cookieCmd, false); err != nil {
+		logging.LogError("cookie command", err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 	if setupFlag {
 		state.needUsername = true
 		state.needCookie = true
@@ -61,13 +110,35 @@ func main() {
 		state.cookie = ""
 		state.needCookie = false
 	}
-	if state.needUsername || state.needCookie {
+	themeName := strings.TrimSpace(themeFlag)
+	if themeName == "" {
+		themeName = state.config.Theme
+	}
+	themesDir, _ := config.ThemesDir()
+	themes, err := ui.LoadThemes(themesDir, themeName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid config:", err)
+		os.Exit(2)
+	}
+	graphics, autoGraphics, err := ui.ParseGraphics(state.config.Posters)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid config:", err)
+		os.Exit(2)
+	}
+	if state.needUsername || state.needCookie || needStorage {
+		secretsPath, _ := config.SecretsPath()
 		result, err := ui.RunOnboarding(ui.OnboardingOptions{
-			Username:   state.username,
-			Cookie:     state.cookie,
-			NeedUser:   state.needUsername,
-			NeedCookie: state.needCookie,
-			ConfigPath: state.configPath,
+			Username:         state.username,
+			Cookie:           state.cookie,
+			NeedUser:         state.needUsername,
+			NeedCookie:       state.needCookie,
+			ConfigPath:       state.configPath,
+			Themes:           themes,
+			NeedStorage:      needStorage,
+			KeyringAvailable: secrets.KeyringAvailable(),
+			CookieStore:      state.config.CookieStore,
+			SecretsPath:      secretsPath,
+			SecretsExist:     secrets.FileExists(secretsPath),
 		})
 		if err != nil {
 			logging.LogError("onboarding", err)
@@ -77,6 +148,15 @@ func main() {
 		if result.Cancelled {
 			os.Exit(0)
 		}
+		if result.CookieStore != "" {
+			state.config.CookieStore = result.CookieStore
+			state.configDirty = true
+			if secretStore, err = openSecrets(result.CookieStore, result.Passphrase, true); err != nil {
+				logging.LogError("secrets open", err)
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
 		if state.needUsername {
 			state.username = strings.TrimSpace(result.Username)
 		}
@@ -85,15 +165,18 @@ func main() {
 				state.cookie = strings.TrimSpace(result.Cookie)
 			}
 		}
+		account, _ := state.config.FindAccount(state.account)
 		if state.username != "" {
-			state.config.Username = state.username
+			account.Username = state.username
 			state.configDirty = true
 		}
 		if strings.TrimSpace(result.Cookie) != "" {
-			state.config.Cookie = result.Cookie
+			account.Cookie = result.Cookie
 			state.configDirty = true
 		}
+		state.config.PutAccount(account)
 	}
+	state.migrateCookies(secretStore)
 	if state.configDirty {
 		if err := config.Save(state.config); err != nil {
 			logging.LogError("config save", err)
@@ -106,13 +189,78 @@ func main() {
 		os.Exit(2)
 	}
 
-	cookie := state.cookie
-	client := letterboxd.NewClient(nil, cookie)
-	client.Debug = debugFlag || envBool("LETTERBOXD_DEBUG")
+	if autoGraphics {
+		graphics = ui.DetectGraphics(os.Getenv, func() bool { return ui.QuerySixel(os.Stdin, os.Stdout) })
+	}
+	var posters *store.Posters
+	if graphics != ui.GraphicsOff {
+		if posters, err = store.OpenPosters(); err != nil {
+			// Posters still show, they just get downloaded every time.
+			logging.LogError("posters open", err)
+		}
+	}
+	options := []tea.ProgramOption{tea.WithAltScreen()}
+	if !noMouseFlag {
+		options = append(options, tea.WithMouseCellMotion())
+	}
+
+	debug := debugFlag || envBool("LETTERBOXD_DEBUG")
+	// Warnings go to the terminal only before the app takes over the screen;
+	// they are always logged.
+	open := func(state startupState, warn func(what string, err error)) (ui.Model, error) {
+		client := letterboxd.NewClient(nil, state.cookie)
+		client.Debug = debug
+		m, err := ui.NewModel(state.username, client).
+			WithThemes(themes).
+			WithAccounts(state.config.AccountList(), state.account).
+			WithSecrets(secretStore).
+			WithKeys(state.config.Keys)
+		if err != nil {
+			return ui.Model{}, fmt.Errorf("invalid config: %w", err)
+		}
+		if s, err := store.Open(state.username); err != nil {
+			logging.LogError("store open", err)
+			warn("local store", err)
+		} else {
+			m = m.WithStore(s)
+		}
+		if q, err := store.OpenQueue(state.username); err != nil {
+			logging.LogError("queue open", err)
+			warn("pending changes", err)
+		} else {
+			m = m.WithQueue(q)
+		}
+		if graphics != ui.GraphicsOff {
+			m = m.WithPosters(posters, graphics)
+		}
+		return m, nil
+	}
+	// Switching accounts builds the other account's model while the app
+	// keeps running; the model swaps it in.
+	switchTo := func(name string) (ui.Model, error) {
+		// Reload so a cookie updated in the app is picked up.
+		next, err := resolveStartup("", name, false)
+		if err != nil {
+			return ui.Model{}, err
+		}
+		logging.Logger().Info("account switch", "account", next.account)
+		if err := next.loadCookie(secretStore, cookieCmd, true); err != nil {
+			return ui.Model{}, err
+		}
+		if noCookieFlag {
+			next.cookie = ""
+		}
+		return open(next, func(string, error) {})
+	}
 
-	m := ui.NewModel(state.username, client)
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	m, err := open(state, func(what string, err error) {
+		fmt.Fprintf(os.Stderr, "warning: unable to open %s: %v\n", what, err)
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if _, err := tea.NewProgram(m.WithAccountLoader(switchTo), options...).Run(); err != nil {
 		logging.LogError("bubbletea run", err)
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -120,6 +268,7 @@ func main() {
 }
 
 type startupState struct {
+	account      string
 	username     string
 	cookie       string
 	config       config.Config
@@ -129,7 +278,10 @@ type startupState struct {
 	needCookie   bool
 }
 
-func resolveStartup(userFlag string) (startupState, error) {
+// resolveStartup reads the config for the account named by accountFlag, or
+// the configured one. An unknown account is an error unless create is set,
+// in which case onboarding fills it in.
+func resolveStartup(userFlag, accountFlag string, create bool) (startupState, error) {
 	state := startupState{}
 	if path, err := config.Path(); err == nil {
 		state.configPath = path
@@ -144,12 +296,22 @@ func resolveStartup(userFlag string) (startupState, error) {
 	}
 	state.config = cfg
 
+	account, found := cfg.FindAccount(accountFlag)
+	if !found && !create {
+		var names []string
+		for _, known := range cfg.AccountList() {
+			names = append(names, known.Name)
+		}
+		return startupState{}, fmt.Errorf("unknown account %q (have %s; add it with -account %s -setup)", account.Name, strings.Join(names, ", "), account.Name)
+	}
+	state.account = account.Name
+
 	username := strings.TrimSpace(userFlag)
 	if username == "" {
-		username = strings.TrimSpace(cfg.Username)
+		username = strings.TrimSpace(account.Username)
 	}
 
-	cookie := strings.TrimSpace(cfg.Cookie)
+	cookie := strings.TrimSpace(account.Cookie)
 
 	state.username = username
 	state.cookie = cookie
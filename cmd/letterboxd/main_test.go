@@ -25,7 +25,7 @@ func TestResolveStartupFromUserFlag(t *testing.T) {
 	if err := config.Save(config.Config{Username: "jo", Cookie: "foo=bar; com.xk72.webparts.csrf=cfgcsrf"}); err != nil {
 		t.Fatalf("save config: %v", err)
 	}
-	state, err := resolveStartup("alice")
+	state, err := resolveStartup("alice", "", false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -54,7 +54,7 @@ func TestResolveStartupFromConfig(t *testing.T) {
 	if err := config.Save(config.Config{Username: "jo", Cookie: "foo=bar; com.xk72.webparts.csrf=cfgcsrf"}); err != nil {
 		t.Fatalf("save config: %v", err)
 	}
-	state, err := resolveStartup("")
+	state, err := resolveStartup("", "", false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -83,7 +83,7 @@ func TestResolveStartupFromLegacyCookie(t *testing.T) {
 		t.Fatalf("write legacy cookie: %v", err)
 	}
 
-	state, err := resolveStartup("")
+	state, err := resolveStartup("", "", false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -109,7 +109,7 @@ func TestResolveStartupNeedsOnboarding(t *testing.T) {
 	t.Setenv("HOME", dir)
 	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
 
-	state, err := resolveStartup("")
+	state, err := resolveStartup("", "", false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -117,3 +117,99 @@ func TestResolveStartupNeedsOnboarding(t *testing.T) {
 		t.Fatalf("expected onboarding requirements; got user=%v cookie=%v", state.needUsername, state.needCookie)
 	}
 }
+
+func TestResolveStartupNamedAccount(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+
+	cfg := config.Config{
+		Username: "jo",
+		Cookie:   "a=b; com.xk72.webparts.csrf=jo",
+		Accounts: []config.Account{{Name: "club", Username: "filmclub", Cookie: "c=d; com.xk72.webparts.csrf=club"}},
+		Account:  "club",
+		Theme:    "light",
+	}
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	state, err := resolveStartup("", "", false)
+	if err != nil || state.account != "club" || state.username != "filmclub" {
+		t.Fatalf("expected configured account, got %+v %v", state, err)
+	}
+	state, err = resolveStartup("", "DEFAULT", false)
+	if err != nil || state.username != "jo" || state.cookie != "a=b; com.xk72.webparts.csrf=jo" {
+		t.Fatalf("expected default account, got %+v %v", state, err)
+	}
+	if _, err := resolveStartup("", "work", false); err == nil {
+		t.Fatalf("expected unknown account to fail")
+	}
+	state, err = resolveStartup("", "work", true)
+	if err != nil || state.account != "work" || !state.needUsername {
+		t.Fatalf("expected -setup to allow a new account, got %+v %v", state, err)
+	}
+
+	// Saving a cookie for one account leaves the others and the rest of the
+	// config alone.
+	err = config.Update(func(cfg *config.Config) {
+		cfg.PutAccount(config.Account{Name: "club", Username: "filmclub", Cookie: "new=1; com.xk72.webparts.csrf=new"})
+	})
+	if err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+	saved, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if saved.Cookie != cfg.Cookie || saved.Theme != "light" || saved.Accounts[0].Cookie != "new=1; com.xk72.webparts.csrf=new" {
+		t.Fatalf("unexpected config after update: %+v", saved)
+	}
+}
+
+func TestCookiesMoveToEncryptedFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+	t.Setenv("LETTERBOXD_PASSPHRASE", "hunter2")
+
+	if err := config.Save(config.Config{Username: "jo", Cookie: "a=b; com.xk72.webparts.csrf=jo", CookieStore: "file"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	state, err := resolveStartup("", "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store, err := openSecrets(state.config.CookieStore, "", false)
+	if err != nil {
+		t.Fatalf("open secrets: %v", err)
+	}
+	state.migrateCookies(store)
+	if !state.configDirty || state.config.Cookie != "" {
+		t.Fatalf("expected the cookie to leave the config, got %+v", state.config)
+	}
+	if err := config.Save(state.config); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	state, err = resolveStartup("", "", false)
+	if err != nil || state.cookie != "" {
+		t.Fatalf("expected no cookie in the config, got %q %v", state.cookie, err)
+	}
+	if store, err = openSecrets(state.config.CookieStore, "", false); err != nil {
+		t.Fatalf("reopen secrets: %v", err)
+	}
+	if err := state.loadCookie(store, "", false); err != nil || state.cookie != "a=b; com.xk72.webparts.csrf=jo" || state.needCookie {
+		t.Fatalf("expected the cookie from the encrypted file, got %q %v", state.cookie, err)
+	}
+	if err := state.loadCookie(store, "echo from-command", false); err != nil || state.cookie != "from-command" || state.needCookie {
+		t.Fatalf("expected the cookie command to win, got %q %v", state.cookie, err)
+	}
+
+	t.Setenv("LETTERBOXD_PASSPHRASE", "")
+	if _, err := openSecrets("file", "", false); err == nil {
+		t.Fatalf("expected the encrypted file to need a passphrase")
+	}
+	if _, err := openSecrets("vault", "", false); err == nil {
+		t.Fatalf("expected an unknown store to fail")
+	}
+}
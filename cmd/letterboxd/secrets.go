@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/solean/letterboxd-tui/internal/config"
+	"github.com/solean/letterboxd-tui/internal/logging"
+	"github.com/solean/letterboxd-tui/internal/secrets"
+)
+
+// openSecrets opens the cookie store named by backend, or returns nil when
+// cookies stay in the config file. The encrypted file's passphrase is
+// passphrase if given, else LETTERBOXD_PASSPHRASE, else asked for on the
+// terminal when prompt is set.
+func openSecrets(backend, passphrase string, prompt bool) (secrets.Store, error) {
+	backend, err := secrets.ParseBackend(backend)
+	if err != nil {
+		return nil, err
+	}
+	switch backend {
+	case secrets.BackendKeyring:
+		keyring, ok := secrets.NewKeyring()
+		if !ok {
+			return nil, errors.New("cookie_store is keyring, but no keyring tool was found (security on macOS, secret-tool on Linux)")
+		}
+		return keyring, nil
+	case secrets.BackendFile:
+		path, err := config.SecretsPath()
+		if err != nil {
+			return nil, err
+		}
+		if passphrase == "" {
+			passphrase = os.Getenv("LETTERBOXD_PASSPHRASE")
+		}
+		if passphrase == "" {
+			if !prompt {
+				return nil, errors.New("cookies are encrypted; set LETTERBOXD_PASSPHRASE")
+			}
+			if passphrase, err = readPassphrase(path); err != nil {
+				return nil, err
+			}
+		}
+		return secrets.OpenFile(path, passphrase)
+	}
+	return nil, nil
+}
+
+func readPassphrase(path string) (string, error) {
+	fmt.Fprintf(os.Stderr, "Passphrase for %s: ", path)
+	data, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// loadCookie takes the account's cookie from cookieCmd or store when there
+// is one, over any cookie left in the config. A store that can't be read is
+// reported and the config cookie kept, except midSession, when the app holds
+// the terminal: then the error is returned and cookieCmd gets no input.
+func (s *startupState) loadCookie(store secrets.Store, cookieCmd string, midSession bool) error {
+	switch {
+	case cookieCmd != "":
+		fromCommand := secrets.FromCommand
+		if midSession {
+			fromCommand = secrets.FromCommandNoInput
+		}
+		cookie, err := fromCommand(cookieCmd, s.account)
+		if err != nil {
+			return err
+		}
+		s.cookie = cookie
+		// The command owns the cookie, so onboarding doesn't ask for it.
+		s.needCookie = false
+		return nil
+	case store != nil:
+		cookie, err := secrets.Lookup(store, s.account)
+		if err != nil && midSession {
+			return fmt.Errorf("unable to read the stored cookie: %w", err)
+		}
+		if err != nil {
+			logging.LogError("cookie lookup", err)
+			fmt.Fprintln(os.Stderr, "warning: unable to read the stored cookie:", err)
+		}
+		if cookie != "" {
+			s.cookie = cookie
+		}
+	}
+	s.needCookie = cookieNeedsPrompt(s.cookie)
+	return nil
+}
+
+// migrateCookies moves cookies left in the config into store, marking the
+// config to be saved. Cookies that can't be moved stay where they are.
+func (s *startupState) migrateCookies(store secrets.Store) {
+	if store == nil {
+		return
+	}
+	moved, err := secrets.Migrate(&s.config, store)
+	if err != nil {
+		logging.LogError("cookie migrate", err)
+		fmt.Fprintln(os.Stderr, "warning: cookies left in the config:", err)
+	}
+	if moved > 0 {
+		s.configDirty = true
+	}
+}
+
+func cookieCommand(flagValue string, cfg config.Config) string {
+	if value := strings.TrimSpace(flagValue); value != "" {
+		return value
+	}
+	return strings.TrimSpace(cfg.CookieCmd)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+	"github.com/solean/letterboxd-tui/internal/logging"
+	"github.com/solean/letterboxd-tui/internal/store"
+)
+
+func runSync(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var userFlag string
+	var accountFlag string
+	var fullFlag bool
+	var noCookieFlag bool
+	var debugFlag bool
+	var cookieCmdFlag string
+	var logLevelFlag string
+	fs.StringVar(&userFlag, "user", "", "Letterboxd username (override config)")
+	fs.StringVar(&accountFlag, "account", "", "Named account from the config to use")
+	fs.BoolVar(&fullFlag, "full", false, "Re-read every page instead of stopping at known entries")
+	fs.BoolVar(&noCookieFlag, "no-cookie", false, "Run without a stored cookie")
+	fs.StringVar(&cookieCmdFlag, "cookie-cmd", "", "Command that prints the cookie (override config)")
+	fs.BoolVar(&debugFlag, "debug", false, "Show debug errors (stack traces, HTTP details)")
+	fs.StringVar(&logLevelFlag, "log-level", "", "Least severe level written to the log: debug, info, warn or error (override config)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: letterboxd sync [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if err := setupLogging(logLevelFlag, ""); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	state, err := resolveStartup(userFlag, accountFlag, false)
+	if err != nil {
+		logging.LogError("startup", err)
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if state.username == "" {
+		fmt.Fprintln(stderr, "missing Letterboxd username (run with -setup or pass -user)")
+		return 2
+	}
+	cookie := ""
+	if !noCookieFlag {
+		// Run from cron there is no one to ask for a passphrase, so the
+		// encrypted file needs LETTERBOXD_PASSPHRASE.
+		store, err := openSecrets(state.config.CookieStore, "", isInteractiveTTY())
+		if err == nil {
+			err = state.loadCookie(store, cookieCommand(cookieCmdFlag, state.config), false)
+		}
+		if err != nil {
+			logging.LogError("cookie", err)
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		cookie = state.cookie
+	}
+	client := letterboxd.NewClient(nil, cookie)
+	client.Debug = debugFlag || envBool("LETTERBOXD_DEBUG")
+
+	s, err := store.Open(state.username)
+	if err != nil {
+		logging.LogError("store open", err)
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintf(stderr, "Syncing @%s…\n", state.username)
+	result, err := store.Sync(client, s, store.SyncOptions{Full: fullFlag})
+	if err != nil {
+		logging.LogError("sync", err)
+		fmt.Fprintln(stderr, err)
+		if saveErr := s.Save(); saveErr != nil {
+			logging.LogError("store save", saveErr)
+		}
+		return 1
+	}
+	snap := s.Snapshot()
+	fmt.Fprintf(stdout, "diary      %d new, %d total\n", result.Diary, len(snap.Diary))
+	fmt.Fprintf(stdout, "watchlist  %d new, %d total\n", result.Watchlist, len(snap.Watchlist))
+	fmt.Fprintf(stdout, "films      %d new, %d total\n", result.Films, len(snap.Films))
+	fmt.Fprintf(stdout, "lists      %d new, %d total\n", result.Lists, len(snap.Lists))
+	fmt.Fprintf(stdout, "saved to %s\n", s.Path())
+	return 0
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunSyncUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runSync([]string{"extra"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("expected usage exit code, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "usage: letterboxd sync") {
+		t.Fatalf("expected usage output, got %q", stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("unexpected stdout: %q", stdout.String())
+	}
+}
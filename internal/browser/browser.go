@@ -0,0 +1,131 @@
+// Package browser reads the letterboxd.com cookies out of a browser
+// profile, so they don't have to be copied from the developer tools.
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	Firefox  = "firefox"
+	Chromium = "chromium"
+
+	csrfCookie      = "com.xk72.webparts.csrf"
+	clearanceCookie = "cf_clearance"
+)
+
+var ErrNoCSRF = errors.New("no " + csrfCookie + " cookie; sign in to letterboxd.com in this browser profile first")
+
+// Cookie is one letterboxd.com cookie. Expires is zero for session cookies.
+type Cookie struct {
+	Name    string
+	Value   string
+	Host    string
+	Path    string
+	Expires time.Time
+}
+
+// Import reads the letterboxd.com cookies from a profile of the named
+// browser, returning them with the database they came from. profile is the
+// profile directory, or the cookie database itself; empty means the
+// browser's default profile.
+func Import(browser, profile string) ([]Cookie, string, error) {
+	switch strings.ToLower(strings.TrimSpace(browser)) {
+	case Firefox:
+		path, err := firefoxCookiesPath(profile)
+		if err != nil {
+			return nil, "", err
+		}
+		cookies, err := readFirefox(path)
+		return cookies, path, err
+	case Chromium, "chrome", "brave":
+		path, err := chromiumCookiesPath(profile)
+		if err != nil {
+			return nil, "", err
+		}
+		cookies, err := readChromium(path, systemKeys(path))
+		return cookies, path, err
+	}
+	return nil, "", fmt.Errorf("unknown browser %q (want firefox or chromium)", browser)
+}
+
+// Header joins the cookies still valid at now into a Cookie header. It
+// fails without the CSRF cookie, which every write needs.
+func Header(cookies []Cookie, now time.Time) (string, error) {
+	best := map[string]Cookie{}
+	for _, cookie := range cookies {
+		if cookie.Value == "" || !cookie.Expires.IsZero() && cookie.Expires.Before(now) {
+			continue
+		}
+		if have, ok := best[cookie.Name]; ok && !preferCookie(cookie, have) {
+			continue
+		}
+		best[cookie.Name] = cookie
+	}
+	if _, ok := best[csrfCookie]; !ok {
+		return "", ErrNoCSRF
+	}
+	names := make([]string, 0, len(best))
+	for name := range best {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+"="+best[name].Value)
+	}
+	return strings.Join(parts, "; "), nil
+}
+
+// preferCookie picks between two cookies of the same name the way a
+// browser would send them first: the longer path, then the later expiry.
+func preferCookie(a, b Cookie) bool {
+	if len(a.Path) != len(b.Path) {
+		return len(a.Path) > len(b.Path)
+	}
+	if a.Expires.IsZero() != b.Expires.IsZero() {
+		return a.Expires.IsZero()
+	}
+	return a.Expires.After(b.Expires)
+}
+
+// Clearance returns the Cloudflare clearance cookie, which browsers only
+// get after passing a challenge.
+func Clearance(cookies []Cookie) (Cookie, bool) {
+	for _, cookie := range cookies {
+		if cookie.Name == clearanceCookie {
+			return cookie, true
+		}
+	}
+	return Cookie{}, false
+}
+
+// forLetterboxd keeps cookies a browser would send to letterboxd.com.
+func forLetterboxd(host string) bool {
+	return strings.EqualFold(strings.TrimPrefix(host, "."), "letterboxd.com")
+}
+
+// findCookieDB resolves profile to a cookie database: the file itself, or
+// the first of names inside the directory.
+func findCookieDB(profile string, names ...string) (string, error) {
+	info, err := os.Stat(profile)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return profile, nil
+	}
+	for _, name := range names {
+		path := filepath.Join(profile, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no cookie database in %s (looked for %s)", profile, strings.Join(names, ", "))
+}
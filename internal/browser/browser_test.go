@@ -0,0 +1,117 @@
+package browser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadFirefoxFixture(t *testing.T) {
+	cookies, err := readFirefox(filepath.Join("testdata", "firefox", "cookies.sqlite"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	header, err := Header(cookies, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("header: %v", err)
+	}
+	// The clearance comes from the write-ahead log; the expired cookie,
+	// the container tab's and other sites' are left out.
+	want := "cf_clearance=ffclearance; com.xk72.webparts.csrf=ffcsrf; letterboxd.user.CURRENT=ffuser"
+	if header != want {
+		t.Fatalf("unexpected header:\n got %s\nwant %s", header, want)
+	}
+	clearance, ok := Clearance(cookies)
+	if !ok || clearance.Expires.Year() != 2100 {
+		t.Fatalf("expected the clearance expiry, got %+v", clearance)
+	}
+}
+
+func TestSQLiteReadsOverflowAndInteriorPages(t *testing.T) {
+	db, err := openSQLite(filepath.Join("testdata", "firefox", "cookies.sqlite"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	columns, rows, err := db.table("moz_cookies")
+	if err != nil {
+		t.Fatalf("table: %v", err)
+	}
+	if len(rows) != 67 || columns[0] != "id" || columns[len(columns)-1] != "isPartitionedAttributeSet" {
+		t.Fatalf("unexpected table: %d rows, columns %v", len(rows), columns)
+	}
+	col := columnIndex(columns)
+	for i, row := range rows {
+		if id := asInt(col.get(row, "id")); id != int64(i+1) {
+			t.Fatalf("expected row ids in order, got %d at %d", id, i)
+		}
+		if asString(col.get(row, "name")) == "big" && asString(col.get(row, "value")) != strings.Repeat("x", 6000) {
+			t.Fatalf("expected the overflowing value in full")
+		}
+	}
+}
+
+func TestReadChromiumFixture(t *testing.T) {
+	keys := func(version string) ([]byte, error) {
+		if version == "v11" {
+			return chromiumKey("s3cret", 1)
+		}
+		return chromiumKey("peanuts", 1)
+	}
+	path, err := chromiumCookiesPath(filepath.Join("testdata", "chromium", "Default"))
+	if err != nil {
+		t.Fatalf("path: %v", err)
+	}
+	cookies, err := readChromium(path, keys)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	header, err := Header(cookies, time.Now())
+	if err != nil {
+		t.Fatalf("header: %v", err)
+	}
+	want := "cf_clearance=chromeclearance; com.xk72.webparts.csrf=chromecsrf; letterboxd.user.CURRENT=chromeuser; plain=unencrypted"
+	if header != want {
+		t.Fatalf("unexpected header:\n got %s\nwant %s", header, want)
+	}
+
+	wrong := func(string) ([]byte, error) { return chromiumKey("wrong", 1) }
+	if _, err := readChromium(path, wrong); err == nil {
+		t.Fatalf("expected a wrong key to fail")
+	}
+}
+
+func TestHeaderNeedsCSRF(t *testing.T) {
+	now := time.Now()
+	cookies := []Cookie{
+		{Name: "com.xk72.webparts.csrf", Value: "old", Expires: now.Add(-time.Hour)},
+		{Name: "letterboxd.user.CURRENT", Value: "user"},
+	}
+	if _, err := Header(cookies, now); err != ErrNoCSRF {
+		t.Fatalf("expected an expired CSRF cookie to count as missing, got %v", err)
+	}
+	cookies = append(cookies,
+		Cookie{Name: "com.xk72.webparts.csrf", Value: "root", Path: "/"},
+		Cookie{Name: "com.xk72.webparts.csrf", Value: "session", Path: ""},
+	)
+	if header, err := Header(cookies, now); err != nil || !strings.Contains(header, "csrf=root") {
+		t.Fatalf("expected the more specific cookie, got %q %v", header, err)
+	}
+}
+
+func TestFirefoxDefaultProfileFromINI(t *testing.T) {
+	root := t.TempDir()
+	ini := "[Profile1]\nName=old\nIsRelative=1\nPath=Profiles/old.default\nDefault=1\n\n" +
+		"[Install4F96D1932A9F858E]\nDefault=Profiles/abc.default-release\nLocked=1\n"
+	if err := os.WriteFile(filepath.Join(root, "profiles.ini"), []byte(ini), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	profile, ok := profileFromINI(root)
+	if !ok || profile != filepath.Join(root, "Profiles", "abc.default-release") {
+		t.Fatalf("expected the install default, got %q", profile)
+	}
+	if _, _, err := Import("netscape", ""); err == nil {
+		t.Fatalf("expected an unknown browser to fail")
+	}
+}
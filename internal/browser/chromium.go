@@ -0,0 +1,187 @@
+package browser
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// chromiumEpoch is 1601-01-01, where Chromium counts expiry microseconds
+// from, in seconds before the Unix epoch.
+const chromiumEpoch = 11644473600
+
+// keySource returns the AES key for an encrypted value's version prefix,
+// "v10" or "v11".
+type keySource func(version string) ([]byte, error)
+
+func readChromium(path string, keys keySource) ([]Cookie, error) {
+	db, err := openSQLite(path)
+	if err != nil {
+		return nil, err
+	}
+	// Since schema 24 the decrypted value starts with a hash of the host.
+	hashed := false
+	if names, meta, err := db.table("meta"); err == nil {
+		col := columnIndex(names)
+		for _, row := range meta {
+			if asString(col.get(row, "key")) == "version" {
+				version, _ := strconv.Atoi(asString(col.get(row, "value")))
+				hashed = version >= 24
+			}
+		}
+	}
+	names, rows, err := db.table("cookies")
+	if err != nil {
+		return nil, err
+	}
+	col := columnIndex(names)
+	var cookies []Cookie
+	for _, row := range rows {
+		get := func(name string) any { return col.get(row, name) }
+		host := asString(get("host_key"))
+		if !forLetterboxd(host) {
+			continue
+		}
+		value := asString(get("value"))
+		if encrypted, _ := get("encrypted_value").([]byte); value == "" && len(encrypted) > 0 {
+			plain, err := decryptChromium(encrypted, keys)
+			if err != nil {
+				return nil, fmt.Errorf("decrypt %s: %w", asString(get("name")), err)
+			}
+			if hashed && len(plain) >= 32 {
+				plain = plain[32:]
+			}
+			value = string(plain)
+		}
+		cookie := Cookie{
+			Name:  asString(get("name")),
+			Value: value,
+			Host:  host,
+			Path:  asString(get("path")),
+		}
+		if expires := asInt(get("expires_utc")); expires > 0 {
+			cookie.Expires = time.Unix(expires/1e6-chromiumEpoch, expires%1e6*1e3)
+		}
+		cookies = append(cookies, cookie)
+	}
+	return cookies, nil
+}
+
+// decryptChromium opens a value sealed the way Chromium does on Linux and
+// macOS: a version prefix, then AES-128-CBC with an IV of spaces.
+func decryptChromium(encrypted []byte, keys keySource) ([]byte, error) {
+	if len(encrypted) < 3 {
+		return nil, errors.New("value too short")
+	}
+	version := string(encrypted[:3])
+	if version != "v10" && version != "v11" {
+		return nil, fmt.Errorf("unsupported encryption %q", version)
+	}
+	key, err := keys(version)
+	if err != nil {
+		return nil, err
+	}
+	data := encrypted[3:]
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, errors.New("bad ciphertext length")
+	}
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, bytes.Repeat([]byte{' '}, aes.BlockSize)).CryptBlocks(plain, data)
+	pad := int(plain[len(plain)-1])
+	if pad == 0 || pad > aes.BlockSize || pad > len(plain) || !bytes.Equal(plain[len(plain)-pad:], bytes.Repeat([]byte{byte(pad)}, pad)) {
+		return nil, errors.New("wrong key")
+	}
+	return plain[:len(plain)-pad], nil
+}
+
+func chromiumKey(password string, iterations int) ([]byte, error) {
+	return pbkdf2.Key(sha1.New, password, []byte("saltysalt"), iterations, 16)
+}
+
+// systemKeys finds the keys for the browser whose cookie database is at
+// path. On Linux, v10 values use a fixed password and v11 values one kept
+// in the Secret Service; on macOS the password is in the keychain.
+func systemKeys(path string) keySource {
+	app, label := chromiumApp(path)
+	return func(version string) ([]byte, error) {
+		switch runtime.GOOS {
+		case "linux", "freebsd", "openbsd":
+			if version == "v10" {
+				return chromiumKey("peanuts", 1)
+			}
+			out, err := exec.Command("secret-tool", "lookup", "application", app).Output()
+			if err != nil || len(bytes.TrimSpace(out)) == 0 {
+				return nil, fmt.Errorf("no %s key in the keyring (secret-tool lookup application %s)", label, app)
+			}
+			return chromiumKey(string(bytes.TrimSpace(out)), 1)
+		case "darwin":
+			out, err := exec.Command("security", "find-generic-password", "-w", "-s", label).Output()
+			if err != nil {
+				return nil, fmt.Errorf("no %q in the keychain", label)
+			}
+			return chromiumKey(string(bytes.TrimSpace(out)), 1003)
+		}
+		return nil, fmt.Errorf("reading Chromium cookies isn't supported on %s", runtime.GOOS)
+	}
+}
+
+// chromiumApp guesses which Chromium-based browser a profile belongs to,
+// for the name its key is stored under.
+func chromiumApp(path string) (string, string) {
+	lower := strings.ToLower(filepath.ToSlash(path))
+	switch {
+	case strings.Contains(lower, "google-chrome") || strings.Contains(lower, "google/chrome"):
+		return "chrome", "Chrome Safe Storage"
+	case strings.Contains(lower, "brave"):
+		return "brave", "Brave Safe Storage"
+	}
+	return "chromium", "Chromium Safe Storage"
+}
+
+func chromiumCookiesPath(profile string) (string, error) {
+	if strings.TrimSpace(profile) != "" {
+		return findCookieDB(profile, filepath.Join("Network", "Cookies"), "Cookies")
+	}
+	for _, dir := range chromiumProfiles() {
+		if path, err := findCookieDB(dir, filepath.Join("Network", "Cookies"), "Cookies"); err == nil {
+			return path, nil
+		}
+	}
+	return "", errors.New("no Chromium profile found; pass -profile")
+}
+
+func chromiumProfiles() []string {
+	home, _ := os.UserHomeDir()
+	if runtime.GOOS == "darwin" {
+		base := filepath.Join(home, "Library", "Application Support")
+		return []string{
+			filepath.Join(base, "Chromium", "Default"),
+			filepath.Join(base, "Google", "Chrome", "Default"),
+			filepath.Join(base, "BraveSoftware", "Brave-Browser", "Default"),
+		}
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return nil
+	}
+	return []string{
+		filepath.Join(base, "chromium", "Default"),
+		filepath.Join(base, "google-chrome", "Default"),
+		filepath.Join(base, "BraveSoftware", "Brave-Browser", "Default"),
+	}
+}
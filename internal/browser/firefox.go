@@ -0,0 +1,153 @@
+package browser
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+func readFirefox(path string) ([]Cookie, error) {
+	db, err := openSQLite(path)
+	if err != nil {
+		return nil, err
+	}
+	columns, rows, err := db.table("moz_cookies")
+	if err != nil {
+		return nil, err
+	}
+	col := columnIndex(columns)
+	var cookies []Cookie
+	for _, row := range rows {
+		get := func(name string) any { return col.get(row, name) }
+		if !forLetterboxd(asString(get("host"))) {
+			continue
+		}
+		// Cookies from container tabs and private windows carry origin
+		// attributes; the normal session has none.
+		if asString(get("originAttributes")) != "" {
+			continue
+		}
+		cookies = append(cookies, Cookie{
+			Name:    asString(get("name")),
+			Value:   asString(get("value")),
+			Host:    asString(get("host")),
+			Path:    asString(get("path")),
+			Expires: firefoxExpiry(asInt(get("expiry"))),
+		})
+	}
+	return cookies, nil
+}
+
+// firefoxExpiry reads expiry, which is in seconds, or in milliseconds in
+// newer releases.
+func firefoxExpiry(expiry int64) time.Time {
+	if expiry <= 0 {
+		return time.Time{}
+	}
+	if expiry > 1e11 {
+		return time.UnixMilli(expiry)
+	}
+	return time.Unix(expiry, 0)
+}
+
+func firefoxCookiesPath(profile string) (string, error) {
+	if strings.TrimSpace(profile) == "" {
+		var err error
+		if profile, err = firefoxDefaultProfile(); err != nil {
+			return "", err
+		}
+	}
+	return findCookieDB(profile, "cookies.sqlite")
+}
+
+func firefoxRoots() []string {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{filepath.Join(home, "Library", "Application Support", "Firefox")}
+	case "windows":
+		return []string{filepath.Join(os.Getenv("APPDATA"), "Mozilla", "Firefox")}
+	}
+	return []string{
+		filepath.Join(home, ".mozilla", "firefox"),
+		filepath.Join(home, "snap", "firefox", "common", ".mozilla", "firefox"),
+		filepath.Join(home, ".var", "app", "org.mozilla.firefox", ".mozilla", "firefox"),
+	}
+}
+
+func firefoxDefaultProfile() (string, error) {
+	for _, root := range firefoxRoots() {
+		if profile, ok := profileFromINI(root); ok {
+			return profile, nil
+		}
+	}
+	return "", errors.New("no Firefox profile found; pass -profile")
+}
+
+// profileFromINI reads profiles.ini under root. The install section names
+// the profile Firefox last opened, which wins over the one marked default.
+func profileFromINI(root string) (string, bool) {
+	file, err := os.Open(filepath.Join(root, "profiles.ini"))
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+
+	type section struct {
+		name   string
+		values map[string]string
+	}
+	var sections []section
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			sections = append(sections, section{name: line[1 : len(line)-1], values: map[string]string{}})
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && len(sections) > 0 {
+			sections[len(sections)-1].values[key] = value
+		}
+	}
+	resolve := func(path string, relative bool) string {
+		if relative {
+			return filepath.Join(root, filepath.FromSlash(path))
+		}
+		return path
+	}
+	for _, s := range sections {
+		if strings.HasPrefix(s.name, "Install") && s.values["Default"] != "" {
+			return resolve(s.values["Default"], true), true
+		}
+	}
+	for _, s := range sections {
+		if strings.HasPrefix(s.name, "Profile") && s.values["Default"] == "1" && s.values["Path"] != "" {
+			return resolve(s.values["Path"], s.values["IsRelative"] != "0"), true
+		}
+	}
+	return "", false
+}
+
+type columns map[string]int
+
+func columnIndex(names []string) columns {
+	index := columns{}
+	for i, name := range names {
+		index[name] = i
+	}
+	return index
+}
+
+// get reads a column by name. Rows written before a column was added are
+// shorter, and read as NULL.
+func (c columns) get(row []any, name string) any {
+	i, ok := c[name]
+	if !ok || i >= len(row) {
+		return nil
+	}
+	return row[i]
+}
@@ -0,0 +1,365 @@
+package browser
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+// sqliteDB reads tables out of an SQLite file without a driver, which would
+// need cgo. It only does what reading a cookie jar takes: full scans of
+// rowid tables, with committed pages from the write-ahead log laid over the
+// main file, since browsers keep their cookie databases in WAL mode.
+type sqliteDB struct {
+	data     []byte
+	pageSize int
+	usable   int
+	wal      map[uint32][]byte
+}
+
+const sqliteMagic = "SQLite format 3\x00"
+
+func openSQLite(path string) (*sqliteDB, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 100 || string(data[:16]) != sqliteMagic {
+		return nil, fmt.Errorf("%s: not an SQLite database", path)
+	}
+	db := &sqliteDB{data: data}
+	db.pageSize = int(binary.BigEndian.Uint16(data[16:18]))
+	if db.pageSize == 1 {
+		db.pageSize = 65536
+	}
+	if db.pageSize < 512 {
+		return nil, fmt.Errorf("%s: bad page size %d", path, db.pageSize)
+	}
+	db.usable = db.pageSize - int(data[20])
+	if wal, err := os.ReadFile(path + "-wal"); err == nil {
+		db.wal = readWAL(wal, db.pageSize)
+	}
+	return db, nil
+}
+
+// readWAL returns the newest copy of each page written by a committed
+// transaction. Frames from an older generation of the log carry different
+// salts and end the scan.
+func readWAL(wal []byte, pageSize int) map[uint32][]byte {
+	const headerSize, frameHeader = 32, 24
+	if len(wal) < headerSize {
+		return nil
+	}
+	magic := binary.BigEndian.Uint32(wal[0:4])
+	if magic != 0x377f0682 && magic != 0x377f0683 {
+		return nil
+	}
+	if int(binary.BigEndian.Uint32(wal[8:12])) != pageSize {
+		return nil
+	}
+	salt1, salt2 := binary.BigEndian.Uint32(wal[16:20]), binary.BigEndian.Uint32(wal[20:24])
+	pages := map[uint32][]byte{}
+	pending := map[uint32][]byte{}
+	for off := headerSize; off+frameHeader+pageSize <= len(wal); off += frameHeader + pageSize {
+		frame := wal[off : off+frameHeader]
+		if binary.BigEndian.Uint32(frame[8:12]) != salt1 || binary.BigEndian.Uint32(frame[12:16]) != salt2 {
+			break
+		}
+		pending[binary.BigEndian.Uint32(frame[0:4])] = wal[off+frameHeader : off+frameHeader+pageSize]
+		if binary.BigEndian.Uint32(frame[4:8]) != 0 {
+			for number, page := range pending {
+				pages[number] = page
+			}
+			pending = map[uint32][]byte{}
+		}
+	}
+	return pages
+}
+
+func (db *sqliteDB) page(number uint32) ([]byte, error) {
+	if page, ok := db.wal[number]; ok {
+		return page, nil
+	}
+	start := int(number-1) * db.pageSize
+	if number == 0 || start+db.pageSize > len(db.data) {
+		return nil, fmt.Errorf("page %d out of range", number)
+	}
+	return db.data[start : start+db.pageSize], nil
+}
+
+// table returns the named table's columns and every row, with values as
+// int64, float64, string, []byte or nil.
+func (db *sqliteDB) table(name string) ([]string, [][]any, error) {
+	schema, err := db.scan(1)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, row := range schema {
+		if len(row) < 5 || row[0] != "table" || !strings.EqualFold(asString(row[1]), name) {
+			continue
+		}
+		root, ok := row[3].(int64)
+		if !ok {
+			break
+		}
+		columns, rowidColumn := parseColumns(asString(row[4]))
+		rows, err := db.scanWithRowid(uint32(root), rowidColumn)
+		return columns, rows, err
+	}
+	return nil, nil, fmt.Errorf("no %s table", name)
+}
+
+func (db *sqliteDB) scan(root uint32) ([][]any, error) {
+	return db.scanWithRowid(root, -1)
+}
+
+// scanWithRowid walks a table b-tree in order. An INTEGER PRIMARY KEY column
+// is stored as NULL and stands for the rowid, so it is filled in from it.
+func (db *sqliteDB) scanWithRowid(root uint32, rowidColumn int) ([][]any, error) {
+	var rows [][]any
+	seen := map[uint32]bool{}
+	var walk func(number uint32) error
+	walk = func(number uint32) error {
+		if seen[number] {
+			return fmt.Errorf("page %d visited twice", number)
+		}
+		seen[number] = true
+		page, err := db.page(number)
+		if err != nil {
+			return err
+		}
+		header := page
+		if number == 1 {
+			header = page[100:]
+		}
+		kind := header[0]
+		count := int(binary.BigEndian.Uint16(header[3:5]))
+		switch kind {
+		case 0x05:
+			pointers := header[12:]
+			for i := 0; i < count; i++ {
+				cell := int(binary.BigEndian.Uint16(pointers[2*i:]))
+				if cell+4 > len(page) {
+					return fmt.Errorf("page %d: bad cell", number)
+				}
+				if err := walk(binary.BigEndian.Uint32(page[cell:])); err != nil {
+					return err
+				}
+			}
+			return walk(binary.BigEndian.Uint32(header[8:12]))
+		case 0x0d:
+			pointers := header[8:]
+			for i := 0; i < count; i++ {
+				cell := int(binary.BigEndian.Uint16(pointers[2*i:]))
+				row, rowid, err := db.leafCell(page, cell)
+				if err != nil {
+					return fmt.Errorf("page %d: %w", number, err)
+				}
+				if rowidColumn >= 0 && rowidColumn < len(row) && row[rowidColumn] == nil {
+					row[rowidColumn] = rowid
+				}
+				rows = append(rows, row)
+			}
+			return nil
+		}
+		return fmt.Errorf("page %d: not a table page (%#x)", number, kind)
+	}
+	return rows, walk(root)
+}
+
+func (db *sqliteDB) leafCell(page []byte, cell int) ([]any, int64, error) {
+	if cell >= len(page) {
+		return nil, 0, errors.New("bad cell")
+	}
+	size, n := readVarint(page[cell:])
+	cell += n
+	rowid, n := readVarint(page[cell:])
+	cell += n
+	payload, err := db.payload(page, cell, int(size))
+	if err != nil {
+		return nil, 0, err
+	}
+	row, err := decodeRecord(payload)
+	return row, int64(rowid), err
+}
+
+// payload gathers a cell's payload, following overflow pages when it
+// doesn't fit on its page.
+func (db *sqliteDB) payload(page []byte, start, size int) ([]byte, error) {
+	u := db.usable
+	maxLocal := u - 35
+	local := size
+	if size > maxLocal {
+		minLocal := (u-12)*32/255 - 23
+		local = minLocal + (size-minLocal)%(u-4)
+		if local > maxLocal {
+			local = minLocal
+		}
+	}
+	if start+local > len(page) {
+		return nil, errors.New("payload past end of page")
+	}
+	out := append([]byte(nil), page[start:start+local]...)
+	if local == size {
+		return out, nil
+	}
+	if start+local+4 > len(page) {
+		return nil, errors.New("missing overflow pointer")
+	}
+	next := binary.BigEndian.Uint32(page[start+local:])
+	for len(out) < size {
+		if next == 0 {
+			return nil, errors.New("overflow chain ends early")
+		}
+		overflow, err := db.page(next)
+		if err != nil {
+			return nil, err
+		}
+		next = binary.BigEndian.Uint32(overflow[0:4])
+		out = append(out, overflow[4:min(u, 4+size-len(out))]...)
+	}
+	return out, nil
+}
+
+func decodeRecord(payload []byte) ([]any, error) {
+	headerSize, n := readVarint(payload)
+	if int(headerSize) > len(payload) {
+		return nil, errors.New("bad record header")
+	}
+	var types []uint64
+	for pos := n; pos < int(headerSize); {
+		serial, n := readVarint(payload[pos:])
+		types = append(types, serial)
+		pos += n
+	}
+	body := payload[headerSize:]
+	row := make([]any, 0, len(types))
+	for _, serial := range types {
+		size := serialSize(serial)
+		if size > len(body) {
+			return nil, errors.New("record past end of payload")
+		}
+		value := body[:size]
+		body = body[size:]
+		switch {
+		case serial == 0:
+			row = append(row, nil)
+		case serial <= 6:
+			v := int64(int8(value[0]))
+			for _, b := range value[1:] {
+				v = v<<8 | int64(b)
+			}
+			row = append(row, v)
+		case serial == 7:
+			row = append(row, math.Float64frombits(binary.BigEndian.Uint64(value)))
+		case serial == 8:
+			row = append(row, int64(0))
+		case serial == 9:
+			row = append(row, int64(1))
+		case serial >= 12 && serial%2 == 0:
+			row = append(row, append([]byte(nil), value...))
+		case serial >= 13:
+			row = append(row, string(value))
+		default:
+			return nil, fmt.Errorf("unknown serial type %d", serial)
+		}
+	}
+	return row, nil
+}
+
+func serialSize(serial uint64) int {
+	switch serial {
+	case 0, 8, 9:
+		return 0
+	case 1, 2, 3, 4:
+		return int(serial)
+	case 5:
+		return 6
+	case 6, 7:
+		return 8
+	}
+	if serial >= 12 {
+		return int(serial-12) / 2
+	}
+	return 0
+}
+
+func readVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 9 && i < len(b); i++ {
+		if i == 8 {
+			return v<<8 | uint64(b[i]), 9
+		}
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return v, max(1, len(b))
+}
+
+// parseColumns reads column names out of a CREATE TABLE statement, and
+// which one, if any, is an INTEGER PRIMARY KEY.
+func parseColumns(sql string) ([]string, int) {
+	open, close := strings.Index(sql, "("), strings.LastIndex(sql, ")")
+	if open == -1 || close <= open {
+		return nil, -1
+	}
+	var columns []string
+	rowid := -1
+	depth, start := 0, open+1
+	defs := []string{}
+	for i := open + 1; i < close; i++ {
+		switch sql[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				defs = append(defs, sql[start:i])
+				start = i + 1
+			}
+		}
+	}
+	defs = append(defs, sql[start:close])
+	for _, def := range defs {
+		fields := strings.Fields(def)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "PRIMARY", "UNIQUE", "CONSTRAINT", "CHECK", "FOREIGN":
+			continue
+		}
+		upper := strings.ToUpper(def)
+		if len(fields) > 1 && strings.ToUpper(fields[1]) == "INTEGER" && strings.Contains(upper, "PRIMARY KEY") {
+			rowid = len(columns)
+		}
+		columns = append(columns, strings.Trim(fields[0], "\"`[]"))
+	}
+	return columns, rowid
+}
+
+func asString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return ""
+}
+
+func asInt(v any) int64 {
+	switch v := v.(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
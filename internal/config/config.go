@@ -2,13 +2,95 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
+// DefaultAccount names the account kept in the top-level username and
+// cookie.
+const DefaultAccount = "default"
+
+// Account is one Letterboxd login.
+type Account struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Cookie   string `json:"cookie,omitempty"`
+}
+
 type Config struct {
+	// Version is the schema the file was written with; see CurrentVersion.
+	Version  int    `json:"version"`
 	Username string `json:"username"`
 	Cookie   string `json:"cookie"`
+	// Accounts are further named logins, and Account names the one to use
+	// when -account isn't given.
+	Accounts []Account `json:"accounts,omitempty"`
+	Account  string    `json:"account,omitempty"`
+	// Keys remaps key bindings by action name, e.g. "down": ["n", "down"].
+	Keys map[string][]string `json:"keys,omitempty"`
+	// Theme names a built-in or user theme; empty or "auto" picks one to
+	// suit the terminal.
+	Theme string `json:"theme,omitempty"`
+	// Posters is how the film view draws posters: auto, off, blocks, kitty,
+	// iterm or sixel. Empty means auto.
+	Posters string `json:"posters,omitempty"`
+	// CookieStore is where cookies are kept: config (here, in plain text),
+	// file (encrypted beside this file) or keyring. CookieCmd, when set,
+	// prints the cookie instead, e.g. "pass show letterboxd".
+	CookieStore string `json:"cookie_store,omitempty"`
+	CookieCmd   string `json:"cookie_cmd,omitempty"`
+	// LogLevel is the least severe level written to the log: debug, info,
+	// warn or error. LogFormat is text or json.
+	LogLevel  string `json:"log_level,omitempty"`
+	LogFormat string `json:"log_format,omitempty"`
+
+	// extra keeps keys this version doesn't know, so saving doesn't drop
+	// settings written by a newer version or by hand.
+	extra map[string]json.RawMessage
+}
+
+// AccountList is every account, the default one first.
+func (c Config) AccountList() []Account {
+	var list []Account
+	if c.Username != "" || c.Cookie != "" || len(c.Accounts) == 0 {
+		list = append(list, Account{Name: DefaultAccount, Username: c.Username, Cookie: c.Cookie})
+	}
+	return append(list, c.Accounts...)
+}
+
+// FindAccount looks an account up by name, ignoring case. An empty name
+// means the configured account, or the default one.
+func (c Config) FindAccount(name string) (Account, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = strings.TrimSpace(c.Account)
+	}
+	if name == "" || strings.EqualFold(name, DefaultAccount) {
+		return Account{Name: DefaultAccount, Username: c.Username, Cookie: c.Cookie}, true
+	}
+	for _, account := range c.Accounts {
+		if strings.EqualFold(account.Name, name) {
+			return account, true
+		}
+	}
+	return Account{Name: name}, false
+}
+
+// PutAccount adds account or replaces the one with its name.
+func (c *Config) PutAccount(account Account) {
+	if account.Name == "" || strings.EqualFold(account.Name, DefaultAccount) {
+		c.Username, c.Cookie = account.Username, account.Cookie
+		return
+	}
+	for i := range c.Accounts {
+		if strings.EqualFold(c.Accounts[i].Name, account.Name) {
+			c.Accounts[i] = account
+			return
+		}
+	}
+	c.Accounts = append(c.Accounts, account)
 }
 
 func Path() (string, error) {
@@ -19,6 +101,24 @@ func Path() (string, error) {
 	return filepath.Join(base, "letterboxd-tui", "config.json"), nil
 }
 
+// ThemesDir is where user theme files live, next to the config file.
+func ThemesDir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "letterboxd-tui", "themes"), nil
+}
+
+// SecretsPath is the encrypted cookie file, next to the config file.
+func SecretsPath() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "letterboxd-tui", "secrets.json"), nil
+}
+
 func Load() (Config, error) {
 	path, err := Path()
 	if err != nil {
@@ -28,13 +128,27 @@ func Load() (Config, error) {
 	if err != nil {
 		return Config{}, err
 	}
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	cfg, err := Parse(data)
+	if err != nil {
+		var cfgErr *Error
+		if errors.As(err, &cfgErr) {
+			cfgErr.Path = path
+		}
 		return Config{}, err
 	}
 	return cfg, nil
 }
 
+// Update applies change to the saved config, keeping everything else in it.
+func Update(change func(*Config)) error {
+	cfg, err := Load()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	change(&cfg)
+	return Save(cfg)
+}
+
 func Save(cfg Config) error {
 	path, err := Path()
 	if err != nil {
@@ -43,6 +157,7 @@ func Save(cfg Config) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
 		return err
 	}
+	cfg.Version = CurrentVersion
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return err
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		data string
+		want Error
+	}{
+		{"{\n  \"username\": \"jo\",\n}\n", Error{Line: 3, Column: 1}},
+		{"{\"username\": \"jo\"\n\"theme\": \"dark\"}", Error{Line: 2, Column: 1}},
+		{`{"username": 3}`, Error{Key: "username", Msg: "want a string, got number"}},
+		{`{"accounts": [{"name": 3}]}`, Error{Key: "accounts.0.name", Msg: "want a string, got number"}},
+		{`{"keys": {"down": "n"}}`, Error{Key: "keys.down", Msg: "want a list, got string"}},
+		{`[]`, Error{Key: "(top level)", Msg: "want an object, got array"}},
+		{`null`, Error{Msg: "want a JSON object"}},
+		{`{"version": "one"}`, Error{Key: "version", Msg: `want a schema number, got "one"`}},
+		{`{"version": -1}`, Error{Key: "version", Msg: "want a schema number, got -1"}},
+		{`{"version": 99}`, Error{Key: "version"}},
+		{`{"accounts": [{"username": "jo"}]}`, Error{Key: "accounts.0.name", Msg: "every account needs a name"}},
+		{`{"accounts": [{"name": "Default"}]}`, Error{Key: "accounts.0.name"}},
+		{`{"accounts": [{"name": "club"}, {"name": "Club"}]}`, Error{Key: "accounts.1.name", Msg: `"Club" is used by another account`}},
+	}
+	for _, tc := range cases {
+		_, err := Parse([]byte(tc.data))
+		var got *Error
+		if !errors.As(err, &got) {
+			t.Fatalf("%s: expected a config error, got %v", tc.data, err)
+		}
+		if got.Key != tc.want.Key || got.Line != tc.want.Line || got.Column != tc.want.Column {
+			t.Fatalf("%s: expected key %q at %d:%d, got %q at %d:%d (%v)", tc.data, tc.want.Key, tc.want.Line, tc.want.Column, got.Key, got.Line, got.Column, err)
+		}
+		if tc.want.Msg != "" && got.Msg != tc.want.Msg {
+			t.Fatalf("%s: expected %q, got %q", tc.data, tc.want.Msg, got.Msg)
+		}
+	}
+}
+
+func TestParseMigratesEachVersion(t *testing.T) {
+	if len(migrations) != CurrentVersion {
+		t.Fatalf("expected a migration for each of %d versions, got %d", CurrentVersion, len(migrations))
+	}
+	cases := []struct {
+		data string
+		want Config
+	}{
+		{`{"username": "jo", "cookie": "a=b"}`, Config{Version: 1, Username: "jo", Cookie: "a=b"}},
+		{`{"version": 0, "username": "jo", "theme": "dark"}`, Config{Version: 1, Username: "jo", Theme: "dark"}},
+		{`{"version": 1, "username": "jo", "posters": "off"}`, Config{Version: 1, Username: "jo", Posters: "off"}},
+	}
+	for _, tc := range cases {
+		cfg, err := Parse([]byte(tc.data))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.data, err)
+		}
+		if cfg.Version != tc.want.Version || cfg.Username != tc.want.Username || cfg.Cookie != tc.want.Cookie || cfg.Theme != tc.want.Theme || cfg.Posters != tc.want.Posters {
+			t.Fatalf("%s: expected %+v, got %+v", tc.data, tc.want, cfg)
+		}
+	}
+}
+
+func TestUnknownKeysSurviveARoundTrip(t *testing.T) {
+	cfg, err := Parse([]byte(`{"version": 1, "username": "jo", "future": {"on": true}, "beta": [1, 2]}`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := strings.Join(cfg.Unknown(), ","); got != "beta,future" {
+		t.Fatalf("expected the unknown keys listed, got %q", got)
+	}
+	if value, err := cfg.Get("future"); err != nil || value != `{"on":true}` {
+		t.Fatalf("expected the unknown key read back, got %q %v", value, err)
+	}
+	if err := cfg.Set("theme", "dark"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	again, err := Parse(data)
+	if err != nil {
+		t.Fatalf("parse again: %v", err)
+	}
+	if again.Theme != "dark" || strings.Join(again.Unknown(), ",") != "beta,future" {
+		t.Fatalf("expected the change and the unknown keys kept, got %s", data)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil || string(raw["beta"]) != "[1,2]" {
+		t.Fatalf("expected beta written back, got %s", data)
+	}
+}
+
+func TestSetRejectsUnknownAndInvalidKeys(t *testing.T) {
+	cases := []struct {
+		store string
+		key   string
+		value string
+		want  string
+	}{
+		{"", "nickname", "x", "unknown key"},
+		{"", "keys.", "x", "unknown key"},
+		{"", "accounts.club.nickname", "x", "unknown key"},
+		{"", "accounts..username", "x", "unknown key"},
+		{"file", "cookie", "a=b", "cookie_store is file"},
+		{"keyring", "accounts.club.cookie", "a=b", "cookie_store is keyring"},
+	}
+	for _, tc := range cases {
+		cfg := Config{CookieStore: tc.store}
+		err := cfg.Set(tc.key, tc.value)
+		var cfgErr *Error
+		if !errors.As(err, &cfgErr) || cfgErr.Key != tc.key || !strings.Contains(cfgErr.Msg, tc.want) {
+			t.Fatalf("set %s: expected %q, got %v", tc.key, tc.want, err)
+		}
+		if cfg.Cookie != "" || len(cfg.Accounts) != 0 {
+			t.Fatalf("set %s: expected nothing written, got %+v", tc.key, cfg)
+		}
+	}
+
+	cfg := Config{}
+	if err := cfg.Set("accounts.club.cookie", "a=b"); err != nil {
+		t.Fatalf("expected cookies kept in the file by default, got %v", err)
+	}
+	if account, ok := cfg.FindAccount("club"); !ok || account.Cookie != "a=b" {
+		t.Fatalf("expected the club account added, got %+v", cfg.Accounts)
+	}
+	if err := cfg.Set("keys.down", "n, down"); err != nil || strings.Join(cfg.Keys["down"], ",") != "n,down" {
+		t.Fatalf("expected the key list split, got %v %v", cfg.Keys, err)
+	}
+}
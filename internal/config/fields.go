@@ -0,0 +1,154 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Field is a setting that can be read and written by key, as
+// `letterboxd config get/set` does.
+type Field struct {
+	Key     string
+	Default string
+	Doc     string
+	value   func(*Config) *string
+}
+
+// Fields are the plain settings, in the order they are listed. Keys and
+// accounts are reached as keys.<action> and accounts.<name>.<field>.
+var Fields = []Field{
+	{Key: "username", Doc: "Letterboxd username of the default account", value: func(c *Config) *string { return &c.Username }},
+	{Key: "cookie", Doc: "Cookie header of the default account", value: func(c *Config) *string { return &c.Cookie }},
+	{Key: "account", Default: DefaultAccount, Doc: "Account to use when -account isn't given", value: func(c *Config) *string { return &c.Account }},
+	{Key: "theme", Default: "auto", Doc: "Theme: auto, dark, light, high-contrast, mono or a user theme", value: func(c *Config) *string { return &c.Theme }},
+	{Key: "posters", Default: "auto", Doc: "Posters: auto, off, blocks, kitty, iterm or sixel", value: func(c *Config) *string { return &c.Posters }},
+	{Key: "cookie_store", Default: "config", Doc: "Where cookies are kept: config, file or keyring", value: func(c *Config) *string { return &c.CookieStore }},
+	{Key: "cookie_cmd", Doc: "Command that prints the cookie", value: func(c *Config) *string { return &c.CookieCmd }},
+	{Key: "log_level", Default: "info", Doc: "Least severe log level written: debug, info, warn or error", value: func(c *Config) *string { return &c.LogLevel }},
+	{Key: "log_format", Default: "text", Doc: "Log format: text or json", value: func(c *Config) *string { return &c.LogFormat }},
+}
+
+func findField(key string) (Field, bool) {
+	for _, field := range Fields {
+		if field.Key == key {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+// Get reads a setting by key, falling back to its default. Keys this
+// version doesn't know are read as they are in the file.
+func (c Config) Get(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if field, ok := findField(key); ok {
+		if value := *field.value(&c); value != "" {
+			return value, nil
+		}
+		return field.Default, nil
+	}
+	if action, ok := strings.CutPrefix(key, "keys."); ok {
+		return strings.Join(c.Keys[action], ","), nil
+	}
+	if name, field, ok := accountKey(key); ok {
+		account, found := c.FindAccount(name)
+		if !found {
+			return "", &Error{Key: key, Msg: fmt.Sprintf("no account named %q", name)}
+		}
+		if field == "username" {
+			return account.Username, nil
+		}
+		return account.Cookie, nil
+	}
+	if value, ok := c.extra[key]; ok {
+		return string(value), nil
+	}
+	return "", unknownKey(key)
+}
+
+// Set writes a setting by key; an empty value goes back to the default.
+// Setting an account's field adds the account if it is new. Cookies are
+// refused unless cookie_store keeps them in this file.
+func (c *Config) Set(key, value string) error {
+	key = strings.TrimSpace(key)
+	value = strings.TrimSpace(value)
+	if _, ok := CookieAccount(key); ok && value != "" && !c.CookiesInFile() {
+		return &Error{Key: key, Msg: fmt.Sprintf("cookie_store is %s, so cookies go there, not in this file", c.CookieStore)}
+	}
+	if field, ok := findField(key); ok {
+		*field.value(c) = value
+		return c.Validate()
+	}
+	if action, ok := strings.CutPrefix(key, "keys."); ok && action != "" {
+		var list []string
+		for _, k := range strings.Split(value, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				list = append(list, k)
+			}
+		}
+		if len(list) == 0 {
+			delete(c.Keys, action)
+			return nil
+		}
+		if c.Keys == nil {
+			c.Keys = map[string][]string{}
+		}
+		c.Keys[action] = list
+		return nil
+	}
+	if name, field, ok := accountKey(key); ok {
+		account, _ := c.FindAccount(name)
+		if field == "username" {
+			account.Username = value
+		} else {
+			account.Cookie = value
+		}
+		c.PutAccount(account)
+		return c.Validate()
+	}
+	return unknownKey(key)
+}
+
+// CookieAccount reports whether key is an account's cookie, and whose.
+func CookieAccount(key string) (string, bool) {
+	key = strings.TrimSpace(key)
+	if key == "cookie" {
+		return DefaultAccount, true
+	}
+	if name, field, ok := accountKey(key); ok && field == "cookie" {
+		return name, true
+	}
+	return "", false
+}
+
+// CookiesInFile reports whether cookie_store keeps cookies in the config
+// file itself.
+func (c Config) CookiesInFile() bool {
+	store := strings.ToLower(strings.TrimSpace(c.CookieStore))
+	return store == "" || store == "config"
+}
+
+// accountKey splits accounts.<name>.username or accounts.<name>.cookie.
+func accountKey(key string) (name, field string, ok bool) {
+	rest, ok := strings.CutPrefix(key, "accounts.")
+	if !ok {
+		return "", "", false
+	}
+	i := strings.LastIndex(rest, ".")
+	if i <= 0 {
+		return "", "", false
+	}
+	name, field = rest[:i], rest[i+1:]
+	return name, field, field == "username" || field == "cookie"
+}
+
+func unknownKey(key string) error {
+	known := make([]string, 0, len(Fields)+2)
+	for _, field := range Fields {
+		known = append(known, field.Key)
+	}
+	sort.Strings(known)
+	known = append(known, "keys.<action>", "accounts.<name>.username|cookie")
+	return &Error{Key: key, Msg: "unknown key (have " + strings.Join(known, ", ") + ")"}
+}
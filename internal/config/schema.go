@@ -0,0 +1,222 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// CurrentVersion is the schema this build reads and writes. Files from
+// before the version key are version 0.
+const CurrentVersion = 1
+
+// migrations[n] upgrades a version n file to version n+1. They work on the
+// raw keys, so a renamed or reshaped key can still be read.
+var migrations = []func(map[string]json.RawMessage) error{
+	// Version 0 has the same keys; it only gains the version.
+	func(map[string]json.RawMessage) error { return nil },
+}
+
+// Error is a problem with the config file, naming the key or, for bad
+// JSON, the line it is on.
+type Error struct {
+	Path   string
+	Key    string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	if e.Path != "" {
+		b.WriteString(e.Path)
+		if e.Line > 0 {
+			fmt.Fprintf(&b, ":%d:%d", e.Line, e.Column)
+		}
+		b.WriteString(": ")
+	} else if e.Line > 0 {
+		fmt.Fprintf(&b, "line %d, column %d: ", e.Line, e.Column)
+	}
+	if e.Key != "" {
+		b.WriteString(e.Key + ": ")
+	}
+	b.WriteString(e.Msg)
+	return b.String()
+}
+
+// Parse reads a config file, migrating it to CurrentVersion and checking
+// it. Keys it doesn't know are kept for Save.
+func Parse(data []byte) (Config, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Config{}, jsonError(data, err)
+	}
+	if raw == nil {
+		return Config{}, &Error{Msg: "want a JSON object"}
+	}
+	version := 0
+	if value, ok := raw["version"]; ok {
+		if err := json.Unmarshal(value, &version); err != nil || version < 0 {
+			return Config{}, &Error{Key: "version", Msg: fmt.Sprintf("want a schema number, got %s", value)}
+		}
+	}
+	if version > CurrentVersion {
+		return Config{}, &Error{Key: "version", Msg: fmt.Sprintf("written by a newer letterboxd (schema %d, this one reads up to %d); upgrade to use it", version, CurrentVersion)}
+	}
+	for ; version < CurrentVersion; version++ {
+		if err := migrations[version](raw); err != nil {
+			return Config{}, &Error{Msg: fmt.Sprintf("migrating from schema %d: %v", version, err)}
+		}
+	}
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return Config{}, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(migrated, &cfg); err != nil {
+		return Config{}, jsonError(migrated, err)
+	}
+	cfg.Version = CurrentVersion
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// jsonError points a decoding error at its line or key.
+func jsonError(data []byte, err error) error {
+	var syntax *json.SyntaxError
+	if errors.As(err, &syntax) {
+		line, column := position(data, syntax.Offset)
+		return &Error{Line: line, Column: column, Msg: syntax.Error()}
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		key := typeErr.Field
+		if key == "" {
+			key = "(top level)"
+		}
+		return &Error{Key: key, Msg: fmt.Sprintf("want %s, got %s", typeName(typeErr.Type), typeErr.Value)}
+	}
+	return &Error{Msg: err.Error()}
+}
+
+func position(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	// Offset is just past the byte that failed.
+	column := len(before) - bytes.LastIndexByte(before, '\n') - 1
+	return line, column
+}
+
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Int:
+		return "a number"
+	case reflect.Slice:
+		return "a list"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	}
+	return t.String()
+}
+
+// Validate checks what the config package owns. Settings read by other
+// packages, like keys and themes, are checked where they are used.
+func (c Config) Validate() error {
+	seen := map[string]bool{}
+	for i, account := range c.Accounts {
+		key := fmt.Sprintf("accounts.%d", i)
+		name := strings.ToLower(strings.TrimSpace(account.Name))
+		switch {
+		case name == "":
+			return &Error{Key: key + ".name", Msg: "every account needs a name"}
+		case name == DefaultAccount:
+			return &Error{Key: key + ".name", Msg: fmt.Sprintf("%q is the top-level username and cookie", DefaultAccount)}
+		case seen[name]:
+			return &Error{Key: key + ".name", Msg: fmt.Sprintf("%q is used by another account", account.Name)}
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// plainConfig is Config without its JSON methods.
+type plainConfig Config
+
+var knownKeys = func() map[string]bool {
+	known := map[string]bool{}
+	t := reflect.TypeOf(plainConfig{})
+	for i := 0; i < t.NumField(); i++ {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ","); name != "" && name != "-" {
+			known[name] = true
+		}
+	}
+	return known
+}()
+
+func (c *Config) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*plainConfig)(c)); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	c.extra = nil
+	for key, value := range raw {
+		if knownKeys[key] {
+			continue
+		}
+		if c.extra == nil {
+			c.extra = map[string]json.RawMessage{}
+		}
+		c.extra[key] = value
+	}
+	return nil
+}
+
+// MarshalJSON writes the known keys in their usual order, then the
+// unknown ones kept from the file.
+func (c Config) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(plainConfig(c))
+	if err != nil || len(c.extra) == 0 {
+		return data, err
+	}
+	keys := make([]string, 0, len(c.extra))
+	for key := range c.extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b bytes.Buffer
+	b.Write(data[:len(data)-1])
+	for _, key := range keys {
+		name, _ := json.Marshal(key)
+		b.WriteByte(',')
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(c.extra[key])
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// Unknown lists the keys kept from the file that this version doesn't use.
+func (c Config) Unknown() []string {
+	keys := make([]string, 0, len(c.extra))
+	for key := range c.extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
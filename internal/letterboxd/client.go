@@ -2,7 +2,9 @@ package letterboxd
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
@@ -18,11 +20,20 @@ type Client struct {
 	HTTP   *http.Client
 	Cookie string
 	Debug  bool
+	// OnEvent, when set, is called with every request and parse warning,
+	// from whichever goroutine made the request.
+	OnEvent func(Event)
 
 	fallbackHTTP   *http.Client
 	forceHTTP2HTTP *http.Client
 }
 
+const maxListPages = 200
+
+// ErrListTruncated comes back with what was read when a list runs past
+// maxListPages, so callers can use the partial list and say so.
+var ErrListTruncated = fmt.Errorf("the list is longer than %d pages; only those were read", maxListPages)
+
 const defaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
 
 func NewClient(httpClient *http.Client, cookie string) *Client {
@@ -52,12 +63,13 @@ func (c *Client) Diary(username string, page int, sort DiarySort) ([]DiaryEntry,
 		return nil, c.wrapDebug(err)
 	}
 	entries, err := parseDiary(doc)
+	c.checkDiaryRows(url, doc, entries)
 	return entries, c.wrapDebug(err)
 }
 
-func (c *Client) Watchlist(username string, page int, sort WatchlistSort) ([]WatchlistItem, error) {
-	url := watchlistURL(username, page, sort)
-	doc, err := c.fetchDocument(url)
+func (c *Client) Watchlist(username string, page int, filter WatchlistFilter) ([]WatchlistItem, error) {
+	url := watchlistURL(username, page, filter)
+	doc, err := c.fetchDocumentWithHeaders(url, filmFilterHeaders(c.Cookie, filter.Release))
 	if err != nil {
 		return nil, c.wrapDebug(err)
 	}
@@ -65,6 +77,107 @@ func (c *Client) Watchlist(username string, page int, sort WatchlistSort) ([]Wat
 	return items, c.wrapDebug(err)
 }
 
+func (c *Client) AllDiary(username string) ([]DiaryEntry, error) {
+	return c.collectDiary(map[string]struct{}{}, func(page int) string {
+		return diaryURL(username, page, DiarySortDefault)
+	})
+}
+
+// DiaryRange returns diary entries logged between from and to (inclusive,
+// by day), newest first, fetching only the months in that span.
+func (c *Client) DiaryRange(username string, from, to time.Time) ([]DiaryEntry, error) {
+	from, to = diaryDay(from), diaryDay(to)
+	if to.Before(from) {
+		from, to = to, from
+	}
+	var all []DiaryEntry
+	seen := map[string]struct{}{}
+	month := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for !month.Before(time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)) {
+		year, mon := month.Year(), month.Month()
+		entries, err := c.collectDiary(seen, func(page int) string {
+			return diaryMonthURL(username, year, mon, page)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.Date.Before(from) || entry.Date.After(to) {
+				continue
+			}
+			all = append(all, entry)
+		}
+		month = month.AddDate(0, -1, 0)
+	}
+	return all, nil
+}
+
+func (c *Client) collectDiary(seen map[string]struct{}, pageURL func(page int) string) ([]DiaryEntry, error) {
+	var all []DiaryEntry
+	for page := 1; ; page++ {
+		if page > maxListPages {
+			return all, ErrListTruncated
+		}
+		url := pageURL(page)
+		doc, err := c.fetchDocument(url)
+		if err != nil {
+			return nil, c.wrapDebug(err)
+		}
+		entries, err := parseDiary(doc)
+		if err != nil {
+			return nil, c.wrapDebug(err)
+		}
+		c.checkDiaryRows(url, doc, entries)
+		added := 0
+		for _, entry := range entries {
+			key := NormalizeFilmURL(entry.FilmURL) + "|" + entry.Date.Format(time.DateOnly)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			all = append(all, entry)
+			added++
+		}
+		if added == 0 {
+			break
+		}
+	}
+	return all, nil
+}
+
+func diaryDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func (c *Client) AllWatchlist(username string, filter WatchlistFilter) ([]WatchlistItem, error) {
+	var all []WatchlistItem
+	seen := map[string]struct{}{}
+	for page := 1; ; page++ {
+		if page > maxListPages {
+			return all, ErrListTruncated
+		}
+		items, err := c.Watchlist(username, page, filter)
+		if err != nil {
+			return nil, err
+		}
+		added := 0
+		for _, item := range items {
+			key := NormalizeFilmURL(item.FilmURL)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			all = append(all, item)
+			added++
+		}
+		if added == 0 {
+			break
+		}
+	}
+	return all, nil
+}
+
 func (c *Client) Film(filmURL, username string) (Film, error) {
 	doc, err := c.fetchDocument(filmURL)
 	if err != nil {
@@ -74,8 +187,13 @@ func (c *Client) Film(filmURL, username string) (Film, error) {
 	if err != nil {
 		return film, c.wrapDebug(err)
 	}
+	if film.Title == "" {
+		c.warn(filmURL, "no title on the film page")
+	}
 	if film.Slug != "" {
-		if meta, err := c.filmJSON(film.Slug); err == nil {
+		if meta, err := c.filmJSON(film.Slug); err != nil {
+			c.warn(filmURL, "film details unavailable: %v", err)
+		} else {
 			if film.WatchlistID == "" && meta.LID != "" {
 				film.WatchlistID = meta.LID
 			}
@@ -94,6 +212,9 @@ func (c *Client) Film(filmURL, username string) (Film, error) {
 			}
 		}
 	}
+	if film.ViewingUID == "" {
+		c.warn(filmURL, "no film ID; logging and watchlist changes won't work for this film")
+	}
 	if username != "" {
 		userURL := userFilmURL(username, filmURL)
 		if userURL != "" {
@@ -160,7 +281,9 @@ func applyDefaultHeaders(req *http.Request) {
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
 }
 
-func (c *Client) fetchDocumentStatus(url string, headers map[string]string) (*goquery.Document, int, error) {
+func (c *Client) fetchDocumentStatus(url string, headers map[string]string) (doc *goquery.Document, status int, err error) {
+	tr := c.newTrace(http.MethodGet, url)
+	defer func() { err = tr.finish(err) }()
 	const maxAttempts = 2
 	useFallback := false
 	forceHTTP2 := false
@@ -185,7 +308,7 @@ func (c *Client) fetchDocumentStatus(url string, headers map[string]string) (*go
 		} else if useFallback {
 			client = c.fallbackClient()
 		}
-		resp, err := client.Do(req)
+		resp, err := tr.do(c, client, req)
 		if err != nil {
 			if !forceHTTP2 && isHTTP2PrefaceError(err) {
 				forceHTTP2 = true
@@ -343,6 +466,19 @@ func (c *Client) cloudflareError(req *http.Request, resp *http.Response, body st
 	return fmt.Errorf("%s\n%w", hint, detail)
 }
 
+// IsTransient reports whether err came from a Cloudflare challenge or a
+// network failure, so the same request may succeed later.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return strings.Contains(err.Error(), "Cloudflare challenge detected")
+}
+
 func isHTTP2PrefaceError(err error) bool {
 	if err == nil {
 		return false
@@ -1,8 +1,12 @@
 package letterboxd
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewClientDefaults(t *testing.T) {
@@ -27,6 +31,57 @@ func TestFetchDocumentStatusError(t *testing.T) {
 	}
 }
 
+func TestDebugErrorsCarryRequestIDAndHideSecrets(t *testing.T) {
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		return newHTTPResponse(http.StatusTeapot, `<input name="__csrf" value="csrf123">`, nil), nil
+	})
+	client.Debug = true
+	_, err := client.fetchDocumentWithHeaders(BaseURL+"/nope", map[string]string{"X-CSRF-Token": "csrf123"})
+	if err == nil || RequestID(err) == "" {
+		t.Fatalf("expected an error with a request ID, got %v", err)
+	}
+	if strings.Contains(err.Error(), "csrf123") || strings.Contains(err.Error(), "testcookie") {
+		t.Fatalf("expected the cookie and token redacted, got %s", err)
+	}
+	_, other := client.fetchDocument(BaseURL + "/nope")
+	if RequestID(other) == RequestID(err) {
+		t.Fatalf("expected each request to get its own ID")
+	}
+}
+
+func TestClientPublishesEvents(t *testing.T) {
+	calls := 0
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return newHTTPResponse(http.StatusForbidden, "", nil), nil
+		}
+		return newHTTPResponse(http.StatusOK, `<table><tr class="diary-entry-row"><td></td></tr></table>`, nil), nil
+	})
+	var events []Event
+	client.OnEvent = func(ev Event) { events = append(events, ev) }
+	if _, err := client.Diary("jane", 1, DiarySort("")); err != nil {
+		t.Fatalf("diary: %v", err)
+	}
+	client.CacheHit("https://a.ltrbxd.com/poster.jpg")
+	if len(events) != 3 {
+		t.Fatalf("expected a request, a warning and a cache hit, got %+v", events)
+	}
+	req := events[0]
+	if req.Kind != EventRequest || req.Status != http.StatusOK || req.Retries != 1 || req.ID == "" {
+		t.Fatalf("unexpected request event %+v", req)
+	}
+	if got := req.RequestHeader.Get("Cookie"); got != "<redacted>" {
+		t.Fatalf("expected the cookie redacted, got %q", got)
+	}
+	if events[1].Kind != EventWarning || !strings.Contains(events[1].Message, "1 of 1 diary rows") {
+		t.Fatalf("unexpected warning %+v", events[1])
+	}
+	if !events[2].CacheHit {
+		t.Fatalf("expected a cache hit, got %+v", events[2])
+	}
+}
+
 func TestFetchDocumentStatusRetriesForbidden(t *testing.T) {
 	calls := 0
 	client := newTestClient(func(req *http.Request) (*http.Response, error) {
@@ -93,7 +148,7 @@ func TestClientMethods(t *testing.T) {
 	if _, err := client.Diary("jane", 1, DiarySortDefault); err != nil {
 		t.Fatalf("Diary error: %v", err)
 	}
-	if _, err := client.Watchlist("jane", 1, WatchlistSortDefault); err != nil {
+	if _, err := client.Watchlist("jane", 1, WatchlistFilter{}); err != nil {
 		t.Fatalf("Watchlist error: %v", err)
 	}
 	if _, err := client.Activity("jane", ""); err != nil {
@@ -119,3 +174,61 @@ func TestClientMethods(t *testing.T) {
 		t.Fatalf("FriendReviews error: %v", err)
 	}
 }
+
+func TestAllDiaryStopsOnRepeatedPage(t *testing.T) {
+	calls := 0
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return newHTTPResponse(http.StatusOK, `<table><tr class="diary-entry-row"><td class="col-monthdate"><span class="month">Jan</span><span class="year">2024</span></td><td class="col-daydate"><span class="daydate">1</span></td><td><h2 class="name"><a href="/film/inception/">Inception</a></h2></td></tr></table>`, nil), nil
+	})
+	entries, err := client.AllDiary("jane")
+	if err != nil {
+		t.Fatalf("AllDiary error: %v", err)
+	}
+	if len(entries) != 1 || calls != 2 {
+		t.Fatalf("unexpected result: entries=%d calls=%d", len(entries), calls)
+	}
+}
+
+func TestAllWatchlistReportsTruncation(t *testing.T) {
+	calls := 0
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return newHTTPResponse(http.StatusOK, fmt.Sprintf(`<div class="js-watchlist-main-content"><div class="react-component" data-item-name="F%d (2000)" data-item-link="/film/f%d/"></div></div>`, calls, calls), nil), nil
+	})
+	items, err := client.AllWatchlist("jane", WatchlistFilter{})
+	if !errors.Is(err, ErrListTruncated) || len(items) != maxListPages || calls != maxListPages {
+		t.Fatalf("expected %d items with ErrListTruncated, got %d items after %d calls: %v", maxListPages, len(items), calls, err)
+	}
+}
+
+func TestDiaryRangeFetchesOnlySpanMonths(t *testing.T) {
+	row := func(month, day, slug string) string {
+		return `<tr class="diary-entry-row"><td class="col-monthdate"><span class="month">` + month + `</span><span class="year">2024</span></td><td class="col-daydate"><span class="daydate">` + day + `</span></td><td><h2 class="name"><a href="/film/` + slug + `/">` + slug + `</a></h2></td></tr>`
+	}
+	var paths []string
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		paths = append(paths, req.URL.Path)
+		switch req.URL.Path {
+		case "/jane/diary/for/2024/03/":
+			return newHTTPResponse(http.StatusOK, "<table>"+row("Mar", "20", "late")+row("Mar", "2", "heat")+"</table>", nil), nil
+		case "/jane/diary/for/2024/02/":
+			return newHTTPResponse(http.StatusOK, "<table>"+row("Feb", "28", "alien")+row("Feb", "1", "early")+"</table>", nil), nil
+		}
+		return newHTTPResponse(http.StatusOK, "<table></table>", nil), nil
+	})
+	from := time.Date(2024, time.February, 15, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)
+	entries, err := client.DiaryRange("jane", to, from)
+	if err != nil {
+		t.Fatalf("DiaryRange error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Title != "heat" || entries[1].Title != "alien" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	for _, path := range paths {
+		if !strings.HasPrefix(path, "/jane/diary/for/2024/02/") && !strings.HasPrefix(path, "/jane/diary/for/2024/03/") {
+			t.Fatalf("unexpected request outside span: %s", path)
+		}
+	}
+}
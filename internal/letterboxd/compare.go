@@ -0,0 +1,177 @@
+package letterboxd
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+)
+
+type SharedFilm struct {
+	Title   string
+	Year    string
+	FilmURL string
+	RatingA string
+	RatingB string
+}
+
+type Comparison struct {
+	UserA         string
+	UserB         string
+	WatchedA      int
+	WatchedB      int
+	RatedA        int
+	RatedB        int
+	Shared        []SharedFilm
+	BothRated     int
+	Correlation   float64
+	CorrelationOK bool
+	Disagreements []SharedFilm
+	// LovedByBForA holds films B loved that are on A's watchlist, and vice versa.
+	LovedByBForA []UserFilm
+	LovedByAForB []UserFilm
+	// Truncated is set when a list was too long to read in full.
+	Truncated bool
+}
+
+func (c *Client) Compare(userA, userB string) (Comparison, error) {
+	truncated := false
+	partial := func(err error) error {
+		if errors.Is(err, ErrListTruncated) {
+			truncated = true
+			return nil
+		}
+		return err
+	}
+	filmsA, err := c.AllUserFilms(userA)
+	if err = partial(err); err != nil {
+		return Comparison{}, err
+	}
+	filmsB, err := c.AllUserFilms(userB)
+	if err = partial(err); err != nil {
+		return Comparison{}, err
+	}
+	watchA, err := c.AllWatchlist(userA, WatchlistFilter{})
+	if err = partial(err); err != nil {
+		return Comparison{}, err
+	}
+	watchB, err := c.AllWatchlist(userB, WatchlistFilter{})
+	if err = partial(err); err != nil {
+		return Comparison{}, err
+	}
+	cmp := CompareFilms(userA, filmsA, watchA, userB, filmsB, watchB)
+	cmp.Truncated = truncated
+	return cmp, nil
+}
+
+func CompareFilms(userA string, filmsA []UserFilm, watchA []WatchlistItem, userB string, filmsB []UserFilm, watchB []WatchlistItem) Comparison {
+	cmp := Comparison{
+		UserA:    userA,
+		UserB:    userB,
+		WatchedA: len(filmsA),
+		WatchedB: len(filmsB),
+		RatedA:   countRated(filmsA),
+		RatedB:   countRated(filmsB),
+	}
+	byURL := make(map[string]UserFilm, len(filmsB))
+	for _, film := range filmsB {
+		byURL[NormalizeFilmURL(film.FilmURL)] = film
+	}
+	var xs, ys []float64
+	for _, a := range filmsA {
+		b, ok := byURL[NormalizeFilmURL(a.FilmURL)]
+		if !ok {
+			continue
+		}
+		shared := SharedFilm{Title: a.Title, Year: a.Year, FilmURL: a.FilmURL, RatingA: a.Rating, RatingB: b.Rating}
+		cmp.Shared = append(cmp.Shared, shared)
+		x, y := ratingValue(a.Rating), ratingValue(b.Rating)
+		if x == 0 || y == 0 {
+			continue
+		}
+		xs = append(xs, x)
+		ys = append(ys, y)
+		if x != y {
+			cmp.Disagreements = append(cmp.Disagreements, shared)
+		}
+	}
+	cmp.BothRated = len(xs)
+	cmp.Correlation, cmp.CorrelationOK = pearson(xs, ys)
+	sort.SliceStable(cmp.Disagreements, func(i, j int) bool {
+		return ratingGap(cmp.Disagreements[i]) > ratingGap(cmp.Disagreements[j])
+	})
+	cmp.LovedByBForA = lovedOnWatchlist(filmsB, watchA)
+	cmp.LovedByAForB = lovedOnWatchlist(filmsA, watchB)
+	return cmp
+}
+
+func countRated(films []UserFilm) int {
+	count := 0
+	for _, film := range films {
+		if ratingValue(film.Rating) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+func lovedOnWatchlist(films []UserFilm, watchlist []WatchlistItem) []UserFilm {
+	wanted := make(map[string]struct{}, len(watchlist))
+	for _, item := range watchlist {
+		wanted[NormalizeFilmURL(item.FilmURL)] = struct{}{}
+	}
+	var loved []UserFilm
+	for _, film := range films {
+		if !film.Liked && ratingValue(film.Rating) < 4.5 {
+			continue
+		}
+		if _, ok := wanted[NormalizeFilmURL(film.FilmURL)]; ok {
+			loved = append(loved, film)
+		}
+	}
+	sort.SliceStable(loved, func(i, j int) bool {
+		return ratingValue(loved[i].Rating) > ratingValue(loved[j].Rating)
+	})
+	return loved
+}
+
+func ratingGap(film SharedFilm) float64 {
+	return math.Abs(ratingValue(film.RatingA) - ratingValue(film.RatingB))
+}
+
+func ratingValue(rating string) float64 {
+	var value float64
+	for _, r := range strings.TrimSpace(rating) {
+		switch r {
+		case '★':
+			value += 1
+		case '½':
+			value += 0.5
+		}
+	}
+	return value
+}
+
+func pearson(xs, ys []float64) (float64, bool) {
+	n := float64(len(xs))
+	if len(xs) < 2 || len(xs) != len(ys) {
+		return 0, false
+	}
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(varX*varY), true
+}
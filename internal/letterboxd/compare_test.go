@@ -0,0 +1,68 @@
+package letterboxd
+
+import (
+	"math"
+	"net/http"
+	"testing"
+)
+
+func TestCompareFilms(t *testing.T) {
+	filmsA := []UserFilm{
+		{Title: "Alien", FilmURL: BaseURL + "/film/alien/", Rating: "★★★★★"},
+		{Title: "Heat", FilmURL: BaseURL + "/film/heat/", Rating: "★★★"},
+		{Title: "Cats", FilmURL: BaseURL + "/film/cats/", Rating: "½"},
+		{Title: "Jaws", FilmURL: BaseURL + "/film/jaws/", Liked: true},
+	}
+	filmsB := []UserFilm{
+		{Title: "Alien", FilmURL: "/film/alien/", Rating: "★★★★"},
+		{Title: "Heat", FilmURL: "/film/heat/", Rating: "★★★"},
+		{Title: "Cats", FilmURL: "/film/cats/", Rating: "★★★★½"},
+		{Title: "Ran", FilmURL: "/film/ran/", Rating: "★★★★★"},
+	}
+	watchA := []WatchlistItem{{Title: "Ran", FilmURL: BaseURL + "/film/ran/"}}
+	watchB := []WatchlistItem{{Title: "Jaws", FilmURL: BaseURL + "/film/jaws/"}}
+
+	cmp := CompareFilms("jane", filmsA, watchA, "joe", filmsB, watchB)
+	if len(cmp.Shared) != 3 || cmp.BothRated != 3 {
+		t.Fatalf("unexpected overlap: shared=%d rated=%d", len(cmp.Shared), cmp.BothRated)
+	}
+	if cmp.RatedA != 3 || cmp.WatchedB != 4 {
+		t.Fatalf("unexpected counts: %+v", cmp)
+	}
+	if len(cmp.Disagreements) != 2 || cmp.Disagreements[0].Title != "Cats" {
+		t.Fatalf("unexpected disagreements: %+v", cmp.Disagreements)
+	}
+	if !cmp.CorrelationOK || cmp.Correlation >= 0 {
+		t.Fatalf("expected negative correlation, got %v (ok=%v)", cmp.Correlation, cmp.CorrelationOK)
+	}
+	if len(cmp.LovedByBForA) != 1 || cmp.LovedByBForA[0].Title != "Ran" {
+		t.Fatalf("unexpected recs for A: %+v", cmp.LovedByBForA)
+	}
+	if len(cmp.LovedByAForB) != 1 || cmp.LovedByAForB[0].Title != "Jaws" {
+		t.Fatalf("unexpected recs for B: %+v", cmp.LovedByAForB)
+	}
+}
+
+func TestPearson(t *testing.T) {
+	if r, ok := pearson([]float64{1, 2, 3}, []float64{2, 4, 6}); !ok || math.Abs(r-1) > 1e-9 {
+		t.Fatalf("expected perfect correlation, got %v", r)
+	}
+	if _, ok := pearson([]float64{3, 3}, []float64{1, 2}); ok {
+		t.Fatalf("expected no correlation for constant ratings")
+	}
+}
+
+func TestAllUserFilmsStopsOnRepeatedPage(t *testing.T) {
+	calls := 0
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return newHTTPResponse(http.StatusOK, `<li class="poster-container"><div class="react-component" data-item-name="Alien (1979)" data-item-link="/film/alien/"></div></li>`, nil), nil
+	})
+	films, err := client.AllUserFilms("jane")
+	if err != nil {
+		t.Fatalf("AllUserFilms error: %v", err)
+	}
+	if len(films) != 1 || calls != 2 {
+		t.Fatalf("unexpected result: films=%d calls=%d", len(films), calls)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"runtime/debug"
 	"sort"
 	"strings"
+
+	"github.com/solean/letterboxd-tui/internal/logging"
 )
 
 const maxDebugBody = 8192
@@ -52,7 +54,7 @@ func (c *Client) httpStatusErrorWithBody(req *http.Request, resp *http.Response,
 	}
 	lines := []string{fmt.Sprintf("unexpected status %d for %s", resp.StatusCode, req.URL.String())}
 	if body != "" {
-		lines = append(lines, "response body: "+body)
+		lines = append(lines, "response body: "+logging.Redact(body))
 	}
 	if headerLine := formatHeaders(resp.Header); headerLine != "" {
 		lines = append(lines, "response headers: "+headerLine)
@@ -104,9 +106,8 @@ func formatHeaders(headers http.Header) string {
 }
 
 func formatHeaderValue(key string, values []string) string {
-	switch strings.ToLower(key) {
-	case "cookie", "set-cookie", "authorization":
+	if logging.SensitiveHeader(key) {
 		return "<redacted>"
 	}
-	return strings.Join(values, ",")
+	return logging.Redact(strings.Join(values, ","))
 }
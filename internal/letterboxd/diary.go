@@ -3,10 +3,13 @@ package letterboxd
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
+const diaryDateLayout = "Jan 2 2006"
+
 func parseDiary(doc *goquery.Document) ([]DiaryEntry, error) {
 	var entries []DiaryEntry
 	currentMonth := ""
@@ -33,9 +36,9 @@ func parseDiary(doc *goquery.Document) ([]DiaryEntry, error) {
 		rewatch := strings.Contains(row.Find(".js-td-rewatch").AttrOr("class", ""), "icon-status-on")
 		review := row.Find(".js-td-review a").Length() > 0
 
-		date := ""
+		var date time.Time
 		if day != "" && currentMonth != "" && currentYear != "" {
-			date = fmt.Sprintf("%s %s %s", currentMonth, day, currentYear)
+			date, _ = time.Parse(diaryDateLayout, fmt.Sprintf("%s %s %s", currentMonth, day, currentYear))
 		}
 		if title != "" {
 			entries = append(entries, DiaryEntry{
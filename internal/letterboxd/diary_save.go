@@ -11,6 +11,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/solean/letterboxd-tui/internal/logging"
 )
 
 type DiaryEntryRequest struct {
@@ -28,7 +30,7 @@ type DiaryEntryRequest struct {
 	JSONResponse     bool
 }
 
-func (c *Client) SaveDiaryEntry(req DiaryEntryRequest) error {
+func (c *Client) SaveDiaryEntry(req DiaryEntryRequest) (err error) {
 	if req.ViewingUID == "" {
 		return c.wrapDebug(errors.New("missing viewing UID"))
 	}
@@ -74,6 +76,8 @@ func (c *Client) SaveDiaryEntry(req DiaryEntryRequest) error {
 	const maxAttempts = 1
 	useFallback := false
 	reqURL := fmt.Sprintf("%s/s/save-diary-entry", BaseURL)
+	tr := c.newTrace(http.MethodPost, reqURL)
+	defer func() { err = tr.finish(err) }()
 	encoded := values.Encode()
 	for attempt := 0; attempt <= maxAttempts; attempt++ {
 		httpReq, err := http.NewRequest(http.MethodPost, reqURL, strings.NewReader(encoded))
@@ -96,7 +100,7 @@ func (c *Client) SaveDiaryEntry(req DiaryEntryRequest) error {
 		if useFallback {
 			client = c.fallbackClient()
 		}
-		resp, err := client.Do(httpReq)
+		resp, err := tr.do(c, client, httpReq)
 		if err != nil {
 			return c.wrapDebug(err)
 		}
@@ -117,7 +121,7 @@ func (c *Client) SaveDiaryEntry(req DiaryEntryRequest) error {
 		}
 		snippet := ""
 		if data, _ := io.ReadAll(io.LimitReader(resp.Body, 512)); len(data) > 0 {
-			snippet = strings.TrimSpace(string(data))
+			snippet = logging.Redact(strings.TrimSpace(string(data)))
 		}
 		resp.Body.Close()
 		isChallenge := isCloudflareChallenge(resp.StatusCode, snippet)
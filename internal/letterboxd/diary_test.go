@@ -1,6 +1,9 @@
 package letterboxd
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestParseDiary(t *testing.T) {
 	html := `
@@ -26,13 +29,13 @@ func TestParseDiary(t *testing.T) {
 	if len(entries) != 2 {
 		t.Fatalf("expected 2 entries, got %d", len(entries))
 	}
-	if entries[0].Date != "Jan 5 2024" || entries[0].FilmURL != BaseURL+"/film/inception/" {
+	if !entries[0].Date.Equal(time.Date(2024, time.January, 5, 0, 0, 0, 0, time.UTC)) || entries[0].FilmURL != BaseURL+"/film/inception/" {
 		t.Fatalf("unexpected first entry: %+v", entries[0])
 	}
 	if !entries[0].Rewatch || !entries[0].Review {
 		t.Fatalf("expected rewatch/review true: %+v", entries[0])
 	}
-	if entries[1].Date != "Jan 6 2024" || entries[1].Title != "Memento" {
+	if !entries[1].Date.Equal(time.Date(2024, time.January, 6, 0, 0, 0, 0, time.UTC)) || entries[1].Title != "Memento" {
 		t.Fatalf("unexpected second entry: %+v", entries[1])
 	}
 }
@@ -0,0 +1,94 @@
+package letterboxd
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/solean/letterboxd-tui/internal/logging"
+)
+
+// EventKind says what an Event reports.
+type EventKind int
+
+const (
+	// EventRequest is a finished call to Letterboxd, or one answered from a
+	// cache.
+	EventRequest EventKind = iota
+	// EventWarning is a page that didn't parse the way it should have.
+	EventWarning
+	// EventError is an error the app ran into outside of a request, such as
+	// failing to save; Message says what it was doing.
+	EventError
+)
+
+// Event is published to Client.OnEvent for each request and parse warning,
+// and made by the app for its own errors.
+// Headers are copies with cookies and tokens redacted.
+type Event struct {
+	Kind           EventKind
+	ID             string
+	Time           time.Time
+	Method         string
+	URL            string
+	Status         int
+	Duration       time.Duration
+	Retries        int
+	Fallback       bool
+	CacheHit       bool
+	Err            error
+	Message        string
+	RequestHeader  http.Header
+	ResponseHeader http.Header
+}
+
+func (c *Client) publish(ev Event) {
+	if c.OnEvent == nil {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	c.OnEvent(ev)
+}
+
+// CacheHit records a request answered from a local cache instead of
+// Letterboxd, so it shows among the client's events.
+func (c *Client) CacheHit(url string) {
+	c.publish(Event{Kind: EventRequest, Method: http.MethodGet, URL: url, CacheHit: true})
+}
+
+// warn reports a page that parsed without something it should have had.
+func (c *Client) warn(url, format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	logging.Logger().Warn("parse warning", slog.String("url", url), slog.String("warning", message))
+	c.publish(Event{Kind: EventWarning, URL: url, Message: message})
+}
+
+// checkDiaryRows warns when diary rows were dropped for want of a title,
+// which means the page's markup changed.
+func (c *Client) checkDiaryRows(url string, doc *goquery.Document, entries []DiaryEntry) {
+	if rows := doc.Find("tr.diary-entry-row").Length(); rows > len(entries) {
+		c.warn(url, "%d of %d diary rows had no film title", rows-len(entries), rows)
+	}
+}
+
+// redactHeader copies h with secret values replaced.
+func redactHeader(h http.Header) http.Header {
+	if h == nil {
+		return nil
+	}
+	clean := make(http.Header, len(h))
+	for key, values := range h {
+		if logging.SensitiveHeader(key) {
+			clean[key] = []string{"<redacted>"}
+			continue
+		}
+		for _, value := range values {
+			clean[key] = append(clean[key], logging.Redact(value))
+		}
+	}
+	return clean
+}
@@ -1,6 +1,8 @@
 package letterboxd
 
 import (
+	"encoding/json"
+	"strconv"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -30,6 +32,7 @@ func parseFilm(doc *goquery.Document, url string) (Film, error) {
 	film.AvgRating = avgRating
 	film.Runtime = runtime
 	film.Cast = cast
+	film.PosterURL = findPosterURL(doc)
 	film.Slug = filmSlug(url)
 	film.FilmID = findFilmID(doc)
 	if film.FilmID != "" {
@@ -38,6 +41,29 @@ func parseFilm(doc *goquery.Document, url string) (Film, error) {
 	return film, nil
 }
 
+// findPosterURL prefers the poster in the page's JSON-LD; og:image is the
+// backdrop on films that have one.
+func findPosterURL(doc *goquery.Document) string {
+	var poster string
+	doc.Find(`script[type="application/ld+json"]`).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		text := strings.TrimSpace(s.Text())
+		text = strings.TrimPrefix(text, "/* <![CDATA[ */")
+		text = strings.TrimSuffix(text, "/* ]]> */")
+		var data struct {
+			Image string `json:"image"`
+		}
+		if json.Unmarshal([]byte(strings.TrimSpace(text)), &data) == nil && data.Image != "" {
+			poster = data.Image
+			return false
+		}
+		return true
+	})
+	if poster == "" {
+		poster = doc.Find(`meta[property="og:image"]`).AttrOr("content", "")
+	}
+	return strings.TrimSpace(poster)
+}
+
 func findRuntime(doc *goquery.Document) string {
 	text := compactSpaces(doc.Find("p.text-link.text-footer").First().Text())
 	if text == "" {
@@ -55,6 +81,19 @@ func findRuntime(doc *goquery.Document) string {
 	return ""
 }
 
+func RuntimeMinutes(runtime string) int {
+	runtime = strings.TrimSpace(runtime)
+	end := 0
+	for end < len(runtime) && runtime[end] >= '0' && runtime[end] <= '9' {
+		end++
+	}
+	mins, err := strconv.Atoi(runtime[:end])
+	if err != nil {
+		return 0
+	}
+	return mins
+}
+
 func parseTopBilledCast(doc *goquery.Document, limit int) []string {
 	var cast []string
 	doc.Find("#tab-cast .cast-list a.text-slug").EachWithBreak(func(_ int, sel *goquery.Selection) bool {
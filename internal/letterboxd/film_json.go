@@ -16,12 +16,14 @@ type filmJSONResponse struct {
 	InWatchlist *bool  `json:"inWatchlist"`
 }
 
-func (c *Client) filmJSON(slug string) (filmJSONResponse, error) {
+func (c *Client) filmJSON(slug string) (payload filmJSONResponse, err error) {
 	slug = strings.TrimSpace(slug)
 	if slug == "" {
 		return filmJSONResponse{}, c.wrapDebug(fmt.Errorf("missing film slug"))
 	}
 	reqURL := fmt.Sprintf("%s/film/%s/json", BaseURL, slug)
+	tr := c.newTrace(http.MethodGet, reqURL)
+	defer func() { err = tr.finish(err) }()
 	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
 	if err != nil {
 		return filmJSONResponse{}, c.wrapDebug(err)
@@ -30,7 +32,7 @@ func (c *Client) filmJSON(slug string) (filmJSONResponse, error) {
 	if c.Cookie != "" {
 		req.Header.Set("Cookie", c.Cookie)
 	}
-	resp, err := c.HTTP.Do(req)
+	resp, err := tr.do(c, c.HTTP, req)
 	if err != nil {
 		return filmJSONResponse{}, c.wrapDebug(err)
 	}
@@ -38,7 +40,6 @@ func (c *Client) filmJSON(slug string) (filmJSONResponse, error) {
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return filmJSONResponse{}, c.httpStatusError(req, resp)
 	}
-	var payload filmJSONResponse
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
 		return filmJSONResponse{}, c.wrapDebug(err)
 	}
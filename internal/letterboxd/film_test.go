@@ -49,6 +49,15 @@ func TestFindRuntime(t *testing.T) {
 	}
 }
 
+func TestRuntimeMinutes(t *testing.T) {
+	cases := map[string]int{"148 mins": 148, "90": 90, " 7 mins ": 7, "": 0, "mins": 0}
+	for in, want := range cases {
+		if got := RuntimeMinutes(in); got != want {
+			t.Fatalf("RuntimeMinutes(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
 func TestParseTopBilledCastLimit(t *testing.T) {
 	doc := docFromHTML(t, `<div id="tab-cast"><div class="cast-list">
 		<a class="text-slug">A</a><a class="text-slug">B</a><a class="text-slug">C</a>
@@ -0,0 +1,159 @@
+package letterboxd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type FilmsFilter struct {
+	Decade  string // e.g. "1990s"
+	Year    string // e.g. "1994"
+	Genre   string // genre slug, e.g. "horror"
+	Rated   string // "4.5", a range like "4-5", or "none"
+	Service string // streaming service slug, e.g. "netflix-us"
+	Liked   bool
+	Sort    FilmsSort
+}
+
+func (f FilmsFilter) Active() bool {
+	return f.Decade != "" || f.Year != "" || f.Genre != "" || f.Rated != "" || f.Service != "" || f.Liked
+}
+
+func (c *Client) UserFilms(username string, filter FilmsFilter, page int) ([]UserFilm, error) {
+	if strings.TrimSpace(username) == "" {
+		return nil, c.wrapDebug(errors.New("missing username"))
+	}
+	doc, err := c.fetchDocument(userFilmsURL(username, filter, page))
+	if err != nil {
+		return nil, c.wrapDebug(err)
+	}
+	films, err := parseUserFilms(doc)
+	return films, c.wrapDebug(err)
+}
+
+func (c *Client) AllUserFilms(username string) ([]UserFilm, error) {
+	var all []UserFilm
+	seen := map[string]struct{}{}
+	for page := 1; ; page++ {
+		if page > maxListPages {
+			return all, ErrListTruncated
+		}
+		films, err := c.UserFilms(username, FilmsFilter{}, page)
+		if err != nil {
+			return nil, err
+		}
+		added := 0
+		for _, film := range films {
+			key := NormalizeFilmURL(film.FilmURL)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			all = append(all, film)
+			added++
+		}
+		if added == 0 {
+			break
+		}
+	}
+	return all, nil
+}
+
+func userFilmsURL(username string, filter FilmsFilter, page int) string {
+	segments := []string{username}
+	if filter.Liked {
+		segments = append(segments, "likes", "films")
+	} else {
+		segments = append(segments, "films")
+	}
+	if rated := strings.TrimSpace(filter.Rated); rated != "" {
+		segments = append(segments, "rated", rated)
+	}
+	segments = append(segments, filterSegments(filter.Decade, filter.Year, filter.Genre, filter.Service)...)
+	if filter.Sort != "" {
+		segments = append(segments, "by", string(filter.Sort))
+	}
+	if page > 1 {
+		segments = append(segments, "page", strconv.Itoa(page))
+	}
+	return BaseURL + "/" + strings.Join(segments, "/") + "/"
+}
+
+func filterSegments(decade, year, genre, service string) []string {
+	var segments []string
+	if year = strings.TrimSpace(year); year != "" {
+		segments = append(segments, "year", year)
+	} else if decade = normalizeDecade(decade); decade != "" {
+		segments = append(segments, "decade", decade)
+	}
+	if genre = slugify(genre); genre != "" {
+		segments = append(segments, "genre", genre)
+	}
+	if service = slugify(service); service != "" {
+		segments = append(segments, "on", service)
+	}
+	return segments
+}
+
+func normalizeDecade(decade string) string {
+	decade = strings.ToLower(strings.TrimSpace(decade))
+	if decade == "" {
+		return ""
+	}
+	if !strings.HasSuffix(decade, "s") {
+		decade += "s"
+	}
+	return decade
+}
+
+func slugify(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return strings.Join(strings.Fields(value), "-")
+}
+
+func RatedSegment(stars float64, orHigher bool) string {
+	if stars <= 0 {
+		return ""
+	}
+	value := strconv.FormatFloat(stars, 'f', -1, 64)
+	if orHigher && stars < 5 {
+		return fmt.Sprintf("%s-5", value)
+	}
+	return value
+}
+
+func parseUserFilms(doc *goquery.Document) ([]UserFilm, error) {
+	var films []UserFilm
+	doc.Find("li.poster-container, li.griditem").Each(func(_ int, item *goquery.Selection) {
+		comp := item.Find(".react-component").First()
+		title := strings.TrimSpace(comp.AttrOr("data-item-name", ""))
+		filmURL := strings.TrimSpace(comp.AttrOr("data-item-link", ""))
+		if title == "" || !strings.Contains(filmURL, "/film/") {
+			return
+		}
+		if strings.HasPrefix(filmURL, "/") {
+			filmURL = BaseURL + filmURL
+		}
+		year := ""
+		if open := strings.LastIndex(title, "("); open != -1 {
+			if close := strings.LastIndex(title, ")"); close > open {
+				year = strings.TrimSpace(title[open+1 : close])
+				title = strings.TrimSpace(title[:open])
+			}
+		}
+		viewing := item.Find(".poster-viewingdata").First()
+		films = append(films, UserFilm{
+			Title:    title,
+			Year:     year,
+			FilmURL:  filmURL,
+			Rating:   strings.TrimSpace(viewing.Find(".rating").First().Text()),
+			Liked:    viewing.Find(".like, .icon-liked").Length() > 0,
+			Reviewed: viewing.Find("a.review-micro, .icon-review").Length() > 0,
+		})
+	})
+	return films, nil
+}
@@ -0,0 +1,90 @@
+package letterboxd
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseUserFilms(t *testing.T) {
+	html := `
+	<ul class="poster-list">
+		<li class="poster-container">
+			<div class="react-component" data-item-name="Hereditary (2018)" data-item-link="/film/hereditary/"></div>
+			<p class="poster-viewingdata"><span class="rating -micro rated-9">★★★★½</span><span class="like"></span></p>
+		</li>
+		<li class="poster-container">
+			<div class="react-component" data-item-name="Alien" data-item-link="/film/alien/"></div>
+			<p class="poster-viewingdata"><a class="review-micro" href="/jane/film/alien/"></a></p>
+		</li>
+		<li class="poster-container">
+			<div class="react-component" data-item-name="Some List" data-item-link="/jane/list/x/"></div>
+		</li>
+	</ul>`
+	films, err := parseUserFilms(docFromHTML(t, html))
+	if err != nil {
+		t.Fatalf("parseUserFilms error: %v", err)
+	}
+	if len(films) != 2 {
+		t.Fatalf("expected 2 films, got %+v", films)
+	}
+	if films[0].Title != "Hereditary" || films[0].Year != "2018" || films[0].Rating != "★★★★½" || !films[0].Liked {
+		t.Fatalf("unexpected first film: %+v", films[0])
+	}
+	if films[1].FilmURL != BaseURL+"/film/alien/" || !films[1].Reviewed || films[1].Liked {
+		t.Fatalf("unexpected second film: %+v", films[1])
+	}
+}
+
+func TestUserFilmsURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter FilmsFilter
+		page   int
+		want   string
+	}{
+		{name: "default", want: BaseURL + "/jane/films/"},
+		{name: "page", page: 2, want: BaseURL + "/jane/films/page/2/"},
+		{name: "decade genre", filter: FilmsFilter{Decade: "1990", Genre: "Horror"}, want: BaseURL + "/jane/films/decade/1990s/genre/horror/"},
+		{name: "year wins", filter: FilmsFilter{Decade: "1990s", Year: "1994"}, want: BaseURL + "/jane/films/year/1994/"},
+		{name: "rated sorted", filter: FilmsFilter{Rated: "4-5", Sort: FilmsSortEntryRating}, page: 3, want: BaseURL + "/jane/films/rated/4-5/by/entry-rating/page/3/"},
+		{name: "liked service", filter: FilmsFilter{Liked: true, Service: "Netflix US"}, want: BaseURL + "/jane/likes/films/on/netflix-us/"},
+	}
+	for _, tt := range tests {
+		if got := userFilmsURL("jane", tt.filter, tt.page); got != tt.want {
+			t.Fatalf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestRatedSegment(t *testing.T) {
+	if got := RatedSegment(4, true); got != "4-5" {
+		t.Fatalf("unexpected rated segment: %q", got)
+	}
+	if got := RatedSegment(4.5, false); got != "4.5" {
+		t.Fatalf("unexpected rated segment: %q", got)
+	}
+	if got := RatedSegment(5, true); got != "5" {
+		t.Fatalf("unexpected rated segment: %q", got)
+	}
+	if got := RatedSegment(0, true); got != "" {
+		t.Fatalf("expected empty segment, got %q", got)
+	}
+}
+
+func TestUserFilms(t *testing.T) {
+	var gotPath string
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Path
+		return newHTTPResponse(http.StatusOK, `<li class="poster-container"><div class="react-component" data-item-name="Alien (1979)" data-item-link="/film/alien/"></div></li>`, nil), nil
+	})
+	films, err := client.UserFilms("jane", FilmsFilter{Genre: "horror"}, 1)
+	if err != nil {
+		t.Fatalf("UserFilms error: %v", err)
+	}
+	if len(films) != 1 || gotPath != "/jane/films/genre/horror/" {
+		t.Fatalf("unexpected result: %+v path=%s", films, gotPath)
+	}
+	if _, err := client.UserFilms("", FilmsFilter{}, 1); err == nil {
+		t.Fatalf("expected error for missing username")
+	}
+}
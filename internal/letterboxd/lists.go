@@ -0,0 +1,82 @@
+package letterboxd
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var listCountRE = regexp.MustCompile(`([\d,]+)\s+films?`)
+
+// Lists returns one page of the lists username has made, newest first.
+func (c *Client) Lists(username string, page int) ([]List, error) {
+	if strings.TrimSpace(username) == "" {
+		return nil, c.wrapDebug(errors.New("missing username"))
+	}
+	url := BaseURL + "/" + username + "/lists/"
+	if page > 1 {
+		url += "page/" + strconv.Itoa(page) + "/"
+	}
+	doc, err := c.fetchDocument(url)
+	if err != nil {
+		return nil, c.wrapDebug(err)
+	}
+	return parseLists(doc), nil
+}
+
+// ListFilms returns one page of the films on the list at listURL, in the
+// list's order.
+func (c *Client) ListFilms(listURL string, page int) ([]UserFilm, error) {
+	listURL = strings.TrimSpace(listURL)
+	if listURL == "" {
+		return nil, c.wrapDebug(errors.New("missing list URL"))
+	}
+	if strings.HasPrefix(listURL, "/") {
+		listURL = BaseURL + listURL
+	}
+	if !strings.HasSuffix(listURL, "/") {
+		listURL += "/"
+	}
+	if page > 1 {
+		listURL += "page/" + strconv.Itoa(page) + "/"
+	}
+	doc, err := c.fetchDocument(listURL)
+	if err != nil {
+		return nil, c.wrapDebug(err)
+	}
+	films, err := parseUserFilms(doc)
+	return films, c.wrapDebug(err)
+}
+
+func parseLists(doc *goquery.Document) []List {
+	var lists []List
+	doc.Find("section.list, article.list-summary").Each(func(_ int, item *goquery.Selection) {
+		link := item.Find("h2 a").First()
+		name := strings.TrimSpace(link.Text())
+		href := strings.TrimSpace(link.AttrOr("href", ""))
+		if name == "" || !strings.Contains(href, "/list/") {
+			return
+		}
+		if strings.HasPrefix(href, "/") {
+			href = BaseURL + href
+		}
+		count := 0
+		text := strings.ReplaceAll(item.Find(".value").First().Text(), "\u00a0", " ")
+		if text == "" {
+			text = strings.ReplaceAll(item.Text(), "\u00a0", " ")
+		}
+		if match := listCountRE.FindStringSubmatch(text); match != nil {
+			count, _ = strconv.Atoi(strings.ReplaceAll(match[1], ",", ""))
+		}
+		lists = append(lists, List{
+			Name:        name,
+			URL:         href,
+			Description: compactSpaces(item.Find(".body-text, .notes").First().Text()),
+			FilmCount:   count,
+		})
+	})
+	return lists
+}
@@ -0,0 +1,47 @@
+package letterboxd
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseLists(t *testing.T) {
+	html := `
+	<section class="list-set">
+		<section class="list">
+			<h2 class="title-2"><a href="/jane/list/best-of-2024/">Best of 2024</a></h2>
+			<small class="value">1,204&nbsp;films</small>
+			<div class="body-text"><p>The  good ones.</p></div>
+		</section>
+		<article class="list-summary">
+			<h2 class="name"><a href="/jane/list/heists/">Heists</a></h2>
+			<span class="value">1 film</span>
+		</article>
+		<section class="list"><h2><a href="/film/heat/">Not a list</a></h2></section>
+	</section>`
+	lists := parseLists(docFromHTML(t, html))
+	if len(lists) != 2 {
+		t.Fatalf("expected 2 lists, got %+v", lists)
+	}
+	if lists[0] != (List{Name: "Best of 2024", URL: BaseURL + "/jane/list/best-of-2024/", Description: "The good ones.", FilmCount: 1204}) {
+		t.Fatalf("unexpected first list: %+v", lists[0])
+	}
+	if lists[1].Name != "Heists" || lists[1].FilmCount != 1 {
+		t.Fatalf("unexpected second list: %+v", lists[1])
+	}
+}
+
+func TestListFilmsPages(t *testing.T) {
+	var paths []string
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		paths = append(paths, req.URL.Path)
+		return newHTTPResponse(http.StatusOK, `<ul><li class="griditem"><div class="react-component" data-item-name="Heat (1995)" data-item-link="/film/heat/"></div></li></ul>`, nil), nil
+	})
+	films, err := client.ListFilms("/jane/list/heists", 2)
+	if err != nil || len(films) != 1 || films[0].Title != "Heat" {
+		t.Fatalf("unexpected films %+v err=%v", films, err)
+	}
+	if len(paths) != 1 || paths[0] != "/jane/list/heists/page/2/" {
+		t.Fatalf("unexpected request paths %v", paths)
+	}
+}
@@ -0,0 +1,198 @@
+package letterboxd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/solean/letterboxd-tui/internal/logging"
+)
+
+type PeopleKind string
+
+const (
+	PeopleFollowing PeopleKind = "following"
+	PeopleFollowers PeopleKind = "followers"
+)
+
+func (c *Client) Following(username string, page int) ([]Member, error) {
+	return c.people(username, PeopleFollowing, page)
+}
+
+func (c *Client) Followers(username string, page int) ([]Member, error) {
+	return c.people(username, PeopleFollowers, page)
+}
+
+func (c *Client) people(username string, kind PeopleKind, page int) ([]Member, error) {
+	if strings.TrimSpace(username) == "" {
+		return nil, c.wrapDebug(errors.New("missing username"))
+	}
+	doc, err := c.fetchDocument(peopleURL(username, kind, page))
+	if err != nil {
+		return nil, c.wrapDebug(err)
+	}
+	members, err := parsePeople(doc)
+	return members, c.wrapDebug(err)
+}
+
+func (c *Client) Follow(username string) error {
+	return c.SetFollowing(username, true)
+}
+
+func (c *Client) Unfollow(username string) error {
+	return c.SetFollowing(username, false)
+}
+
+func (c *Client) SetFollowing(username string, follow bool) error {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return c.wrapDebug(errors.New("missing username"))
+	}
+	csrf := cookieValue(c.Cookie, "com.xk72.webparts.csrf")
+	if csrf == "" {
+		return c.wrapDebug(errors.New("missing csrf token in cookie"))
+	}
+	action := "unfollow"
+	if follow {
+		action = "follow"
+	}
+	values := url.Values{}
+	values.Set("__csrf", csrf)
+	reqURL := fmt.Sprintf("%s/%s/%s/", BaseURL, username, action)
+	return c.postFollow(reqURL, values, ProfileURL(username), action)
+}
+
+func (c *Client) postFollow(reqURL string, values url.Values, referer, action string) (err error) {
+	tr := c.newTrace(http.MethodPost, reqURL)
+	defer func() { err = tr.finish(err) }()
+	const maxAttempts = 1
+	useFallback := false
+	for attempt := 0; attempt <= maxAttempts; attempt++ {
+		httpReq, err := http.NewRequest(http.MethodPost, reqURL, strings.NewReader(values.Encode()))
+		if err != nil {
+			return c.wrapDebug(err)
+		}
+		applyDefaultHeaders(httpReq)
+		httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		httpReq.Header.Set("Origin", BaseURL)
+		httpReq.Header.Set("Accept", "application/json, text/javascript, */*; q=0.01")
+		httpReq.Header.Set("X-Requested-With", "XMLHttpRequest")
+		if strings.TrimSpace(referer) != "" {
+			httpReq.Header.Set("Referer", referer)
+		}
+		if c.Cookie != "" {
+			httpReq.Header.Set("Cookie", c.Cookie)
+		}
+
+		client := c.HTTP
+		if useFallback {
+			client = c.fallbackClient()
+		}
+		resp, err := tr.do(c, client, httpReq)
+		if err != nil {
+			return c.wrapDebug(err)
+		}
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+			resp.Body.Close()
+			if err != nil {
+				return c.wrapDebug(err)
+			}
+			if errMsg := diarySaveError(body); errMsg != "" {
+				return c.wrapDebug(fmt.Errorf("%s failed: %s", action, errMsg))
+			}
+			return nil
+		}
+		snippet := ""
+		if data, _ := io.ReadAll(io.LimitReader(resp.Body, 512)); len(data) > 0 {
+			snippet = logging.Redact(strings.TrimSpace(string(data)))
+		}
+		resp.Body.Close()
+		isChallenge := isCloudflareChallenge(resp.StatusCode, snippet)
+		if attempt < maxAttempts && (isChallenge || shouldRetryStatus(resp.StatusCode)) {
+			if !useFallback {
+				useFallback = true
+				continue
+			}
+			time.Sleep(cloudflareBackoff(attempt))
+			continue
+		}
+		if isChallenge {
+			return c.cloudflareError(httpReq, resp, snippet)
+		}
+		if snippet != "" {
+			return c.wrapDebug(fmt.Errorf("%s failed: status %d body=%q", action, resp.StatusCode, snippet))
+		}
+		return c.wrapDebug(fmt.Errorf("%s failed: status %d", action, resp.StatusCode))
+	}
+	return c.wrapDebug(fmt.Errorf("%s failed: retry attempts exhausted", action))
+}
+
+func peopleURL(username string, kind PeopleKind, page int) string {
+	if kind == "" {
+		kind = PeopleFollowing
+	}
+	if page > 1 {
+		return fmt.Sprintf("%s/%s/%s/page/%d/", BaseURL, username, kind, page)
+	}
+	return fmt.Sprintf("%s/%s/%s/", BaseURL, username, kind)
+}
+
+func PeopleKindFromURL(rawURL string) (PeopleKind, bool) {
+	rawURL = strings.TrimPrefix(strings.TrimSpace(rawURL), BaseURL)
+	parts := strings.Split(strings.Trim(rawURL, "/"), "/")
+	if len(parts) < 2 {
+		return "", false
+	}
+	switch PeopleKind(parts[1]) {
+	case PeopleFollowing:
+		return PeopleFollowing, true
+	case PeopleFollowers:
+		return PeopleFollowers, true
+	}
+	return "", false
+}
+
+func parsePeople(doc *goquery.Document) ([]Member, error) {
+	var members []Member
+	seen := make(map[string]struct{})
+	doc.Find(".person-summary").Each(func(_ int, summary *goquery.Selection) {
+		nameSel := summary.Find("a.name").First()
+		if nameSel.Length() == 0 {
+			nameSel = summary.Find("a.avatar").First()
+		}
+		username := UsernameFromURL(strings.TrimSpace(nameSel.AttrOr("href", "")))
+		if username == "" {
+			return
+		}
+		if _, ok := seen[username]; ok {
+			return
+		}
+		seen[username] = struct{}{}
+		display := compactSpaces(nameSel.Text())
+		if display == "" {
+			display = username
+		}
+		member := Member{
+			Username:    username,
+			DisplayName: display,
+			URL:         ProfileURL(username),
+		}
+		row := summary.Closest("tr")
+		if row.Length() == 0 {
+			row = summary.Parent()
+		}
+		if wrapper := row.Find(".js-follow-button-wrapper").First(); wrapper.Length() > 0 {
+			class := wrapper.AttrOr("class", "")
+			member.Followed = strings.Contains(class, "-following")
+			member.FollowOK = true
+		}
+		members = append(members, member)
+	})
+	return members, nil
+}
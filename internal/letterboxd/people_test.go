@@ -0,0 +1,106 @@
+package letterboxd
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestParsePeople(t *testing.T) {
+	html := `
+	<table class="person-table">
+		<tr>
+			<td class="table-person">
+				<div class="person-summary">
+					<a class="avatar" href="/alice/"></a>
+					<h3 class="title-3"><a class="name" href="/alice/">Alice A.</a></h3>
+				</div>
+			</td>
+			<td class="col-follow"><div class="follow-button-wrapper js-follow-button-wrapper -following"></div></td>
+		</tr>
+		<tr>
+			<td class="table-person">
+				<div class="person-summary">
+					<a class="avatar" href="/bob/"></a>
+					<h3 class="title-3"><a class="name" href="/bob/"> </a></h3>
+				</div>
+			</td>
+			<td class="col-follow"><div class="follow-button-wrapper js-follow-button-wrapper"></div></td>
+		</tr>
+		<tr><td><div class="person-summary"><a class="name" href="/alice/">Alice A.</a></div></td></tr>
+	</table>`
+	members, err := parsePeople(docFromHTML(t, html))
+	if err != nil {
+		t.Fatalf("parsePeople error: %v", err)
+	}
+	if len(members) != 2 {
+		t.Fatalf("expected 2 members, got %+v", members)
+	}
+	if members[0].Username != "alice" || members[0].DisplayName != "Alice A." || !members[0].Followed || !members[0].FollowOK {
+		t.Fatalf("unexpected first member: %+v", members[0])
+	}
+	if members[1].Username != "bob" || members[1].DisplayName != "bob" || members[1].Followed {
+		t.Fatalf("unexpected second member: %+v", members[1])
+	}
+	if members[1].URL != BaseURL+"/bob/" {
+		t.Fatalf("unexpected member URL: %q", members[1].URL)
+	}
+}
+
+func TestPeopleURL(t *testing.T) {
+	if got := peopleURL("jane", PeopleFollowing, 1); got != BaseURL+"/jane/following/" {
+		t.Fatalf("unexpected people URL: %q", got)
+	}
+	if got := peopleURL("jane", PeopleFollowers, 3); got != BaseURL+"/jane/followers/page/3/" {
+		t.Fatalf("unexpected people URL: %q", got)
+	}
+}
+
+func TestPeopleKindFromURL(t *testing.T) {
+	if kind, ok := PeopleKindFromURL(BaseURL + "/jane/followers/"); !ok || kind != PeopleFollowers {
+		t.Fatalf("unexpected kind: %q %v", kind, ok)
+	}
+	if kind, ok := PeopleKindFromURL("/jane/following/"); !ok || kind != PeopleFollowing {
+		t.Fatalf("unexpected kind: %q %v", kind, ok)
+	}
+	if _, ok := PeopleKindFromURL(BaseURL + "/jane/films/"); ok {
+		t.Fatalf("expected films link to be rejected")
+	}
+}
+
+func TestSetFollowing(t *testing.T) {
+	var gotPath, gotCSRF string
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Path
+		_ = req.ParseForm()
+		gotCSRF = req.PostForm.Get("__csrf")
+		return newHTTPResponse(http.StatusOK, `{"result":true}`, nil), nil
+	})
+	if err := client.Follow("alice"); err != nil {
+		t.Fatalf("Follow error: %v", err)
+	}
+	if gotPath != "/alice/follow/" || gotCSRF != "csrf123" {
+		t.Fatalf("unexpected follow request: %s csrf=%s", gotPath, gotCSRF)
+	}
+	if err := client.Unfollow("alice"); err != nil {
+		t.Fatalf("Unfollow error: %v", err)
+	}
+	if gotPath != "/alice/unfollow/" {
+		t.Fatalf("unexpected unfollow path: %s", gotPath)
+	}
+}
+
+func TestSetFollowingErrors(t *testing.T) {
+	if err := NewClient(nil, "com.xk72.webparts.csrf=x").Follow(" "); err == nil {
+		t.Fatalf("expected error for missing username")
+	}
+	if err := NewClient(nil, "").Follow("alice"); err == nil {
+		t.Fatalf("expected error for missing csrf")
+	}
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		return newHTTPResponse(http.StatusOK, `{"result":false,"messages":["Nope"]}`, nil), nil
+	})
+	if err := client.postFollow(BaseURL+"/alice/follow/", make(url.Values), "", "follow"); err == nil {
+		t.Fatalf("expected error for failed result")
+	}
+}
@@ -0,0 +1,43 @@
+package letterboxd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxPosterBytes caps a poster download; Letterboxd's are well under it.
+const maxPosterBytes = 8 << 20
+
+// Poster downloads the image at posterURL, as found in Film.PosterURL.
+func (c *Client) Poster(posterURL string) (data []byte, err error) {
+	posterURL = strings.TrimSpace(posterURL)
+	if posterURL == "" {
+		return nil, c.wrapDebug(fmt.Errorf("missing poster url"))
+	}
+	tr := c.newTrace(http.MethodGet, posterURL)
+	defer func() { err = tr.finish(err) }()
+	req, err := http.NewRequest(http.MethodGet, posterURL, nil)
+	if err != nil {
+		return nil, c.wrapDebug(err)
+	}
+	applyDefaultHeaders(req)
+	req.Header.Set("Accept", "image/jpeg,image/png,image/*;q=0.8")
+	resp, err := tr.do(c, c.HTTP, req)
+	if err != nil {
+		return nil, c.wrapDebug(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, c.httpStatusError(req, resp)
+	}
+	data, err = io.ReadAll(io.LimitReader(resp.Body, maxPosterBytes+1))
+	if err != nil {
+		return nil, c.wrapDebug(err)
+	}
+	if len(data) > maxPosterBytes {
+		return nil, c.wrapDebug(fmt.Errorf("poster larger than %d bytes", maxPosterBytes))
+	}
+	return data, nil
+}
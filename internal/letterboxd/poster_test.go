@@ -0,0 +1,46 @@
+package letterboxd
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFindPosterURLPrefersJSONLD(t *testing.T) {
+	doc := docFromHTML(t, `<html><head>
+		<meta property="og:image" content="https://a.ltrbxd.com/backdrop.jpg">
+		<script type="application/ld+json">
+		/* <![CDATA[ */
+		{"@type":"Movie","image":"https://a.ltrbxd.com/resized/film-poster/poster.jpg"}
+		/* ]]> */
+		</script>
+	</head></html>`)
+	if got := findPosterURL(doc); got != "https://a.ltrbxd.com/resized/film-poster/poster.jpg" {
+		t.Fatalf("unexpected poster url: %q", got)
+	}
+
+	doc = docFromHTML(t, `<meta property="og:image" content="https://a.ltrbxd.com/only.jpg">`)
+	if got := findPosterURL(doc); got != "https://a.ltrbxd.com/only.jpg" {
+		t.Fatalf("expected og:image fallback, got %q", got)
+	}
+}
+
+func TestPosterDownloadsWithoutCookie(t *testing.T) {
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("Cookie") != "" {
+			t.Fatalf("poster request should not send the session cookie")
+		}
+		return newHTTPResponse(http.StatusOK, "\x89PNG...", nil), nil
+	})
+	data, err := client.Poster("https://a.ltrbxd.com/poster.jpg")
+	if err != nil || !strings.HasPrefix(string(data), "\x89PNG") {
+		t.Fatalf("unexpected poster: %q %v", data, err)
+	}
+
+	client = newTestClient(func(*http.Request) (*http.Response, error) {
+		return newHTTPResponse(http.StatusNotFound, "missing", nil), nil
+	})
+	if _, err := client.Poster("https://a.ltrbxd.com/poster.jpg"); err == nil {
+		t.Fatalf("expected error for missing poster")
+	}
+}
@@ -24,6 +24,11 @@ func parseProfile(doc *goquery.Document) (Profile, error) {
 		}
 	})
 
+	if wrapper := doc.Find(".profile-header .js-follow-button-wrapper, .profile-summary .js-follow-button-wrapper").First(); wrapper.Length() > 0 {
+		profile.Followed = strings.Contains(wrapper.AttrOr("class", ""), "-following")
+		profile.FollowOK = true
+	}
+
 	doc.Find("#favourites .posteritem .react-component").Each(func(_ int, fav *goquery.Selection) {
 		title := strings.TrimSpace(fav.AttrOr("data-item-name", ""))
 		filmURL := strings.TrimSpace(fav.AttrOr("data-item-link", ""))
@@ -38,3 +38,18 @@ func TestParseProfile(t *testing.T) {
 		t.Fatalf("unexpected recent film url: %q", profile.Recent[0].FilmURL)
 	}
 }
+
+func TestParseProfileFollowState(t *testing.T) {
+	html := `<section class="profile-header"><div class="follow-button-wrapper js-follow-button-wrapper -following"></div></section>`
+	profile, err := parseProfile(docFromHTML(t, html))
+	if err != nil {
+		t.Fatalf("parseProfile error: %v", err)
+	}
+	if !profile.FollowOK || !profile.Followed {
+		t.Fatalf("expected followed profile: %+v", profile)
+	}
+	profile, _ = parseProfile(docFromHTML(t, `<div></div>`))
+	if profile.FollowOK {
+		t.Fatalf("did not expect follow state without button")
+	}
+}
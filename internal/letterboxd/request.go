@@ -0,0 +1,139 @@
+package letterboxd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/solean/letterboxd-tui/internal/logging"
+)
+
+// requestPrefix tells this run's request IDs from an earlier run's in the
+// same log.
+var (
+	requestPrefix = newRequestPrefix()
+	requestCount  atomic.Uint64
+)
+
+func newRequestPrefix() string {
+	var b [2]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "0000"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// trace follows one call to Letterboxd through its attempts, logging each
+// under one ID.
+type trace struct {
+	client   *Client
+	id       string
+	method   string
+	url      string
+	start    time.Time
+	attempts int
+	fallback bool
+	status   int
+
+	reqHeader  http.Header
+	respHeader http.Header
+}
+
+func (c *Client) newTrace(method, url string) *trace {
+	return &trace{
+		client: c,
+		id:     fmt.Sprintf("%s-%04d", requestPrefix, requestCount.Add(1)),
+		method: method,
+		url:    url,
+		start:  time.Now(),
+	}
+}
+
+// do sends one attempt with client, which is the fallback client on a
+// retry.
+func (t *trace) do(c *Client, client *http.Client, req *http.Request) (*http.Response, error) {
+	t.attempts++
+	if client != c.HTTP {
+		t.fallback = true
+	}
+	t.reqHeader = req.Header
+	begin := time.Now()
+	resp, err := client.Do(req)
+	attrs := []any{
+		slog.String("id", t.id),
+		slog.Int("attempt", t.attempts),
+		slog.String("method", t.method),
+		slog.String("url", t.url),
+		slog.Duration("duration", time.Since(begin)),
+		slog.Bool("fallback", t.fallback),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("err", err))
+	} else {
+		t.status = resp.StatusCode
+		t.respHeader = resp.Header
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	logging.Logger().Debug("http attempt", attrs...)
+	return resp, err
+}
+
+// finish logs and publishes how the call went, and ties err to the call's
+// ID.
+func (t *trace) finish(err error) error {
+	duration := time.Since(t.start)
+	t.client.publish(Event{
+		Kind:           EventRequest,
+		ID:             t.id,
+		Time:           t.start,
+		Method:         t.method,
+		URL:            t.url,
+		Status:         t.status,
+		Duration:       duration,
+		Retries:        max(t.attempts-1, 0),
+		Fallback:       t.fallback,
+		Err:            err,
+		RequestHeader:  redactHeader(t.reqHeader),
+		ResponseHeader: redactHeader(t.respHeader),
+	})
+	attrs := []any{
+		slog.String("id", t.id),
+		slog.String("method", t.method),
+		slog.String("url", t.url),
+		slog.Int("status", t.status),
+		slog.Duration("duration", duration),
+		slog.Int("retries", max(t.attempts-1, 0)),
+		slog.Bool("fallback", t.fallback),
+	}
+	if err == nil {
+		logging.Logger().Debug("http request", attrs...)
+		return nil
+	}
+	logging.Logger().Warn("http request failed", append(attrs, slog.Any("err", err))...)
+	return &requestError{id: t.id, err: err}
+}
+
+// requestError is an error from a traced call, carrying the ID it was
+// logged under so a later report of it can be matched up.
+type requestError struct {
+	id  string
+	err error
+}
+
+func (e *requestError) Error() string     { return e.err.Error() }
+func (e *requestError) Unwrap() error     { return e.err }
+func (e *requestError) RequestID() string { return e.id }
+
+// RequestID returns the ID of the request err came from, if any.
+func RequestID(err error) string {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return reqErr.id
+	}
+	return ""
+}
@@ -0,0 +1,87 @@
+package letterboxd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	// ErrSignedOut means the cookie no longer signs in: Letterboxd shows the
+	// sign-in form instead of the account.
+	ErrSignedOut = errors.New("the cookie is signed out or has expired")
+	// ErrWrongUser means the cookie signs in as someone else.
+	ErrWrongUser = errors.New("the cookie belongs to another member")
+)
+
+// Whoami returns the member the cookie signs in as, read from the account
+// settings page, which only a signed-in session can see.
+func (c *Client) Whoami() (string, error) {
+	if cookieValue(c.Cookie, "com.xk72.webparts.csrf") == "" {
+		return "", ErrSignedOut
+	}
+	doc, err := c.fetchDocument(BaseURL + "/settings/")
+	if err != nil {
+		return "", c.wrapDebug(err)
+	}
+	username := parseWhoami(doc)
+	if username == "" {
+		return "", ErrSignedOut
+	}
+	return username, nil
+}
+
+// CheckSession confirms the cookie signs in as username.
+func (c *Client) CheckSession(username string) error {
+	who, err := c.Whoami()
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(who, strings.TrimSpace(username)) {
+		return fmt.Errorf("%w: signed in as %s, not %s", ErrWrongUser, who, username)
+	}
+	return nil
+}
+
+func parseWhoami(doc *goquery.Document) string {
+	if doc.Find("form#signin-form, form.signin-form, form[action*='/user/login']").Length() > 0 {
+		return ""
+	}
+	if value, ok := doc.Find("input#frm-username, form input[name='username']").First().Attr("value"); ok && strings.TrimSpace(value) != "" {
+		return strings.TrimSpace(value)
+	}
+	// The account menu in the header links to the member's profile.
+	href, _ := doc.Find(".main-nav .nav-account > a[href], .nav-account a.toggle-menu[href]").First().Attr("href")
+	if parsed, err := url.Parse(href); err == nil {
+		if segment := strings.Split(strings.Trim(parsed.Path, "/"), "/")[0]; segment != "" {
+			return segment
+		}
+	}
+	return ""
+}
+
+// ClearanceIssued reads when the cookie's cf_clearance was issued, from the
+// timestamp Cloudflare puts in its value.
+func ClearanceIssued(cookie string) (time.Time, bool) {
+	value := cookieValue(cookie, "cf_clearance")
+	for _, part := range strings.Split(value, "-") {
+		if len(part) != 10 {
+			continue
+		}
+		if secs, err := strconv.ParseInt(part, 10, 64); err == nil {
+			return time.Unix(secs, 0), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ClearanceExpired reports whether err is a Cloudflare challenge, which is
+// what an expired or missing cf_clearance gets.
+func ClearanceExpired(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "Cloudflare challenge detected")
+}
@@ -0,0 +1,61 @@
+package letterboxd
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWhoami(t *testing.T) {
+	page := `<html><body><header><nav class="main-nav"><ul><li class="nav-account"><a class="toggle-menu" href="/jane/">Jane</a></li></ul></nav></header>
+		<form id="settings"><input id="frm-username" name="username" value="jane"></form></body></html>`
+	var path string
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		path = req.URL.Path
+		return newHTTPResponse(http.StatusOK, page, nil), nil
+	})
+	who, err := client.Whoami()
+	if err != nil || who != "jane" || path != "/settings/" {
+		t.Fatalf("unexpected whoami %q %v from %s", who, err, path)
+	}
+	if err := client.CheckSession("Jane"); err != nil {
+		t.Fatalf("expected the session to match, got %v", err)
+	}
+	if err := client.CheckSession("bob"); !errors.Is(err, ErrWrongUser) {
+		t.Fatalf("expected a mismatch, got %v", err)
+	}
+
+	navOnly := `<html><body><nav class="main-nav"><ul><li class="nav-account"><a href="/jane/">Jane</a></li></ul></nav></body></html>`
+	if who := parseWhoami(docFromHTML(t, navOnly)); who != "jane" {
+		t.Fatalf("expected the account menu to name the member, got %q", who)
+	}
+}
+
+func TestWhoamiSignedOut(t *testing.T) {
+	signIn := `<html><body><form id="signin-form" action="/user/login.do"><input name="username" value=""></form></body></html>`
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		return newHTTPResponse(http.StatusOK, signIn, nil), nil
+	})
+	if _, err := client.Whoami(); !errors.Is(err, ErrSignedOut) {
+		t.Fatalf("expected signed out, got %v", err)
+	}
+	calls := 0
+	client = NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return newHTTPResponse(http.StatusOK, "", nil), nil
+	})}, "a=b")
+	if _, err := client.Whoami(); !errors.Is(err, ErrSignedOut) || calls != 0 {
+		t.Fatalf("expected a cookie without CSRF to be signed out without a request, got %v after %d calls", err, calls)
+	}
+}
+
+func TestClearanceIssued(t *testing.T) {
+	issued, ok := ClearanceIssued("a=b; cf_clearance=Gmc1ek8O1Ks.lAa9tHZxSE-1760000000-1.2.1.1-xyz; c=d")
+	if !ok || !issued.Equal(time.Unix(1760000000, 0)) {
+		t.Fatalf("unexpected issue time %v %v", issued, ok)
+	}
+	if _, ok := ClearanceIssued("a=b"); ok {
+		t.Fatalf("expected no clearance")
+	}
+}
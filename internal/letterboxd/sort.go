@@ -1,6 +1,11 @@
 package letterboxd
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type DiarySort string
 
@@ -20,6 +25,18 @@ const (
 	WatchlistSortRating       WatchlistSort = "rating"
 )
 
+type FilmsSort string
+
+const (
+	FilmsSortDefault      FilmsSort = ""
+	FilmsSortName         FilmsSort = "name"
+	FilmsSortEntryRating  FilmsSort = "entry-rating"
+	FilmsSortRating       FilmsSort = "rating"
+	FilmsSortRelease      FilmsSort = "release"
+	FilmsSortShortest     FilmsSort = "shortest"
+	FilmsSortDateEarliest FilmsSort = "date-earliest"
+)
+
 func diaryURL(username string, page int, sort DiarySort) string {
 	if sort != "" {
 		if page > 1 {
@@ -33,15 +50,20 @@ func diaryURL(username string, page int, sort DiarySort) string {
 	return fmt.Sprintf("%s/%s/diary/", BaseURL, username)
 }
 
-func watchlistURL(username string, page int, sort WatchlistSort) string {
-	if sort != "" {
-		if page > 1 {
-			return fmt.Sprintf("%s/%s/watchlist/by/%s/page/%d/", BaseURL, username, sort, page)
-		}
-		return fmt.Sprintf("%s/%s/watchlist/by/%s/", BaseURL, username, sort)
+func diaryMonthURL(username string, year int, month time.Month, page int) string {
+	if page > 1 {
+		return fmt.Sprintf("%s/%s/diary/for/%d/%02d/page/%d/", BaseURL, username, year, month, page)
+	}
+	return fmt.Sprintf("%s/%s/diary/for/%d/%02d/", BaseURL, username, year, month)
+}
+
+func watchlistURL(username string, page int, filter WatchlistFilter) string {
+	segments := append([]string{username, "watchlist"}, filterSegments(filter.Decade, filter.Year, filter.Genre, filter.Service)...)
+	if filter.Sort != "" {
+		segments = append(segments, "by", string(filter.Sort))
 	}
 	if page > 1 {
-		return fmt.Sprintf("%s/%s/watchlist/page/%d/", BaseURL, username, page)
+		segments = append(segments, "page", strconv.Itoa(page))
 	}
-	return fmt.Sprintf("%s/%s/watchlist/", BaseURL, username)
+	return BaseURL + "/" + strings.Join(segments, "/") + "/"
 }
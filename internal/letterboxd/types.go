@@ -1,9 +1,11 @@
 package letterboxd
 
+import "time"
+
 const BaseURL = "https://letterboxd.com"
 
 type DiaryEntry struct {
-	Date    string
+	Date    time.Time
 	Title   string
 	FilmURL string
 	Rating  string
@@ -15,6 +17,8 @@ type Profile struct {
 	Stats     []ProfileStat
 	Favorites []FavoriteFilm
 	Recent    []ProfileRecent
+	Followed  bool
+	FollowOK  bool
 }
 
 type ProfileStat struct {
@@ -48,6 +52,7 @@ type Film struct {
 	AvgRating   string
 	Runtime     string
 	URL         string
+	PosterURL   string
 	Slug        string
 	FilmID      string
 	WatchlistID string
@@ -84,3 +89,27 @@ type SearchResult struct {
 	Slug    string
 	FilmID  string
 }
+
+type Member struct {
+	Username    string
+	DisplayName string
+	URL         string
+	Followed    bool
+	FollowOK    bool
+}
+
+type UserFilm struct {
+	Title    string
+	Year     string
+	FilmURL  string
+	Rating   string
+	Liked    bool
+	Reviewed bool
+}
+
+type List struct {
+	Name        string
+	URL         string
+	Description string
+	FilmCount   int
+}
@@ -1,6 +1,9 @@
 package letterboxd
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestProfileURL(t *testing.T) {
 	if got := ProfileURL(""); got != "" {
@@ -93,20 +96,37 @@ func TestDiaryURL(t *testing.T) {
 	}
 }
 
+func TestDiaryMonthURL(t *testing.T) {
+	if got := diaryMonthURL("jane", 2024, time.March, 1); got != BaseURL+"/jane/diary/for/2024/03/" {
+		t.Fatalf("unexpected diary month URL: %q", got)
+	}
+	if got := diaryMonthURL("jane", 2023, time.November, 2); got != BaseURL+"/jane/diary/for/2023/11/page/2/" {
+		t.Fatalf("unexpected diary month URL: %q", got)
+	}
+}
+
 func TestWatchlistURL(t *testing.T) {
-	if got := watchlistURL("jane", 1, WatchlistSortDefault); got != BaseURL+"/jane/watchlist/" {
+	if got := watchlistURL("jane", 1, WatchlistFilter{}); got != BaseURL+"/jane/watchlist/" {
 		t.Fatalf("unexpected watchlist URL: %q", got)
 	}
-	if got := watchlistURL("jane", 2, WatchlistSortDefault); got != BaseURL+"/jane/watchlist/page/2/" {
+	if got := watchlistURL("jane", 2, WatchlistFilter{}); got != BaseURL+"/jane/watchlist/page/2/" {
 		t.Fatalf("unexpected watchlist URL: %q", got)
 	}
-	if got := watchlistURL("jane", 1, WatchlistSortName); got != BaseURL+"/jane/watchlist/by/name/" {
+	if got := watchlistURL("jane", 1, WatchlistFilter{Sort: WatchlistSortName}); got != BaseURL+"/jane/watchlist/by/name/" {
 		t.Fatalf("unexpected watchlist URL: %q", got)
 	}
-	if got := watchlistURL("jane", 2, WatchlistSortRating); got != BaseURL+"/jane/watchlist/by/rating/page/2/" {
+	if got := watchlistURL("jane", 2, WatchlistFilter{Sort: WatchlistSortRating}); got != BaseURL+"/jane/watchlist/by/rating/page/2/" {
 		t.Fatalf("unexpected watchlist URL: %q", got)
 	}
-	if got := watchlistURL("jane", 4, WatchlistSortRelease); got != BaseURL+"/jane/watchlist/by/release/page/4/" {
+	if got := watchlistURL("jane", 4, WatchlistFilter{Sort: WatchlistSortRelease}); got != BaseURL+"/jane/watchlist/by/release/page/4/" {
 		t.Fatalf("unexpected watchlist URL: %q", got)
 	}
+	filter := WatchlistFilter{Decade: "1970", Genre: "Science Fiction", Service: "mubi", Sort: WatchlistSortName}
+	if got := watchlistURL("jane", 2, filter); got != BaseURL+"/jane/watchlist/decade/1970s/genre/science-fiction/on/mubi/by/name/page/2/" {
+		t.Fatalf("unexpected filtered watchlist URL: %q", got)
+	}
+	filter = WatchlistFilter{Decade: "1970s", Year: "1979"}
+	if got := watchlistURL("jane", 1, filter); got != BaseURL+"/jane/watchlist/year/1979/" {
+		t.Fatalf("unexpected year watchlist URL: %q", got)
+	}
 }
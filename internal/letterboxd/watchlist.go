@@ -6,6 +6,46 @@ import (
 	"github.com/PuerkitoBio/goquery"
 )
 
+type WatchlistRelease string
+
+const (
+	WatchlistReleaseAny WatchlistRelease = ""
+	WatchlistReleased   WatchlistRelease = "released"
+	WatchlistUnreleased WatchlistRelease = "unreleased"
+)
+
+type WatchlistFilter struct {
+	Decade  string
+	Year    string
+	Genre   string
+	Service string
+	Release WatchlistRelease
+	Sort    WatchlistSort
+}
+
+func (f WatchlistFilter) Active() bool {
+	return f.Decade != "" || f.Year != "" || f.Genre != "" || f.Service != "" || f.Release != WatchlistReleaseAny
+}
+
+// Letterboxd has no URL segment for release status; the site applies it from
+// the filmFilter cookie instead.
+func filmFilterHeaders(cookie string, release WatchlistRelease) map[string]string {
+	var value string
+	switch release {
+	case WatchlistReleased:
+		value = "hide-unreleased"
+	case WatchlistUnreleased:
+		value = "hide-released"
+	default:
+		return nil
+	}
+	cookie = strings.TrimSpace(cookie)
+	if cookie != "" {
+		cookie += "; "
+	}
+	return map[string]string{"Cookie": cookie + "filmFilter=" + value}
+}
+
 func parseWatchlist(doc *goquery.Document) ([]WatchlistItem, error) {
 	var items []WatchlistItem
 	doc.Find(".js-watchlist-main-content .react-component").Each(func(_ int, item *goquery.Selection) {
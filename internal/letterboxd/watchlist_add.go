@@ -8,6 +8,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/solean/letterboxd-tui/internal/logging"
 )
 
 type WatchlistRequest struct {
@@ -85,7 +87,9 @@ func (c *Client) SetWatchlist(req WatchlistRequest, inWatchlist bool) error {
 	return c.wrapDebug(errors.New("unable to update watchlist"))
 }
 
-func (c *Client) patchWatchlist(reqURL, csrf, referer string, inWatchlist bool) error {
+func (c *Client) patchWatchlist(reqURL, csrf, referer string, inWatchlist bool) (err error) {
+	tr := c.newTrace(http.MethodPatch, reqURL)
+	defer func() { err = tr.finish(err) }()
 	const maxAttempts = 1
 	useFallback := false
 	payload := fmt.Sprintf(`{"inWatchlist":%t}`, inWatchlist)
@@ -110,7 +114,7 @@ func (c *Client) patchWatchlist(reqURL, csrf, referer string, inWatchlist bool)
 		if useFallback {
 			client = c.fallbackClient()
 		}
-		resp, err := client.Do(httpReq)
+		resp, err := tr.do(c, client, httpReq)
 		if err != nil {
 			return c.wrapDebug(err)
 		}
@@ -120,7 +124,7 @@ func (c *Client) patchWatchlist(reqURL, csrf, referer string, inWatchlist bool)
 		}
 		snippet := ""
 		if data, _ := io.ReadAll(io.LimitReader(resp.Body, 512)); len(data) > 0 {
-			snippet = strings.TrimSpace(string(data))
+			snippet = logging.Redact(strings.TrimSpace(string(data)))
 		}
 		resp.Body.Close()
 		isChallenge := isCloudflareChallenge(resp.StatusCode, snippet)
@@ -143,7 +147,9 @@ func (c *Client) patchWatchlist(reqURL, csrf, referer string, inWatchlist bool)
 	return c.wrapDebug(errors.New("watchlist update failed: retry attempts exhausted"))
 }
 
-func (c *Client) postWatchlist(reqURL string, values url.Values, referer string) (int, error) {
+func (c *Client) postWatchlist(reqURL string, values url.Values, referer string) (status int, err error) {
+	tr := c.newTrace(http.MethodPost, reqURL)
+	defer func() { err = tr.finish(err) }()
 	const maxAttempts = 1
 	useFallback := false
 	var lastStatus int
@@ -168,7 +174,7 @@ func (c *Client) postWatchlist(reqURL string, values url.Values, referer string)
 		if useFallback {
 			client = c.fallbackClient()
 		}
-		resp, err := client.Do(httpReq)
+		resp, err := tr.do(c, client, httpReq)
 		if err != nil {
 			return 0, c.wrapDebug(err)
 		}
@@ -179,7 +185,7 @@ func (c *Client) postWatchlist(reqURL string, values url.Values, referer string)
 		}
 		snippet := ""
 		if data, _ := io.ReadAll(io.LimitReader(resp.Body, 512)); len(data) > 0 {
-			snippet = strings.TrimSpace(string(data))
+			snippet = logging.Redact(strings.TrimSpace(string(data)))
 		}
 		resp.Body.Close()
 		isChallenge := isCloudflareChallenge(resp.StatusCode, snippet)
@@ -1,6 +1,9 @@
 package letterboxd
 
-import "testing"
+import (
+	"net/http"
+	"testing"
+)
 
 func TestParseWatchlist(t *testing.T) {
 	html := `
@@ -24,3 +27,24 @@ func TestParseWatchlist(t *testing.T) {
 		t.Fatalf("unexpected film URL: %q", items[0].FilmURL)
 	}
 }
+
+func TestWatchlistReleaseFilterCookie(t *testing.T) {
+	var cookie string
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		cookie = req.Header.Get("Cookie")
+		return newHTTPResponse(http.StatusOK, `<div class="js-watchlist-main-content"></div>`, nil), nil
+	})
+	client.Cookie = "session=abc"
+	if _, err := client.Watchlist("jane", 1, WatchlistFilter{Release: WatchlistUnreleased}); err != nil {
+		t.Fatalf("Watchlist error: %v", err)
+	}
+	if cookie != "session=abc; filmFilter=hide-released" {
+		t.Fatalf("unexpected cookie header: %q", cookie)
+	}
+	if _, err := client.Watchlist("jane", 1, WatchlistFilter{}); err != nil {
+		t.Fatalf("Watchlist error: %v", err)
+	}
+	if cookie != "session=abc" {
+		t.Fatalf("unexpected cookie header without release filter: %q", cookie)
+	}
+}
@@ -1,53 +1,138 @@
+// Package logging writes the app's log file: leveled, rotated by size, and
+// with cookies and CSRF tokens redacted from everything written.
 package logging
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
-	"time"
+	"sync"
 )
 
 const (
-	appDir       = "letterboxd-tui"
-	errorLogFile = "errors.log"
+	appDir  = "letterboxd-tui"
+	logFile = "letterboxd.log"
+
+	// DefaultMaxSize is how big the log grows before it is rotated, and
+	// DefaultMaxFiles how many rotated logs are kept beside it.
+	DefaultMaxSize  = 5 << 20
+	DefaultMaxFiles = 3
+)
+
+// Options configures the logger set up by Setup.
+type Options struct {
+	Level    slog.Level
+	JSON     bool
+	Path     string // empty means LogPath
+	MaxSize  int64
+	MaxFiles int
+}
+
+var (
+	mu     sync.Mutex
+	logger *slog.Logger
+	closer io.Closer
 )
 
-func ErrorLogPath() (string, error) {
+func LogPath() (string, error) {
 	base, err := os.UserConfigDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(base, appDir, errorLogFile), nil
+	return filepath.Join(base, appDir, logFile), nil
 }
 
-func LogError(context string, err error) {
-	if err == nil {
-		return
+// ParseLevel reads a level name: debug, info, warn or error.
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
 	}
+	return slog.LevelInfo, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", name)
+}
 
-	path, pathErr := ErrorLogPath()
-	if pathErr != nil {
-		return
+// Setup points the logger at a rotated file, LogPath unless opts says
+// otherwise.
+func Setup(opts Options) error {
+	path := opts.Path
+	if path == "" {
+		var err error
+		if path, err = LogPath(); err != nil {
+			return err
+		}
 	}
-	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
-		return
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultMaxSize
+	}
+	if opts.MaxFiles <= 0 {
+		opts.MaxFiles = DefaultMaxFiles
+	}
+	file := &rotatingFile{path: path, maxSize: opts.MaxSize, maxFiles: opts.MaxFiles}
+	handlerOpts := &slog.HandlerOptions{Level: opts.Level}
+	var handler slog.Handler
+	if opts.JSON {
+		handler = slog.NewJSONHandler(file, handlerOpts)
+	} else {
+		handler = slog.NewTextHandler(file, handlerOpts)
 	}
 
-	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-	if err != nil {
-		return
+	mu.Lock()
+	defer mu.Unlock()
+	if closer != nil {
+		closer.Close()
 	}
-	defer file.Close()
+	logger, closer = slog.New(redactHandler{handler}), file
+	return nil
+}
+
+// discard is the logger until Setup is called, so tests and library use
+// don't write to the user's log.
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// Logger is the app's logger.
+func Logger() *slog.Logger {
+	mu.Lock()
+	defer mu.Unlock()
+	if logger == nil {
+		return discard
+	}
+	return logger
+}
+
+// requestIDer is an error from a request the letterboxd client logged
+// under an ID.
+type requestIDer interface {
+	RequestID() string
+}
 
+// LogError logs err at error level under context, with the ID of the
+// request it came from when there is one.
+func LogError(context string, err error) {
+	if err == nil {
+		return
+	}
 	ctx := strings.TrimSpace(context)
 	if ctx == "" {
 		ctx = "error"
 	}
-	timestamp := time.Now().Format(time.RFC3339)
-	message := formatError(err)
-	fmt.Fprintf(file, "%s [%s] %s\n", timestamp, ctx, message)
+	attrs := []any{slog.String("err", formatError(err))}
+	var withID requestIDer
+	if errors.As(err, &withID) {
+		attrs = append(attrs, slog.String("request_id", withID.RequestID()))
+	}
+	Logger().Error(ctx, attrs...)
 }
 
 func formatError(err error) string {
@@ -55,15 +140,7 @@ func formatError(err error) string {
 	if message == "" {
 		return "unknown error"
 	}
-	message = strings.ReplaceAll(message, "\r\n", "\n")
-	lines := strings.Split(message, "\n")
-	if len(lines) == 1 {
-		return lines[0]
-	}
-	for i := 1; i < len(lines); i++ {
-		lines[i] = "  " + lines[i]
-	}
-	return strings.Join(lines, "\n")
+	return strings.ReplaceAll(message, "\r\n", "\n")
 }
 
 func safeErrorString(err error) (message string) {
@@ -84,3 +161,54 @@ func safeErrorString(err error) (message string) {
 	}()
 	return err.Error()
 }
+
+// redactHandler scrubs secrets from the message and every string or error
+// attribute before the record is written.
+type redactHandler struct {
+	slog.Handler
+}
+
+func (h redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	clean := slog.NewRecord(record.Time, record.Level, Redact(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		clean.AddAttrs(redactAttr(attr))
+		return true
+	})
+	return h.Handler.Handle(ctx, clean)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clean := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		clean[i] = redactAttr(attr)
+	}
+	return redactHandler{h.Handler.WithAttrs(clean)}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{h.Handler.WithGroup(name)}
+}
+
+func redactAttr(attr slog.Attr) slog.Attr {
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		if sensitiveKey(attr.Key) {
+			return slog.String(attr.Key, redacted)
+		}
+		return slog.String(attr.Key, Redact(value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		clean := make([]any, len(group))
+		for i, inner := range group {
+			clean[i] = redactAttr(inner)
+		}
+		return slog.Group(attr.Key, clean...)
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return slog.String(attr.Key, Redact(safeErrorString(err)))
+		}
+		return slog.String(attr.Key, Redact(fmt.Sprint(value.Any())))
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	cases := map[string]string{
+		"Cookie: a=b; cf_clearance=xyz":                       "Cookie: <redacted>",
+		"com.xk72.webparts.csrf=abc123; letterboxd.user=jo":   "com.xk72.webparts.csrf=<redacted>; letterboxd.user=jo",
+		"__csrf=abc&filmId=12":                                "__csrf=<redacted>&filmId=12",
+		`{"csrf":"abc","result":false}`:                       `{"csrf":"<redacted>","result":false}`,
+		`<input type="hidden" name="__csrf" value="abc123">`:  `<input type="hidden" name="__csrf" value="<redacted>">`,
+		"request headers: X-Csrf-Token=tok; Referer=/film/x/": "request headers: X-Csrf-Token=<redacted>; Referer=/film/x/",
+		"unexpected status 500 for https://letterboxd.com/":   "unexpected status 500 for https://letterboxd.com/",
+	}
+	for in, want := range cases {
+		if got := Redact(in); got != want {
+			t.Fatalf("Redact(%q):\n got %q\nwant %q", in, got, want)
+		}
+	}
+}
+
+type idError struct{ error }
+
+func (idError) RequestID() string { return "ab12-0001" }
+
+func TestSetupWritesRedactedJSONAndRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := Setup(Options{Level: slog.LevelInfo, JSON: true, Path: path, MaxSize: 400, MaxFiles: 2}); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	t.Cleanup(func() {
+		mu.Lock()
+		closer.Close()
+		logger, closer = nil, nil
+		mu.Unlock()
+	})
+
+	Logger().Debug("hidden")
+	LogError("save", idError{errors.New("failed with cf_clearance=secret")})
+	Logger().Info("request", "Cookie", "a=b", slog.Group("req", "body", "__csrf=tok&x=1"))
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || strings.Contains(string(data), "secret") || strings.Contains(string(data), "tok") || strings.Contains(string(data), "a=b") {
+		t.Fatalf("expected two redacted lines without the debug one, got %s", data)
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("expected JSON, got %q: %v", lines[0], err)
+	}
+	if record["level"] != "ERROR" || record["msg"] != "save" || record["request_id"] != "ab12-0001" {
+		t.Fatalf("unexpected record %v", record)
+	}
+
+	for i := 0; i < 10; i++ {
+		Logger().Info("filler", "n", i)
+	}
+	if _, err := os.Stat(path + ".2"); err != nil {
+		t.Fatalf("expected rotated logs: %v", err)
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		t.Fatalf("expected at most two rotated logs")
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() > 400 {
+		t.Fatalf("expected the current log under the limit, got %v %v", info, err)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	if level, err := ParseLevel("DEBUG"); err != nil || level != slog.LevelDebug {
+		t.Fatalf("expected debug, got %v %v", level, err)
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Fatalf("expected an unknown level to fail")
+	}
+}
@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+)
+
+const redacted = "<redacted>"
+
+// sensitiveHeaders are headers whose whole value is a secret.
+var sensitiveHeaders = map[string]bool{
+	"cookie":        true,
+	"set-cookie":    true,
+	"authorization": true,
+	"x-csrf-token":  true,
+}
+
+// SensitiveHeader reports whether a header's value must not be shown.
+func SensitiveHeader(name string) bool {
+	return sensitiveHeaders[strings.ToLower(strings.TrimSpace(name))]
+}
+
+func sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	return SensitiveHeader(key) || key == "__csrf" || key == "csrf" || key == "cf_clearance"
+}
+
+// secretPatterns find secrets in free text: header lines, cookie pairs and
+// form fields, JSON fields and the hidden CSRF input in pages. The first
+// group is kept and the rest replaced.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?im)^(\s*(?:cookie|set-cookie|authorization|x-csrf-token)\s*[:=]\s*).+$`),
+	regexp.MustCompile(`(?i)((?:^|[\s;&?,"'(])(?:__csrf|csrf|cf_clearance|com\.xk72\.webparts\.csrf|x-csrf-token)=)[^\s;&,"')]+`),
+	regexp.MustCompile(`(?i)("(?:__csrf|csrf|cf_clearance|x-csrf-token)"\s*:\s*")[^"]*`),
+	regexp.MustCompile(`(?i)(name=["']?__csrf["']?\s+value=["']?)[^"'\s>]*`),
+}
+
+// Redact replaces cookie values and CSRF tokens in s with <redacted>.
+func Redact(s string) string {
+	for _, pattern := range secretPatterns {
+		s = pattern.ReplaceAllString(s, "${1}"+redacted)
+	}
+	return s
+}
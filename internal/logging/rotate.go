@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// rotatingFile appends to path and, once it would grow past maxSize, moves
+// it to path.1 (and path.1 to path.2, and so on), keeping maxFiles old logs.
+type rotatingFile struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+func (f *rotatingFile) rotate() error {
+	f.file.Close()
+	f.file = nil
+	os.Remove(rotatedName(f.path, f.maxFiles))
+	for n := f.maxFiles - 1; n >= 1; n-- {
+		os.Rename(rotatedName(f.path, n), rotatedName(f.path, n+1))
+	}
+	if err := os.Rename(f.path, rotatedName(f.path, 1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return f.open()
+}
+
+func rotatedName(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
@@ -0,0 +1,57 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// FromCommand runs cmdline through the shell and returns the first line it
+// prints, e.g. "pass show letterboxd/cookie" with the cookie on line one.
+// The command sees the account in LETTERBOXD_ACCOUNT, so one command can
+// serve several accounts.
+func FromCommand(cmdline, account string) (string, error) {
+	// Left on the terminal, so tools like pass can ask for their passphrase.
+	return runCommand(cmdline, account, os.Stdin)
+}
+
+// FromCommandNoInput is FromCommand for when the app holds the terminal: a
+// command that needs to prompt fails rather than fight it for input.
+func FromCommandNoInput(cmdline, account string) (string, error) {
+	return runCommand(cmdline, account, nil)
+}
+
+func runCommand(cmdline, account string, stdin io.Reader) (string, error) {
+	cmdline = strings.TrimSpace(cmdline)
+	if cmdline == "" {
+		return "", errors.New("empty cookie command")
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", cmdline)
+	} else {
+		cmd = exec.Command("sh", "-c", cmdline)
+	}
+	cmd.Env = append(os.Environ(), "LETTERBOXD_ACCOUNT="+fileKey(account))
+	cmd.Stdin = stdin
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("cookie command: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("cookie command: %w", err)
+	}
+	out, _, _ := strings.Cut(stdout.String(), "\n")
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return "", errors.New("cookie command printed nothing")
+	}
+	return out, nil
+}
@@ -0,0 +1,197 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const fileVersion = 1
+
+// scryptN is the scrypt cost for new files, around 100ms and 32MB. Files
+// record their own parameters, so raising it later keeps old files readable.
+var scryptN = 1 << 15
+
+// File is a Store kept in one file, sealed with AES-256-GCM under a key
+// derived from a passphrase with scrypt. Every write re-encrypts the whole
+// file with a fresh nonce.
+type File struct {
+	mu      sync.Mutex
+	path    string
+	header  fileHeader
+	key     []byte
+	secrets map[string]string
+}
+
+type fileHeader struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	N       int    `json:"n"`
+	R       int    `json:"r"`
+	P       int    `json:"p"`
+	Salt    []byte `json:"salt"`
+}
+
+type fileData struct {
+	fileHeader
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+// FileExists reports whether there is a secrets file at path, so the caller
+// knows whether a passphrase is being set or checked.
+func FileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// OpenFile unlocks the secrets file at path, or starts an empty one that is
+// written on the first Set.
+func OpenFile(path, passphrase string) (*File, error) {
+	if passphrase == "" {
+		return nil, errors.New("secrets file needs a passphrase")
+	}
+	f := &File{path: path, secrets: map[string]string{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		f.header = fileHeader{Version: fileVersion, KDF: "scrypt", N: scryptN, R: 8, P: 1, Salt: salt}
+		if f.key, err = f.header.derive(passphrase); err != nil {
+			return nil, err
+		}
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var sealed fileData
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if sealed.Version != fileVersion || sealed.KDF != "scrypt" {
+		return nil, fmt.Errorf("read %s: unsupported version %d (%s)", path, sealed.Version, sealed.KDF)
+	}
+	f.header = sealed.fileHeader
+	if f.key, err = f.header.derive(passphrase); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(f.key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, sealed.Nonce, sealed.Data, nil)
+	if err != nil {
+		return nil, ErrPassphrase
+	}
+	if err := json.Unmarshal(plain, &f.secrets); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return f, nil
+}
+
+func (h fileHeader) derive(passphrase string) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), h.Salt, h.N, h.R, h.P, 32)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (f *File) Get(account string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	secret, ok := f.secrets[fileKey(account)]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (f *File) Set(account, secret string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.secrets[fileKey(account)] = secret
+	return f.save()
+}
+
+func (f *File) Delete(account string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := fileKey(account)
+	if _, ok := f.secrets[key]; !ok {
+		return nil
+	}
+	delete(f.secrets, key)
+	return f.save()
+}
+
+// fileKey matches account names the way the config does, ignoring case.
+func fileKey(account string) string {
+	account = strings.ToLower(strings.TrimSpace(account))
+	if account == "" {
+		return "default"
+	}
+	return account
+}
+
+func (f *File) save() error {
+	plain, err := json.Marshal(f.secrets)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(f.key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(fileData{
+		fileHeader: f.header,
+		Nonce:      nonce,
+		Data:       gcm.Seal(nil, nonce, plain, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(f.path, append(data, '\n'))
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".secrets-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,138 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const keyringService = "letterboxd-tui"
+
+// Keyring is a Store in the OS keyring, reached through its command-line
+// tool: security on macOS and secret-tool (libsecret) on Linux. Secrets go
+// to the tools on stdin, never in their arguments.
+type Keyring struct {
+	tool string
+	run  func(stdin string, name string, args ...string) (string, error)
+}
+
+// NewKeyring finds the keyring tool for this OS, reporting false when there
+// isn't one.
+func NewKeyring() (*Keyring, bool) {
+	tool := ""
+	switch runtime.GOOS {
+	case "darwin":
+		tool = "security"
+	case "linux", "freebsd", "openbsd", "netbsd":
+		tool = "secret-tool"
+	default:
+		return nil, false
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return nil, false
+	}
+	return &Keyring{tool: tool, run: runTool}, true
+}
+
+// KeyringAvailable reports whether NewKeyring would find a keyring.
+func KeyringAvailable() bool {
+	_, ok := NewKeyring()
+	return ok
+}
+
+// exitError carries a tool's exit code so lookups can tell "not found"
+// from a locked or missing keyring.
+type exitError struct {
+	code int
+	msg  string
+}
+
+func (e *exitError) Error() string {
+	return e.msg
+}
+
+func runTool(stdin string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			return "", &exitError{code: exit.ExitCode(), msg: name + ": " + msg}
+		}
+		return "", fmt.Errorf("%s: %s", name, msg)
+	}
+	return stdout.String(), nil
+}
+
+func (k *Keyring) Get(account string) (string, error) {
+	var out string
+	var err error
+	if k.tool == "security" {
+		out, err = k.run("", k.tool, "find-generic-password", "-s", keyringService, "-a", fileKey(account), "-w")
+	} else {
+		out, err = k.run("", k.tool, "lookup", "service", keyringService, "account", fileKey(account))
+	}
+	var exit *exitError
+	if errors.As(err, &exit) && k.notFound(exit) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	out = strings.TrimRight(out, "\r\n")
+	if out == "" {
+		return "", ErrNotFound
+	}
+	return out, nil
+}
+
+// notFound tells a missing item from other failures: security exits 44,
+// while secret-tool exits 1 with nothing on stderr.
+func (k *Keyring) notFound(exit *exitError) bool {
+	if k.tool == "security" {
+		return exit.code == 44
+	}
+	return exit.msg == k.tool+": exit status 1"
+}
+
+func (k *Keyring) Set(account, secret string) error {
+	if strings.ContainsAny(secret, "\r\n") {
+		return errors.New("secret must be a single line")
+	}
+	if k.tool == "security" {
+		// security -i reads commands from stdin, which keeps the cookie out
+		// of the process list.
+		if strings.ContainsAny(secret+account, `"\`) {
+			return errors.New(`keyring entries can't contain '"' or '\'`)
+		}
+		line := fmt.Sprintf("add-generic-password -U -s %s -a \"%s\" -l \"Letterboxd TUI cookie\" -w \"%s\"\n", keyringService, fileKey(account), secret)
+		_, err := k.run(line, k.tool, "-i")
+		return err
+	}
+	_, err := k.run(secret, k.tool, "store", "--label=Letterboxd TUI cookie ("+fileKey(account)+")", "service", keyringService, "account", fileKey(account))
+	return err
+}
+
+func (k *Keyring) Delete(account string) error {
+	var err error
+	if k.tool == "security" {
+		_, err = k.run("", k.tool, "delete-generic-password", "-s", keyringService, "-a", fileKey(account))
+	} else {
+		_, err = k.run("", k.tool, "clear", "service", keyringService, "account", fileKey(account))
+	}
+	var exit *exitError
+	if errors.As(err, &exit) && k.notFound(exit) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+// Package secrets keeps account cookies out of the plaintext config file,
+// either in a passphrase-encrypted file beside it or in the OS keyring.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/solean/letterboxd-tui/internal/config"
+)
+
+// Backends, as named by the config's cookie_store.
+const (
+	BackendConfig  = "config"
+	BackendFile    = "file"
+	BackendKeyring = "keyring"
+)
+
+var (
+	ErrNotFound   = errors.New("secret not found")
+	ErrPassphrase = errors.New("wrong passphrase for the secrets file")
+)
+
+// Store holds one cookie per account name.
+type Store interface {
+	Get(account string) (string, error)
+	Set(account, secret string) error
+	Delete(account string) error
+}
+
+// ParseBackend checks a cookie_store value. Empty means the config file.
+func ParseBackend(name string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", BackendConfig:
+		return BackendConfig, nil
+	case BackendFile:
+		return BackendFile, nil
+	case BackendKeyring:
+		return BackendKeyring, nil
+	}
+	return "", fmt.Errorf("unknown cookie store %q (want config, file or keyring)", name)
+}
+
+// Migrate moves every cookie still in cfg into store and blanks it in cfg,
+// returning how many moved. The caller saves cfg. A cookie that fails to
+// move stays where it was.
+func Migrate(cfg *config.Config, store Store) (int, error) {
+	moved := 0
+	for _, account := range cfg.AccountList() {
+		cookie := strings.TrimSpace(account.Cookie)
+		if cookie == "" {
+			continue
+		}
+		if err := store.Set(account.Name, cookie); err != nil {
+			return moved, fmt.Errorf("move cookie for %s: %w", account.Name, err)
+		}
+		account.Cookie = ""
+		cfg.PutAccount(account)
+		moved++
+	}
+	return moved, nil
+}
+
+// Lookup reads an account's cookie from store, treating a missing one as
+// empty.
+func Lookup(store Store, account string) (string, error) {
+	secret, err := store.Get(account)
+	if errors.Is(err, ErrNotFound) {
+		return "", nil
+	}
+	return strings.TrimSpace(secret), err
+}
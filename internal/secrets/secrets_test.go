@@ -0,0 +1,154 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/solean/letterboxd-tui/internal/config"
+)
+
+func init() {
+	// Keep the tests fast; the cost is read back from the file anyway.
+	scryptN = 1 << 10
+}
+
+func TestFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	f, err := OpenFile(path, "hunter2")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, err := f.Get("default"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if FileExists(path) {
+		t.Fatalf("expected nothing written before the first Set")
+	}
+	if err := f.Set("Club", "a=b; com.xk72.webparts.csrf=x"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if strings.Contains(string(data), "csrf") {
+		t.Fatalf("expected the cookie to be encrypted, got %s", data)
+	}
+
+	again, err := OpenFile(path, "hunter2")
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got, err := again.Get("club"); err != nil || got != "a=b; com.xk72.webparts.csrf=x" {
+		t.Fatalf("unexpected secret %q %v", got, err)
+	}
+	if err := again.Delete("club"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := again.Get("club"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected deleted secret to be gone, got %v", err)
+	}
+
+	if _, err := OpenFile(path, "wrong"); !errors.Is(err, ErrPassphrase) {
+		t.Fatalf("expected wrong passphrase error, got %v", err)
+	}
+}
+
+type memStore map[string]string
+
+func (m memStore) Get(account string) (string, error) {
+	if secret, ok := m[account]; ok {
+		return secret, nil
+	}
+	return "", ErrNotFound
+}
+
+func (m memStore) Set(account, secret string) error {
+	m[account] = secret
+	return nil
+}
+
+func (m memStore) Delete(account string) error {
+	delete(m, account)
+	return nil
+}
+
+func TestMigrateMovesCookiesOutOfConfig(t *testing.T) {
+	cfg := config.Config{
+		Username: "jo",
+		Cookie:   "a=b",
+		Accounts: []config.Account{{Name: "club", Username: "filmclub", Cookie: "c=d"}, {Name: "empty", Username: "x"}},
+	}
+	store := memStore{}
+	moved, err := Migrate(&cfg, store)
+	if err != nil || moved != 2 {
+		t.Fatalf("expected two cookies moved, got %d %v", moved, err)
+	}
+	if cfg.Cookie != "" || cfg.Accounts[0].Cookie != "" || cfg.Username != "jo" || cfg.Accounts[0].Username != "filmclub" {
+		t.Fatalf("expected cookies blanked and usernames kept, got %+v", cfg)
+	}
+	if store["default"] != "a=b" || store["club"] != "c=d" {
+		t.Fatalf("unexpected store %v", store)
+	}
+	if cookie, err := Lookup(store, "empty"); cookie != "" || err != nil {
+		t.Fatalf("expected a missing cookie to be empty, got %q %v", cookie, err)
+	}
+}
+
+func TestFromCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	got, err := FromCommand("printf '%s=b; c=d\\nsecond line\\n' \"$LETTERBOXD_ACCOUNT\"", "Club")
+	if err != nil || got != "club=b; c=d" {
+		t.Fatalf("expected the first line, got %q %v", got, err)
+	}
+	if _, err := FromCommand("echo nope >&2; exit 3", ""); err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Fatalf("expected the command's error, got %v", err)
+	}
+	if _, err := FromCommand("true", ""); err == nil {
+		t.Fatalf("expected empty output to fail")
+	}
+	if got, err := FromCommandNoInput("read line && echo \"$line\"; echo prompted", ""); err != nil || got != "prompted" {
+		t.Fatalf("expected the command to get no input, got %q %v", got, err)
+	}
+}
+
+func TestKeyringCommands(t *testing.T) {
+	var calls []string
+	stored := ""
+	k := &Keyring{tool: "secret-tool", run: func(stdin, name string, args ...string) (string, error) {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		switch args[0] {
+		case "store":
+			stored = stdin
+		case "lookup":
+			if stored == "" {
+				return "", &exitError{code: 1, msg: "secret-tool: exit status 1"}
+			}
+			return stored + "\n", nil
+		}
+		return "", nil
+	}}
+	if _, err := k.Get("Club"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if err := k.Set("Club", "a=b"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if got, err := k.Get("club"); err != nil || got != "a=b" {
+		t.Fatalf("unexpected secret %q %v", got, err)
+	}
+	for _, call := range calls {
+		if strings.Contains(call, "a=b") {
+			t.Fatalf("expected the secret to stay out of the arguments: %s", call)
+		}
+	}
+	if !strings.Contains(calls[1], "service letterboxd-tui account club") {
+		t.Fatalf("unexpected store call %q", calls[1])
+	}
+}
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Posters caches downloaded poster images on disk, one file per URL. Poster
+// URLs change when Letterboxd updates an image, so entries never go stale.
+type Posters struct {
+	dir string
+}
+
+// PostersDir is under the user cache directory, since everything in it can
+// be downloaded again.
+func PostersDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, appDir, "posters"), nil
+}
+
+func OpenPosters() (*Posters, error) {
+	dir, err := PostersDir()
+	if err != nil {
+		return nil, err
+	}
+	return OpenPostersDir(dir), nil
+}
+
+func OpenPostersDir(dir string) *Posters {
+	return &Posters{dir: dir}
+}
+
+func (p *Posters) path(posterURL string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(posterURL)))
+	return filepath.Join(p.dir, hex.EncodeToString(sum[:16]))
+}
+
+// Get returns the cached image for posterURL, if there is one.
+func (p *Posters) Get(posterURL string) ([]byte, bool) {
+	data, err := os.ReadFile(p.path(posterURL))
+	if err != nil || len(data) == 0 {
+		return nil, false
+	}
+	return data, true
+}
+
+func (p *Posters) Put(posterURL string, data []byte) error {
+	return writeFile(p.path(posterURL), data)
+}
@@ -0,0 +1,25 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPostersRoundTrip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "posters")
+	posters := OpenPostersDir(dir)
+	url := "https://a.ltrbxd.com/resized/film-poster/heat.jpg"
+	if _, ok := posters.Get(url); ok {
+		t.Fatalf("expected empty cache")
+	}
+	if err := posters.Put(url, []byte("jpeg")); err != nil {
+		t.Fatalf("Put error: %v", err)
+	}
+	data, ok := OpenPostersDir(dir).Get(url)
+	if !ok || string(data) != "jpeg" {
+		t.Fatalf("unexpected cached poster: %q %v", data, ok)
+	}
+	if _, ok := posters.Get(url + "?v=2"); ok {
+		t.Fatalf("expected a different URL to miss")
+	}
+}
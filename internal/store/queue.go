@@ -0,0 +1,241 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+type OpKind string
+
+const (
+	OpDiaryEntry OpKind = "diary_entry"
+	OpWatchlist  OpKind = "watchlist"
+)
+
+// Op is a write that could not reach Letterboxd and waits to be replayed.
+type Op struct {
+	ID          string                        `json:"id"`
+	Kind        OpKind                        `json:"kind"`
+	Title       string                        `json:"title"`
+	FilmURL     string                        `json:"film_url"`
+	Diary       *letterboxd.DiaryEntryRequest `json:"diary,omitempty"`
+	Watchlist   *letterboxd.WatchlistRequest  `json:"watchlist,omitempty"`
+	InWatchlist bool                          `json:"in_watchlist,omitempty"`
+	Queued      time.Time                     `json:"queued"`
+	Attempts    int                           `json:"attempts"`
+	LastError   string                        `json:"last_error,omitempty"`
+}
+
+func NewDiaryOp(title, filmURL string, req letterboxd.DiaryEntryRequest) Op {
+	return Op{Kind: OpDiaryEntry, Title: title, FilmURL: filmURL, Diary: &req}
+}
+
+func NewWatchlistOp(title, filmURL string, req letterboxd.WatchlistRequest, inWatchlist bool) Op {
+	return Op{Kind: OpWatchlist, Title: title, FilmURL: filmURL, Watchlist: &req, InWatchlist: inWatchlist}
+}
+
+// Key identifies what an op changes: one diary entry per film and day, or
+// the watchlist state of one film. Ops with the same key are duplicates.
+func (op Op) Key() string {
+	film := letterboxd.NormalizeFilmURL(op.FilmURL)
+	if op.Kind == OpDiaryEntry && op.Diary != nil {
+		return string(op.Kind) + "|" + film + "|" + op.Diary.WatchedDate
+	}
+	return string(op.Kind) + "|" + film
+}
+
+type queueFile struct {
+	NextID int  `json:"next_id"`
+	Ops    []Op `json:"ops"`
+}
+
+type Queue struct {
+	path string
+	mu   sync.Mutex
+	data queueFile
+}
+
+func QueuePath(username string) (string, error) {
+	return userFile("queue", username)
+}
+
+func OpenQueue(username string) (*Queue, error) {
+	path, err := QueuePath(username)
+	if err != nil {
+		return nil, err
+	}
+	return OpenQueueFile(path)
+}
+
+func OpenQueueFile(path string) (*Queue, error) {
+	q := &Queue{path: path}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &q.data); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (q *Queue) Ops() []Op {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]Op(nil), q.data.Ops...)
+}
+
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.data.Ops)
+}
+
+// Add queues op, replacing a pending op with the same key so a retried form
+// or a toggled watchlist button is only replayed once, as its latest version.
+func (q *Queue) Add(op Op) Op {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if op.Queued.IsZero() {
+		op.Queued = time.Now()
+	}
+	for i, pending := range q.data.Ops {
+		if pending.ID == op.ID || pending.Key() == op.Key() {
+			op.ID = pending.ID
+			q.data.Ops[i] = op
+			return op
+		}
+	}
+	q.data.NextID++
+	op.ID = strconv.Itoa(q.data.NextID)
+	q.data.Ops = append(q.data.Ops, op)
+	return op
+}
+
+// replace updates a pending op in place, leaving the queue untouched if it
+// was discarded meanwhile.
+func (q *Queue) replace(op Op) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, pending := range q.data.Ops {
+		if pending.ID == op.ID {
+			q.data.Ops[i] = op
+			return
+		}
+	}
+}
+
+func (q *Queue) Remove(id string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, op := range q.data.Ops {
+		if op.ID == id {
+			q.data.Ops = append(q.data.Ops[:i], q.data.Ops[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (q *Queue) Save() error {
+	q.mu.Lock()
+	data, err := json.Marshal(q.data)
+	q.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return writeFile(q.path, data)
+}
+
+type ReplayResult struct {
+	Sent int
+	// Skipped counts ops Letterboxd already reflects, e.g. a diary entry
+	// whose first attempt went through before the connection dropped.
+	Skipped int
+}
+
+// Replay sends queued ops in order, dropping any that are already applied.
+// It stops at the first transient failure and keeps ops that fail for other
+// reasons so they can be edited or discarded. The queue is saved either way.
+func Replay(client *letterboxd.Client, q *Queue, username string) (ReplayResult, error) {
+	var result ReplayResult
+	var firstErr error
+	for _, op := range q.Ops() {
+		done, err := applied(client, username, op)
+		if err == nil && !done {
+			err = send(client, op)
+		}
+		if err != nil {
+			op.Attempts++
+			op.LastError = err.Error()
+			q.replace(op)
+			if firstErr == nil {
+				firstErr = err
+			}
+			if letterboxd.IsTransient(err) {
+				break
+			}
+			continue
+		}
+		q.Remove(op.ID)
+		if done {
+			result.Skipped++
+		} else {
+			result.Sent++
+		}
+	}
+	if err := q.Save(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return result, firstErr
+}
+
+func applied(client *letterboxd.Client, username string, op Op) (bool, error) {
+	film := letterboxd.NormalizeFilmURL(op.FilmURL)
+	switch op.Kind {
+	case OpDiaryEntry:
+		if op.Diary == nil {
+			return false, errors.New("queued diary entry has no request")
+		}
+		day, err := time.Parse(time.DateOnly, op.Diary.WatchedDate)
+		if err != nil {
+			return false, err
+		}
+		entries, err := client.DiaryRange(username, day, day)
+		if err != nil {
+			return false, err
+		}
+		for _, entry := range entries {
+			if letterboxd.NormalizeFilmURL(entry.FilmURL) == film {
+				return true, nil
+			}
+		}
+		return false, nil
+	case OpWatchlist:
+		current, err := client.Film(op.FilmURL, username)
+		if err != nil {
+			return false, err
+		}
+		return current.WatchlistOK && current.InWatchlist == op.InWatchlist, nil
+	}
+	return false, errors.New("unknown queued operation " + string(op.Kind))
+}
+
+func send(client *letterboxd.Client, op Op) error {
+	switch {
+	case op.Kind == OpDiaryEntry && op.Diary != nil:
+		return client.SaveDiaryEntry(*op.Diary)
+	case op.Kind == OpWatchlist && op.Watchlist != nil:
+		return client.SetWatchlist(*op.Watchlist, op.InWatchlist)
+	}
+	return errors.New("queued " + string(op.Kind) + " has no request")
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+func TestQueueAddReplacesDuplicates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue", "jane.json")
+	q, err := OpenQueueFile(path)
+	if err != nil {
+		t.Fatalf("OpenQueueFile error: %v", err)
+	}
+	first := q.Add(NewDiaryOp("Heat", "/film/heat/", letterboxd.DiaryEntryRequest{ViewingUID: "u1", WatchedDate: "2024-03-10", RatingValue: 6}))
+	again := q.Add(NewDiaryOp("Heat", "https://letterboxd.com/film/heat/", letterboxd.DiaryEntryRequest{ViewingUID: "u1", WatchedDate: "2024-03-10", RatingValue: 8}))
+	q.Add(NewWatchlistOp("Heat", "/film/heat/", letterboxd.WatchlistRequest{FilmSlug: "heat"}, true))
+	q.Add(NewWatchlistOp("Heat", "/film/heat/", letterboxd.WatchlistRequest{FilmSlug: "heat"}, false))
+	if again.ID != first.ID || q.Len() != 2 {
+		t.Fatalf("expected duplicates to be replaced, got %+v", q.Ops())
+	}
+	if err := q.Save(); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	reopened, err := OpenQueueFile(path)
+	if err != nil {
+		t.Fatalf("reopen error: %v", err)
+	}
+	ops := reopened.Ops()
+	if len(ops) != 2 || ops[0].Diary.RatingValue != 8 || ops[1].InWatchlist {
+		t.Fatalf("unexpected ops after reopen: %+v", ops)
+	}
+	if added := reopened.Add(NewDiaryOp("Alien", "/film/alien/", letterboxd.DiaryEntryRequest{WatchedDate: "2024-03-10"})); added.ID == first.ID {
+		t.Fatalf("expected a fresh id, got %q", added.ID)
+	}
+	if !reopened.Remove(first.ID) || reopened.Len() != 2 {
+		t.Fatalf("expected remove to drop the diary op")
+	}
+}
+
+func TestReplaySkipsAppliedAndSendsPending(t *testing.T) {
+	q, err := OpenQueueFile(filepath.Join(t.TempDir(), "jane.json"))
+	if err != nil {
+		t.Fatalf("OpenQueueFile error: %v", err)
+	}
+	q.Add(NewDiaryOp("Film 10", "/film/f10/", letterboxd.DiaryEntryRequest{ViewingUID: "u10", WatchedDate: "2024-03-10"}))
+	q.Add(NewWatchlistOp("Heat", "https://letterboxd.com/film/heat/", letterboxd.WatchlistRequest{WatchlistID: "lid1"}, true))
+
+	var paths []string
+	pages := map[string]string{
+		"/jane/diary/for/2024/03/": diaryRows(10),
+		"/film/heat/":              `<meta property="og:title" content="Heat (1995)">`,
+		"/film/heat/json":          `{"inWatchlist": false}`,
+	}
+	result, err := Replay(newTestClient(pages, &paths), q, "jane")
+	if err != nil {
+		t.Fatalf("Replay error: %v", err)
+	}
+	if result != (ReplayResult{Sent: 1, Skipped: 1}) || q.Len() != 0 {
+		t.Fatalf("unexpected replay: %+v, %d left", result, q.Len())
+	}
+	if last := paths[len(paths)-1]; last != "/api/v0/me/watchlist/lid1" {
+		t.Fatalf("expected watchlist write last, got %v", paths)
+	}
+}
+
+func TestReplayStopsWhenOffline(t *testing.T) {
+	q, err := OpenQueueFile(filepath.Join(t.TempDir(), "jane.json"))
+	if err != nil {
+		t.Fatalf("OpenQueueFile error: %v", err)
+	}
+	q.Add(NewWatchlistOp("Heat", "/film/heat/", letterboxd.WatchlistRequest{WatchlistID: "lid1"}, true))
+	q.Add(NewWatchlistOp("Alien", "/film/alien/", letterboxd.WatchlistRequest{WatchlistID: "lid2"}, true))
+	client := letterboxd.NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("network is unreachable")
+	})}, "com.xk72.webparts.csrf=csrf123")
+	if _, err := Replay(client, q, "jane"); !letterboxd.IsTransient(err) {
+		t.Fatalf("expected transient error, got %v", err)
+	}
+	ops := q.Ops()
+	if len(ops) != 2 || ops[0].Attempts != 1 || ops[0].LastError == "" || ops[1].Attempts != 0 {
+		t.Fatalf("expected replay to stop at the first op, got %+v", ops)
+	}
+
+	q, _ = OpenQueueFile(filepath.Join(t.TempDir(), "other.json"))
+	q.Add(NewWatchlistOp("Heat", "/film/heat/", letterboxd.WatchlistRequest{WatchlistID: "lid1"}, true))
+	q.Add(NewWatchlistOp("Alien", "/film/alien/", letterboxd.WatchlistRequest{WatchlistID: "lid2"}, true))
+	client = letterboxd.NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header)}, nil
+	})}, "")
+	if _, err := Replay(client, q, "jane"); err == nil || letterboxd.IsTransient(err) {
+		t.Fatalf("expected a permanent error, got %v", err)
+	}
+	if ops := q.Ops(); len(ops) != 2 || ops[1].Attempts != 1 {
+		t.Fatalf("expected replay to continue past permanent failures, got %+v", ops)
+	}
+}
@@ -0,0 +1,302 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+const (
+	appDir  = "letterboxd-tui"
+	version = 1
+)
+
+// Data is everything kept locally for one member. Each *Mark is the key of
+// the newest entry when that list was last synced to the end; a later sync
+// reads down to it and keeps what is older, so it is only set once everything
+// older is stored.
+type Data struct {
+	Version         int                        `json:"version"`
+	Username        string                     `json:"username"`
+	Diary           []letterboxd.DiaryEntry    `json:"diary"`
+	DiaryMark       string                     `json:"diary_mark"`
+	DiarySynced     time.Time                  `json:"diary_synced"`
+	Watchlist       []letterboxd.WatchlistItem `json:"watchlist"`
+	WatchlistMark   string                     `json:"watchlist_mark"`
+	WatchlistSynced time.Time                  `json:"watchlist_synced"`
+	Films           []letterboxd.UserFilm      `json:"films"`
+	FilmsMark       string                     `json:"films_mark"`
+	FilmsSynced     time.Time                  `json:"films_synced"`
+	Lists           []List                     `json:"lists"`
+	ListsSynced     time.Time                  `json:"lists_synced"`
+	Metadata        map[string]letterboxd.Film `json:"metadata"`
+}
+
+// List is one of the member's lists with the films on it.
+type List struct {
+	letterboxd.List
+	Films []letterboxd.UserFilm `json:"films"`
+}
+
+type Store struct {
+	path string
+	mu   sync.Mutex
+	data Data
+}
+
+// Dir is the per-user data directory: $XDG_DATA_HOME, ~/.local/share on
+// Linux, and the config dir elsewhere.
+func Dir() (string, error) {
+	if dir := strings.TrimSpace(os.Getenv("XDG_DATA_HOME")); dir != "" {
+		return filepath.Join(dir, appDir), nil
+	}
+	if runtime.GOOS == "linux" {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, ".local", "share", appDir), nil
+		}
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, appDir), nil
+}
+
+func Path(username string) (string, error) {
+	return userFile("store", username)
+}
+
+func userFile(kind, username string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, kind, strings.ToLower(strings.TrimSpace(username))+".json"), nil
+}
+
+func Open(username string) (*Store, error) {
+	path, err := Path(username)
+	if err != nil {
+		return nil, err
+	}
+	return OpenFile(path, username)
+}
+
+func OpenFile(path, username string) (*Store, error) {
+	s := &Store{path: path, data: Data{Version: version, Username: username}}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var data Data
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	if data.Version == version && strings.EqualFold(data.Username, username) {
+		s.data = data
+	}
+	return s, nil
+}
+
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Snapshot returns a copy of the stored data that is safe to use while a
+// sync is running.
+func (s *Store) Snapshot() Data {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data := s.data
+	data.Diary = append([]letterboxd.DiaryEntry(nil), s.data.Diary...)
+	data.Watchlist = append([]letterboxd.WatchlistItem(nil), s.data.Watchlist...)
+	data.Films = append([]letterboxd.UserFilm(nil), s.data.Films...)
+	data.Lists = append([]List(nil), s.data.Lists...)
+	data.Metadata = nil
+	return data
+}
+
+func (s *Store) Film(filmURL string) (letterboxd.Film, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	film, ok := s.data.Metadata[letterboxd.NormalizeFilmURL(filmURL)]
+	return film, ok
+}
+
+func (s *Store) PutFilm(film letterboxd.Film) {
+	key := letterboxd.NormalizeFilmURL(film.URL)
+	if key == "" || film.Title == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Metadata == nil {
+		s.data.Metadata = map[string]letterboxd.Film{}
+	}
+	s.data.Metadata[key] = film
+}
+
+// MergeDiary adds entries that are not stored yet and keeps the diary
+// ordered newest first. It returns how many entries were added. Merged
+// entries never move a sync mark, so a later sync still reads past them.
+func (s *Store) MergeDiary(entries []letterboxd.DiaryEntry) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	known := make(map[string]struct{}, len(s.data.Diary))
+	for _, entry := range s.data.Diary {
+		known[DiaryKey(entry)] = struct{}{}
+	}
+	added := 0
+	for _, entry := range entries {
+		key := DiaryKey(entry)
+		if _, ok := known[key]; ok {
+			continue
+		}
+		known[key] = struct{}{}
+		s.data.Diary = append(s.data.Diary, entry)
+		added++
+	}
+	if added > 0 {
+		sort.SliceStable(s.data.Diary, func(i, j int) bool {
+			return s.data.Diary[i].Date.After(s.data.Diary[j].Date)
+		})
+	}
+	return added
+}
+
+// MergeWatchlist puts unknown items ahead of the stored ones, matching the
+// watchlist's newest-added-first order.
+func (s *Store) MergeWatchlist(items []letterboxd.WatchlistItem) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	known := make(map[string]struct{}, len(s.data.Watchlist))
+	for _, item := range s.data.Watchlist {
+		known[letterboxd.NormalizeFilmURL(item.FilmURL)] = struct{}{}
+	}
+	var fresh []letterboxd.WatchlistItem
+	for _, item := range items {
+		key := letterboxd.NormalizeFilmURL(item.FilmURL)
+		if _, ok := known[key]; ok {
+			continue
+		}
+		known[key] = struct{}{}
+		fresh = append(fresh, item)
+	}
+	s.data.Watchlist = append(fresh, s.data.Watchlist...)
+	return len(fresh)
+}
+
+// MergeFilms adds unknown films first and refreshes the rating, like and
+// review flags of films already stored.
+func (s *Store) MergeFilms(films []letterboxd.UserFilm) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index := make(map[string]int, len(s.data.Films))
+	for i, film := range s.data.Films {
+		index[letterboxd.NormalizeFilmURL(film.FilmURL)] = i
+	}
+	var fresh []letterboxd.UserFilm
+	for _, film := range films {
+		key := letterboxd.NormalizeFilmURL(film.FilmURL)
+		if i, ok := index[key]; ok {
+			if i >= 0 {
+				s.data.Films[i] = film
+			}
+			continue
+		}
+		index[key] = -1
+		fresh = append(fresh, film)
+	}
+	s.data.Films = append(fresh, s.data.Films...)
+	return len(fresh)
+}
+
+func (s *Store) Save() error {
+	s.mu.Lock()
+	data, err := json.Marshal(s.data)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return writeFile(s.path, data)
+}
+
+// writeFile replaces path atomically so a crash mid-write never leaves a
+// truncated file behind.
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".store-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Search matches query against the titles of everything stored, returning
+// each film once: watched films first, then diary-only and watchlist films.
+func (s *Store) Search(query string, limit int) []letterboxd.SearchResult {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var results []letterboxd.SearchResult
+	seen := map[string]struct{}{}
+	add := func(title, year, filmURL string) bool {
+		key := letterboxd.NormalizeFilmURL(filmURL)
+		if key == "" || !strings.Contains(strings.ToLower(title), query) {
+			return true
+		}
+		if _, ok := seen[key]; ok {
+			return true
+		}
+		seen[key] = struct{}{}
+		results = append(results, letterboxd.SearchResult{Title: title, Year: year, FilmURL: key, Slug: letterboxd.FilmSlug(key)})
+		return limit <= 0 || len(results) < limit
+	}
+	for _, film := range s.data.Films {
+		if !add(film.Title, film.Year, film.FilmURL) {
+			return results
+		}
+	}
+	for _, entry := range s.data.Diary {
+		if !add(entry.Title, "", entry.FilmURL) {
+			return results
+		}
+	}
+	for _, item := range s.data.Watchlist {
+		if !add(item.Title, item.Year, item.FilmURL) {
+			return results
+		}
+	}
+	return results
+}
+
+func DiaryKey(entry letterboxd.DiaryEntry) string {
+	return letterboxd.NormalizeFilmURL(entry.FilmURL) + "|" + entry.Date.Format(time.DateOnly)
+}
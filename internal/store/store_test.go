@@ -0,0 +1,95 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestStoreSaveAndReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store", "jane.json")
+	s, err := OpenFile(path, "jane")
+	if err != nil {
+		t.Fatalf("OpenFile error: %v", err)
+	}
+	s.MergeDiary([]letterboxd.DiaryEntry{{Date: day(2024, time.January, 2), Title: "Heat", FilmURL: letterboxd.BaseURL + "/film/heat/"}})
+	s.PutFilm(letterboxd.Film{Title: "Heat", URL: letterboxd.BaseURL + "/film/heat/", Runtime: "170 mins"})
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("expected private store file, got %v %v", info, err)
+	}
+
+	reopened, err := OpenFile(path, "Jane")
+	if err != nil {
+		t.Fatalf("reopen error: %v", err)
+	}
+	snap := reopened.Snapshot()
+	if len(snap.Diary) != 1 || !snap.Diary[0].Date.Equal(day(2024, time.January, 2)) {
+		t.Fatalf("unexpected diary after reopen: %+v", snap.Diary)
+	}
+	if film, ok := reopened.Film("https://letterboxd.com/film/heat"); !ok || film.Runtime != "170 mins" {
+		t.Fatalf("expected film metadata after reopen, got %+v", film)
+	}
+
+	other, err := OpenFile(path, "bob")
+	if err != nil {
+		t.Fatalf("open for other user error: %v", err)
+	}
+	if len(other.Snapshot().Diary) != 0 {
+		t.Fatalf("expected another member's store to start empty")
+	}
+}
+
+func TestMergeDiaryKeepsNewestFirst(t *testing.T) {
+	s := &Store{}
+	s.MergeDiary([]letterboxd.DiaryEntry{{Date: day(2024, time.January, 1), FilmURL: "/film/a/"}})
+	added := s.MergeDiary([]letterboxd.DiaryEntry{
+		{Date: day(2024, time.March, 1), FilmURL: "/film/b/"},
+		{Date: day(2024, time.January, 1), FilmURL: "/film/a/"},
+	})
+	snap := s.Snapshot()
+	if added != 1 || len(snap.Diary) != 2 || snap.Diary[0].FilmURL != "/film/b/" {
+		t.Fatalf("unexpected merge: added=%d diary=%+v", added, snap.Diary)
+	}
+}
+
+func TestMergeFilmsRefreshesRatings(t *testing.T) {
+	s := &Store{}
+	s.MergeFilms([]letterboxd.UserFilm{{Title: "Heat", FilmURL: "/film/heat/", Rating: "★★★"}})
+	added := s.MergeFilms([]letterboxd.UserFilm{
+		{Title: "Alien", FilmURL: "/film/alien/"},
+		{Title: "Alien", FilmURL: "/film/alien/"},
+		{Title: "Heat", FilmURL: "/film/heat/", Rating: "★★★★"},
+	})
+	films := s.Snapshot().Films
+	if added != 1 || len(films) != 2 || films[0].Title != "Alien" || films[1].Rating != "★★★★" {
+		t.Fatalf("unexpected films: added=%d %+v", added, films)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	s := &Store{}
+	s.MergeFilms([]letterboxd.UserFilm{{Title: "Alien", Year: "1979", FilmURL: "/film/alien/"}})
+	s.MergeDiary([]letterboxd.DiaryEntry{
+		{Date: day(2024, time.January, 1), Title: "Alien", FilmURL: "/film/alien/"},
+		{Date: day(2024, time.January, 2), Title: "Aliens", FilmURL: "/film/aliens/"},
+	})
+	s.MergeWatchlist([]letterboxd.WatchlistItem{{Title: "Alien³", FilmURL: "/film/alien-3/"}, {Title: "Heat", FilmURL: "/film/heat/"}})
+	results := s.Search("ALIEN", 0)
+	if len(results) != 3 || results[0].Year != "1979" || results[2].Slug != "alien-3" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if got := s.Search("alien", 2); len(got) != 2 {
+		t.Fatalf("expected limit to apply, got %d", len(got))
+	}
+}
@@ -0,0 +1,222 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+const syncMaxPages = 200
+
+type SyncOptions struct {
+	// Full re-reads every page instead of stopping at the last sync's mark,
+	// which also picks up deletions and rating changes on older films.
+	Full bool
+}
+
+type SyncResult struct {
+	Diary     int
+	Watchlist int
+	Films     int
+	Lists     int
+}
+
+var syncNow = time.Now
+
+// Sync brings the diary, watchlist, watched films and lists up to date and
+// saves the store. Each list pages from newest down to the entry that was
+// newest at the last complete sync, unless opts.Full is set or there hasn't
+// been one.
+func Sync(client *letterboxd.Client, s *Store, opts SyncOptions) (SyncResult, error) {
+	var result SyncResult
+	var err error
+	if result.Diary, err = SyncDiary(client, s, opts); err != nil {
+		return result, err
+	}
+	if result.Watchlist, err = SyncWatchlist(client, s, opts); err != nil {
+		return result, err
+	}
+	if result.Films, err = SyncFilms(client, s, opts); err != nil {
+		return result, err
+	}
+	if result.Lists, err = SyncLists(client, s); err != nil {
+		return result, err
+	}
+	return result, s.Save()
+}
+
+func SyncDiary(client *letterboxd.Client, s *Store, opts SyncOptions) (int, error) {
+	snap := s.Snapshot()
+	diary, added, err := syncPages(func(page int) ([]letterboxd.DiaryEntry, error) {
+		return client.Diary(snap.Username, page, letterboxd.DiarySortDefault)
+	}, DiaryKey, snap.Diary, syncMark(snap.DiaryMark, opts))
+	if err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Diary = diary
+	s.data.DiaryMark = newestKey(diary, DiaryKey)
+	s.data.DiarySynced = syncNow()
+	return added, nil
+}
+
+func SyncWatchlist(client *letterboxd.Client, s *Store, opts SyncOptions) (int, error) {
+	snap := s.Snapshot()
+	watchlist, added, err := syncPages(func(page int) ([]letterboxd.WatchlistItem, error) {
+		return client.Watchlist(snap.Username, page, letterboxd.WatchlistFilter{})
+	}, watchlistKey, snap.Watchlist, syncMark(snap.WatchlistMark, opts))
+	if err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Watchlist = watchlist
+	s.data.WatchlistMark = newestKey(watchlist, watchlistKey)
+	s.data.WatchlistSynced = syncNow()
+	return added, nil
+}
+
+func SyncFilms(client *letterboxd.Client, s *Store, opts SyncOptions) (int, error) {
+	snap := s.Snapshot()
+	films, added, err := syncPages(func(page int) ([]letterboxd.UserFilm, error) {
+		return client.UserFilms(snap.Username, letterboxd.FilmsFilter{}, page)
+	}, filmKey, snap.Films, syncMark(snap.FilmsMark, opts))
+	if err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Films = films
+	s.data.FilmsMark = newestKey(films, filmKey)
+	s.data.FilmsSynced = syncNow()
+	return added, nil
+}
+
+// SyncLists reads every list the member has made, and the films of those
+// that are new or whose film count changed. Lists deleted on Letterboxd are
+// dropped. It returns how many lists are new.
+func SyncLists(client *letterboxd.Client, s *Store) (int, error) {
+	snap := s.Snapshot()
+	summaries, _, err := syncPages(func(page int) ([]letterboxd.List, error) {
+		return client.Lists(snap.Username, page)
+	}, listKey, nil, "")
+	if err != nil {
+		return 0, err
+	}
+	stored := make(map[string]List, len(snap.Lists))
+	for _, list := range snap.Lists {
+		stored[listKey(list.List)] = list
+	}
+	lists := make([]List, 0, len(summaries))
+	added := 0
+	for _, summary := range summaries {
+		old, ok := stored[listKey(summary)]
+		if !ok {
+			added++
+		}
+		if ok && old.FilmCount == summary.FilmCount && len(old.Films) == summary.FilmCount {
+			lists = append(lists, List{List: summary, Films: old.Films})
+			continue
+		}
+		films, _, err := syncPages(func(page int) ([]letterboxd.UserFilm, error) {
+			return client.ListFilms(summary.URL, page)
+		}, filmKey, nil, "")
+		if err != nil {
+			return 0, err
+		}
+		lists = append(lists, List{List: summary, Films: films})
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Lists = lists
+	s.data.ListsSynced = syncNow()
+	return added, nil
+}
+
+func syncMark(mark string, opts SyncOptions) string {
+	if opts.Full {
+		return ""
+	}
+	return mark
+}
+
+func watchlistKey(item letterboxd.WatchlistItem) string {
+	return letterboxd.NormalizeFilmURL(item.FilmURL)
+}
+
+func filmKey(film letterboxd.UserFilm) string {
+	return letterboxd.NormalizeFilmURL(film.FilmURL)
+}
+
+func listKey(list letterboxd.List) string {
+	return list.URL
+}
+
+func newestKey[T any](items []T, key func(T) string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	return key(items[0])
+}
+
+// syncPages reads a list newest first and returns it with how many of its
+// entries were not stored before. When mark is found among the stored
+// entries, reading stops there: the pages read replace everything stored
+// ahead of it, including entries the app merged since, and the stored
+// entries from the mark on are kept as they are. Otherwise the whole list
+// is read and replaces stored. Nothing is returned on an error, so a
+// partial read is never mistaken for a complete one.
+func syncPages[T any](fetch func(page int) ([]T, error), key func(T) string, stored []T, mark string) ([]T, int, error) {
+	known := make(map[string]struct{}, len(stored))
+	markAt := -1
+	for i, item := range stored {
+		k := key(item)
+		known[k] = struct{}{}
+		if k == mark && mark != "" && markAt == -1 {
+			markAt = i
+		}
+	}
+	var list []T
+	seen := map[string]struct{}{}
+	added := 0
+	for page := 1; ; page++ {
+		if page > syncMaxPages {
+			return nil, 0, fmt.Errorf("stopped after %d pages without reaching the end", syncMaxPages)
+		}
+		items, err := fetch(page)
+		if err != nil {
+			return nil, 0, err
+		}
+		fresh, reachedMark := 0, false
+		for _, item := range items {
+			k := key(item)
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			fresh++
+			if markAt >= 0 && k == mark {
+				reachedMark = true
+				break
+			}
+			list = append(list, item)
+			if _, ok := known[k]; !ok {
+				added++
+			}
+		}
+		if reachedMark {
+			for _, item := range stored[markAt:] {
+				if _, ok := seen[key(item)]; ok && key(item) != mark {
+					continue
+				}
+				list = append(list, item)
+			}
+			return list, added, nil
+		}
+		if fresh == 0 {
+			return list, added, nil
+		}
+	}
+}
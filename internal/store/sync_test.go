@@ -0,0 +1,198 @@
+package store
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newTestClient(pages map[string]string, paths *[]string) *letterboxd.Client {
+	return letterboxd.NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		*paths = append(*paths, req.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(pages[req.URL.Path])),
+			Header:     make(http.Header),
+		}, nil
+	})}, "com.xk72.webparts.csrf=csrf123")
+}
+
+func diaryRows(days ...int) string {
+	html := "<table>"
+	for _, d := range days {
+		html += fmt.Sprintf(`<tr class="diary-entry-row"><td class="col-monthdate"><span class="month">Mar</span><span class="year">2024</span></td><td class="col-daydate"><span class="daydate">%d</span></td><td><h2 class="name"><a href="/film/f%d/">Film %d</a></h2></td></tr>`, d, d, d)
+	}
+	return html + "</table>"
+}
+
+func TestSyncDiaryStopsAtKnownEntries(t *testing.T) {
+	s, err := OpenFile(filepath.Join(t.TempDir(), "jane.json"), "jane")
+	if err != nil {
+		t.Fatalf("OpenFile error: %v", err)
+	}
+	var paths []string
+	pages := map[string]string{
+		"/jane/diary/":              diaryRows(10, 9),
+		"/jane/diary/films/page/2/": diaryRows(8, 7),
+	}
+	added, err := SyncDiary(newTestClient(pages, &paths), s, SyncOptions{})
+	if err != nil || added != 4 || len(paths) != 3 {
+		t.Fatalf("unexpected first sync: added=%d err=%v paths=%v", added, err, paths)
+	}
+	if snap := s.Snapshot(); snap.DiaryMark == "" || len(snap.Diary) != 4 {
+		t.Fatalf("expected complete diary, got %+v", snap)
+	}
+
+	paths = nil
+	pages["/jane/diary/"] = diaryRows(12, 11)
+	pages["/jane/diary/films/page/2/"] = diaryRows(10, 9)
+	pages["/jane/diary/films/page/3/"] = diaryRows(8, 7)
+	added, err = SyncDiary(newTestClient(pages, &paths), s, SyncOptions{})
+	if err != nil || added != 2 || len(paths) != 2 {
+		t.Fatalf("unexpected incremental sync: added=%d err=%v paths=%v", added, err, paths)
+	}
+	diary := s.Snapshot().Diary
+	if len(diary) != 6 || diary[0].Title != "Film 12" || diary[5].Title != "Film 7" {
+		t.Fatalf("unexpected diary order: %+v", diary)
+	}
+
+	paths = nil
+	delete(pages, "/jane/diary/films/page/3/")
+	added, err = SyncDiary(newTestClient(pages, &paths), s, SyncOptions{Full: true})
+	if err != nil || added != 0 || len(s.Snapshot().Diary) != 4 {
+		t.Fatalf("expected full sync to drop deleted entries: added=%d err=%v diary=%d", added, err, len(s.Snapshot().Diary))
+	}
+}
+
+func TestSyncSavesAllLists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jane.json")
+	s, err := OpenFile(path, "jane")
+	if err != nil {
+		t.Fatalf("OpenFile error: %v", err)
+	}
+	var paths []string
+	pages := map[string]string{
+		"/jane/diary/":     diaryRows(1),
+		"/jane/watchlist/": `<div class="js-watchlist-main-content"><div class="react-component" data-item-name="Heat (1995)" data-item-link="/film/heat/"></div></div>`,
+		"/jane/films/":     `<ul><li class="poster-container"><div class="react-component" data-item-name="Alien (1979)" data-item-link="/film/alien/"></div></li></ul>`,
+	}
+	result, err := Sync(newTestClient(pages, &paths), s, SyncOptions{})
+	if err != nil {
+		t.Fatalf("Sync error: %v", err)
+	}
+	if result != (SyncResult{Diary: 1, Watchlist: 1, Films: 1}) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	reopened, err := OpenFile(path, "jane")
+	if err != nil {
+		t.Fatalf("reopen error: %v", err)
+	}
+	snap := reopened.Snapshot()
+	if len(snap.Diary) != 1 || len(snap.Watchlist) != 1 || len(snap.Films) != 1 {
+		t.Fatalf("expected saved lists, got %+v", snap)
+	}
+}
+
+func TestSyncDiaryReadsPastMergedPagesToTheMark(t *testing.T) {
+	s, err := OpenFile(filepath.Join(t.TempDir(), "jane.json"), "jane")
+	if err != nil {
+		t.Fatalf("OpenFile error: %v", err)
+	}
+	var paths []string
+	pages := map[string]string{"/jane/diary/": diaryRows(10, 9)}
+	if _, err := SyncDiary(newTestClient(pages, &paths), s, SyncOptions{}); err != nil {
+		t.Fatalf("first sync error: %v", err)
+	}
+
+	// The app merges the newest page it shows, leaving a gap before the
+	// entries the last sync stored.
+	pages["/jane/diary/"] = diaryRows(14, 13)
+	pages["/jane/diary/films/page/2/"] = diaryRows(12, 11)
+	pages["/jane/diary/films/page/3/"] = diaryRows(10, 9)
+	page, err := newTestClient(pages, &paths).Diary("jane", 1, letterboxd.DiarySortDefault)
+	if err != nil {
+		t.Fatalf("Diary error: %v", err)
+	}
+	s.MergeDiary(page)
+
+	// Film 13 is deleted on Letterboxd before the next sync.
+	pages["/jane/diary/"] = diaryRows(14, 12)
+	pages["/jane/diary/films/page/2/"] = diaryRows(11, 10)
+	pages["/jane/diary/films/page/3/"] = diaryRows(9)
+	paths = nil
+	added, err := SyncDiary(newTestClient(pages, &paths), s, SyncOptions{})
+	if err != nil || added != 2 || len(paths) != 2 {
+		t.Fatalf("expected the sync to read down to the mark: added=%d err=%v paths=%v", added, err, paths)
+	}
+	diary := s.Snapshot().Diary
+	if len(diary) != 5 || diary[1].Title != "Film 12" || diary[2].Title != "Film 11" || diary[4].Title != "Film 9" {
+		t.Fatalf("expected the gap filled and the deleted entry dropped, got %+v", diary)
+	}
+}
+
+func TestSyncDiaryKeepsStoreOnError(t *testing.T) {
+	s, err := OpenFile(filepath.Join(t.TempDir(), "jane.json"), "jane")
+	if err != nil {
+		t.Fatalf("OpenFile error: %v", err)
+	}
+	client := letterboxd.NewClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/jane/diary/" {
+			return &http.Response{StatusCode: http.StatusInternalServerError, Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header)}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(diaryRows(2, 1))), Header: make(http.Header)}, nil
+	})}, "")
+	if _, err := SyncDiary(client, s, SyncOptions{}); err == nil {
+		t.Fatalf("expected the failed page to fail the sync")
+	}
+	if snap := s.Snapshot(); len(snap.Diary) != 0 || snap.DiaryMark != "" {
+		t.Fatalf("expected nothing stored from a partial read, got %+v", snap)
+	}
+}
+
+func TestSyncListsFetchesChangedLists(t *testing.T) {
+	s, err := OpenFile(filepath.Join(t.TempDir(), "jane.json"), "jane")
+	if err != nil {
+		t.Fatalf("OpenFile error: %v", err)
+	}
+	list := func(slug string, count int) string {
+		return fmt.Sprintf(`<section class="list"><h2><a href="/jane/list/%s/">%s</a></h2><small class="value">%d films</small></section>`, slug, slug, count)
+	}
+	film := func(slug string) string {
+		return fmt.Sprintf(`<li class="griditem"><div class="react-component" data-item-name="%s" data-item-link="/film/%s/"></div></li>`, slug, slug)
+	}
+	var paths []string
+	pages := map[string]string{
+		"/jane/lists/":       list("heists", 1) + list("noir", 1),
+		"/jane/list/heists/": "<ul>" + film("heat") + "</ul>",
+		"/jane/list/noir/":   "<ul>" + film("laura") + "</ul>",
+	}
+	added, err := SyncLists(newTestClient(pages, &paths), s)
+	if err != nil || added != 2 || len(s.Snapshot().Lists) != 2 {
+		t.Fatalf("unexpected first sync: added=%d err=%v lists=%+v", added, err, s.Snapshot().Lists)
+	}
+
+	paths = nil
+	pages["/jane/lists/"] = list("noir", 2)
+	pages["/jane/list/noir/"] = "<ul>" + film("laura") + film("gilda") + "</ul>"
+	added, err = SyncLists(newTestClient(pages, &paths), s)
+	lists := s.Snapshot().Lists
+	if err != nil || added != 0 || len(lists) != 1 || len(lists[0].Films) != 2 {
+		t.Fatalf("expected the deleted list dropped and the changed one refetched: added=%d err=%v lists=%+v", added, err, lists)
+	}
+	for _, path := range paths {
+		if strings.HasPrefix(path, "/jane/list/heists/") {
+			t.Fatalf("expected no request for a deleted list, got %v", paths)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/config"
+	"github.com/solean/letterboxd-tui/internal/secrets"
+	"github.com/solean/letterboxd-tui/internal/store"
+)
+
+// accountPrefix starts the palette titles of account commands, so the
+// accounts key can open the palette already narrowed to them.
+const accountPrefix = "Account: "
+
+// WithAccounts sets the accounts the switcher offers and which one is in use.
+func (m Model) WithAccounts(accounts []config.Account, current string) Model {
+	m.accounts = nil
+	for _, account := range accounts {
+		if strings.TrimSpace(account.Username) != "" {
+			m.accounts = append(m.accounts, account)
+		}
+	}
+	m.account = current
+	return m
+}
+
+// WithSecrets keeps cookies saved in the app in store rather than the
+// config file.
+func (m Model) WithSecrets(store secrets.Store) Model {
+	m.secrets = store
+	return m
+}
+
+// AccountLoader builds the model for the named account, with its own
+// client, store and queue.
+type AccountLoader func(name string) (Model, error)
+
+// WithAccountLoader lets the switcher load another account in place.
+func (m Model) WithAccountLoader(load AccountLoader) Model {
+	m.loadAccount = load
+	return m
+}
+
+// accountLoadedMsg carries the model built for an account picked in the
+// switcher.
+type accountLoadedMsg struct {
+	name  string
+	model Model
+	err   error
+}
+
+// accountMsg tags a command's result with the account session that issued
+// it, so results still in flight for an account switched away from are
+// dropped.
+type accountMsg struct {
+	session int
+	msg     tea.Msg
+}
+
+// accountCmd tags the messages cmd produces with session, including those
+// of a batch.
+func accountCmd(session int, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case tea.BatchMsg:
+			cmds := make(tea.BatchMsg, len(msg))
+			for i, c := range msg {
+				cmds[i] = accountCmd(session, c)
+			}
+			return cmds
+		case nil, tea.QuitMsg, accountMsg:
+			return msg
+		default:
+			return accountMsg{session: session, msg: msg}
+		}
+	}
+}
+
+func (m Model) accountName() string {
+	if m.account == "" {
+		return config.DefaultAccount
+	}
+	return m.account
+}
+
+func (m Model) canSwitchAccount() bool {
+	return len(m.accounts) > 1 && m.loadAccount != nil
+}
+
+func (m Model) accountCommands() []paletteCommand {
+	var commands []paletteCommand
+	for _, account := range m.accounts {
+		if strings.EqualFold(account.Name, m.accountName()) {
+			continue
+		}
+		commands = append(commands, paletteCommand{
+			title: accountPrefix + account.Name + " (@" + account.Username + ")",
+			run:   switchAccount(account.Name),
+		})
+	}
+	return commands
+}
+
+func switchAccount(name string) func(Model) (tea.Model, tea.Cmd) {
+	return func(m Model) (tea.Model, tea.Cmd) {
+		if m.queueReplaying {
+			m.accountStatus = "wait for pending changes to finish sending"
+			return m, nil
+		}
+		m.switchTo = name
+		m.accountStatus = "switching to " + name + "…"
+		load, s, q := m.loadAccount, m.store, m.queue
+		return m, func() tea.Msg {
+			// Saved first, so the next model opens them whole even when both
+			// accounts are the same member.
+			if err := saveAccount(s, q); err != nil {
+				return accountLoadedMsg{name: name, err: err}
+			}
+			next, err := load(name)
+			return accountLoadedMsg{name: name, model: next, err: err}
+		}
+	}
+}
+
+func saveAccount(s *store.Store, q *store.Queue) error {
+	if s != nil {
+		if err := s.Save(); err != nil {
+			return fmt.Errorf("saving local store: %w", err)
+		}
+	}
+	if q != nil {
+		if err := q.Save(); err != nil {
+			return fmt.Errorf("saving pending changes: %w", err)
+		}
+	}
+	return nil
+}
+
+// switchedAccount swaps in the model loaded for the account being switched
+// to, keeping the theme and window size.
+func (m Model) switchedAccount(msg accountLoadedMsg) (Model, tea.Cmd) {
+	if msg.name != m.switchTo {
+		return m, nil
+	}
+	m.switchTo = ""
+	if msg.err != nil {
+		m.logError("account switch", msg.err)
+		m.accountStatus = "couldn't switch to " + msg.name + ": " + firstLine(msg.err.Error())
+		return m, nil
+	}
+	if m.debugStop != nil {
+		close(m.debugStop)
+	}
+	next := msg.model.WithThemes(m.themes).WithAccountLoader(m.loadAccount)
+	next.session = m.session + 1
+	var cmd tea.Cmd
+	if m.width > 0 || m.height > 0 {
+		updated, sizeCmd := next.update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		next, cmd = updated.(Model), sizeCmd
+	}
+	return next, tea.Batch(next.Init(), cmd)
+}
+
+// openAccounts opens the palette listing only the other accounts.
+func (m Model) openAccounts() (Model, tea.Cmd) {
+	m, cmd := m.openPalette()
+	m.paletteInput.SetValue(accountPrefix)
+	m.paletteInput.CursorEnd()
+	return m, cmd
+}
@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/config"
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+	"github.com/solean/letterboxd-tui/internal/store"
+)
+
+func TestAccountSwitcherLoadsPickedAccountInPlace(t *testing.T) {
+	accounts := []config.Account{
+		{Name: config.DefaultAccount, Username: "jane"},
+		{Name: "club", Username: "filmclub"},
+		{Name: "draft"},
+	}
+	load := func(name string) (Model, error) {
+		if name != "club" {
+			return Model{}, errors.New("unknown account " + name)
+		}
+		return NewModel("filmclub", nil).WithAccounts(accounts, name), nil
+	}
+	m := NewModel("jane", nil).WithAccounts(accounts, config.DefaultAccount).WithAccountLoader(load)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = updated.(Model)
+
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	if !m.paletteModal {
+		t.Fatalf("expected A to open the account switcher")
+	}
+	titles := paletteTitles(m)
+	if len(titles) != 1 || titles[0] != "Account: club (@filmclub) " {
+		t.Fatalf("expected only the other complete account, got %q", titles)
+	}
+	m, cmd := press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.switchTo != "club" || cmd == nil {
+		t.Fatalf("expected a load for club, got %q", m.switchTo)
+	}
+	stale := accountCmd(m.session, func() tea.Msg {
+		return profileMsg{profile: letterboxd.Profile{Stats: []letterboxd.ProfileStat{{Label: "Films"}}}}
+	})()
+
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if m.username != "filmclub" || m.accountName() != "club" || m.loadAccount == nil {
+		t.Fatalf("expected the model for club, got @%s as %s", m.username, m.accountName())
+	}
+	if m.width != 100 || m.height != 30 {
+		t.Fatalf("expected the window size to carry over, got %dx%d", m.width, m.height)
+	}
+	updated, _ = m.Update(stale)
+	if m = updated.(Model); len(m.profile.Stats) != 0 {
+		t.Fatalf("expected a result from the old account to be dropped, got %+v", m.profile)
+	}
+}
+
+func TestAccountSwitcherNeedsTwoAccounts(t *testing.T) {
+	m := NewModel("jane", nil).WithAccounts([]config.Account{{Name: config.DefaultAccount, Username: "jane"}}, "")
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	if m.paletteModal {
+		t.Fatalf("expected no switcher with a single account")
+	}
+}
+
+func TestAccountSwitchSavesAndStopsTheOldAccount(t *testing.T) {
+	dir := t.TempDir()
+	accounts := []config.Account{
+		{Name: config.DefaultAccount, Username: "jane"},
+		{Name: "club", Username: "filmclub"},
+	}
+	var load AccountLoader
+	load = func(name string) (Model, error) {
+		username := map[string]string{config.DefaultAccount: "jane", "club": "filmclub"}[name]
+		s, err := store.OpenFile(filepath.Join(dir, "store", username+".json"), username)
+		if err != nil {
+			return Model{}, err
+		}
+		q, err := store.OpenQueueFile(filepath.Join(dir, "queue", username+".json"))
+		if err != nil {
+			return Model{}, err
+		}
+		m := NewModel(username, letterboxd.NewClient(nil, "")).WithAccounts(accounts, name).WithStore(s).WithQueue(q)
+		return m.WithAccountLoader(load), nil
+	}
+	switchTo := func(m Model, name string) Model {
+		updated, cmd := switchAccount(name)(m)
+		updated, _ = updated.(Model).Update(cmd())
+		return updated.(Model)
+	}
+
+	m, err := load(config.DefaultAccount)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	m.queue.Add(store.NewWatchlistOp("Heat", letterboxd.BaseURL+"/film/heat/", letterboxd.WatchlistRequest{FilmSlug: "heat"}, true))
+	m.store.PutFilm(letterboxd.Film{URL: letterboxd.BaseURL + "/film/heat/", Title: "Heat"})
+	wait := waitDebugEventCmd(m.debugEvents, m.debugStop)
+
+	m = switchTo(m, "club")
+	if m.username != "filmclub" || m.pendingOps() != 0 {
+		t.Fatalf("expected club's own model, got @%s with %d pending", m.username, m.pendingOps())
+	}
+	done := make(chan tea.Msg, 1)
+	go func() { done <- wait() }()
+	select {
+	case msg := <-done:
+		if msg != nil {
+			t.Fatalf("expected the old debug feed to stop, got %T", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected the old debug feed to stop")
+	}
+
+	m = switchTo(m, config.DefaultAccount)
+	if m.username != "jane" || m.pendingOps() != 1 {
+		t.Fatalf("expected jane's pending change kept, got @%s with %d pending", m.username, m.pendingOps())
+	}
+	if _, ok := m.store.Film(letterboxd.BaseURL + "/film/heat/"); !ok {
+		t.Fatalf("expected jane's saved film kept")
+	}
+}
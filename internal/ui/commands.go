@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
 	"runtime"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/solean/letterboxd-tui/internal/config"
 	"github.com/solean/letterboxd-tui/internal/letterboxd"
+	"github.com/solean/letterboxd-tui/internal/secrets"
 )
 
 var execCommand = exec.Command
@@ -21,11 +23,25 @@ type diaryMsg struct {
 	sort  letterboxd.DiarySort
 }
 
+type allDiaryMsg struct {
+	entries   []letterboxd.DiaryEntry
+	err       error
+	offline   bool
+	truncated bool
+}
+
 type watchlistMsg struct {
-	items []letterboxd.WatchlistItem
-	err   error
-	page  int
-	sort  letterboxd.WatchlistSort
+	items  []letterboxd.WatchlistItem
+	err    error
+	page   int
+	filter letterboxd.WatchlistFilter
+}
+
+type userFilmsMsg struct {
+	items  []letterboxd.UserFilm
+	err    error
+	page   int
+	filter letterboxd.FilmsFilter
 }
 
 type filmMsg struct {
@@ -58,6 +74,20 @@ type activityMsg struct {
 	after string
 }
 
+type peopleMsg struct {
+	items []letterboxd.Member
+	err   error
+	page  int
+	user  string
+	kind  letterboxd.PeopleKind
+}
+
+type followResultMsg struct {
+	username string
+	follow   bool
+	err      error
+}
+
 type errMsg struct {
 	err error
 }
@@ -66,8 +96,13 @@ type openMsg struct {
 	err error
 }
 
+// logResultMsg and watchlistResultMsg carry the film the write was for,
+// which may no longer be the one on screen when they arrive.
 type logResultMsg struct {
-	err error
+	err     error
+	req     letterboxd.DiaryEntryRequest
+	title   string
+	filmURL string
 }
 
 type cookieSavedMsg struct {
@@ -77,19 +112,27 @@ type cookieSavedMsg struct {
 type watchlistResultMsg struct {
 	err         error
 	inWatchlist bool
+	req         letterboxd.WatchlistRequest
+	title       string
+	filmURL     string
 }
 
 func (m Model) Init() tea.Cmd {
 	cmds := []tea.Cmd{
 		fetchProfileCmd(m.client, m.profileUser),
 		fetchDiaryCmd(m.client, m.username, 1, m.diarySortParam()),
-		fetchWatchlistCmd(m.client, m.username, 1, m.watchlistSortParam()),
+		fetchWatchlistCmd(m.client, m.username, 1, m.watchlistQuery()),
+		fetchUserFilmsCmd(m.client, m.username, m.filmsQuery(), 1),
 		fetchActivityCmd(m.client, m.username, tabActivity, ""),
 	}
 	if m.hasCookie() {
-		cmds = append(cmds, fetchActivityCmd(m.client, m.username, tabFollowing, ""))
+		cmds = append(cmds, fetchActivityCmd(m.client, m.username, tabFollowing, ""), checkSessionCmd(m.client, m.username))
+	}
+	if m.pendingOps() > 0 {
+		cmds = append(cmds, func() tea.Msg { return queueRetryMsg{} })
 	}
-	return tea.Batch(cmds...)
+	cmds = append(cmds, waitDebugEventCmd(m.debugEvents, m.debugStop))
+	return accountCmd(m.session, tea.Batch(cmds...))
 }
 
 func fetchProfileCmd(client *letterboxd.Client, username string) tea.Cmd {
@@ -113,10 +156,27 @@ func fetchDiaryCmd(client *letterboxd.Client, username string, page int, sort le
 	}
 }
 
-func fetchWatchlistCmd(client *letterboxd.Client, username string, page int, sort letterboxd.WatchlistSort) tea.Cmd {
+func fetchAllDiaryCmd(client *letterboxd.Client, username string) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := client.AllDiary(username)
+		if errors.Is(err, letterboxd.ErrListTruncated) {
+			return allDiaryMsg{entries: entries, truncated: true}
+		}
+		return allDiaryMsg{entries: entries, err: err}
+	}
+}
+
+func fetchWatchlistCmd(client *letterboxd.Client, username string, page int, filter letterboxd.WatchlistFilter) tea.Cmd {
+	return func() tea.Msg {
+		items, err := client.Watchlist(username, page, filter)
+		return watchlistMsg{items: items, err: err, page: page, filter: filter}
+	}
+}
+
+func fetchUserFilmsCmd(client *letterboxd.Client, username string, filter letterboxd.FilmsFilter, page int) tea.Cmd {
 	return func() tea.Msg {
-		items, err := client.Watchlist(username, page, sort)
-		return watchlistMsg{items: items, err: err, page: page, sort: sort}
+		items, err := client.UserFilms(username, filter, page)
+		return userFilmsMsg{items: items, err: err, page: page, filter: filter}
 	}
 }
 
@@ -170,24 +230,59 @@ func fetchActivityCmd(client *letterboxd.Client, username string, which tab, aft
 	}
 }
 
-func saveDiaryEntryCmd(client *letterboxd.Client, req letterboxd.DiaryEntryRequest) tea.Cmd {
+func fetchPeopleCmd(client *letterboxd.Client, username string, kind letterboxd.PeopleKind, page int) tea.Cmd {
+	return func() tea.Msg {
+		var (
+			items []letterboxd.Member
+			err   error
+		)
+		switch kind {
+		case letterboxd.PeopleFollowers:
+			items, err = client.Followers(username, page)
+		default:
+			items, err = client.Following(username, page)
+		}
+		return peopleMsg{items: items, err: err, page: page, user: username, kind: kind}
+	}
+}
+
+func setFollowingCmd(client *letterboxd.Client, username string, follow bool) tea.Cmd {
+	return func() tea.Msg {
+		err := client.SetFollowing(username, follow)
+		return followResultMsg{username: username, follow: follow, err: err}
+	}
+}
+
+func saveDiaryEntryCmd(client *letterboxd.Client, film letterboxd.Film, req letterboxd.DiaryEntryRequest) tea.Cmd {
+	title, filmURL := film.Title, film.URL
 	return func() tea.Msg {
 		err := client.SaveDiaryEntry(req)
-		return logResultMsg{err: err}
+		return logResultMsg{err: err, req: req, title: title, filmURL: filmURL}
 	}
 }
 
-func saveCookieCmd(username, cookie string) tea.Cmd {
+// saveCookieCmd stores the cookie on its account, leaving the rest of the
+// config alone. With a secret store the cookie goes there and the config
+// only gets the username.
+func saveCookieCmd(store secrets.Store, account config.Account) tea.Cmd {
+	account.Username = strings.TrimSpace(account.Username)
+	account.Cookie = strings.TrimSpace(account.Cookie)
 	return func() tea.Msg {
-		cfg := config.Config{Username: strings.TrimSpace(username), Cookie: strings.TrimSpace(cookie)}
-		return cookieSavedMsg{err: config.Save(cfg)}
+		if store != nil {
+			if err := store.Set(account.Name, account.Cookie); err != nil {
+				return cookieSavedMsg{err: err}
+			}
+			account.Cookie = ""
+		}
+		return cookieSavedMsg{err: config.Update(func(cfg *config.Config) { cfg.PutAccount(account) })}
 	}
 }
 
-func setWatchlistCmd(client *letterboxd.Client, req letterboxd.WatchlistRequest, inWatchlist bool) tea.Cmd {
+func setWatchlistCmd(client *letterboxd.Client, film letterboxd.Film, req letterboxd.WatchlistRequest, inWatchlist bool) tea.Cmd {
+	title, filmURL := film.Title, film.URL
 	return func() tea.Msg {
 		err := client.SetWatchlist(req, inWatchlist)
-		return watchlistResultMsg{err: err, inWatchlist: inWatchlist}
+		return watchlistResultMsg{err: err, inWatchlist: inWatchlist, req: req, title: title, filmURL: filmURL}
 	}
 }
 
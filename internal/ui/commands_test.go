@@ -73,7 +73,7 @@ func TestFetchCommands(t *testing.T) {
 	if msg := fetchDiaryCmd(client, "jane", 1, letterboxd.DiarySortDefault)(); msg.(diaryMsg).err != nil {
 		t.Fatalf("unexpected diary error")
 	}
-	if msg := fetchWatchlistCmd(client, "jane", 1, letterboxd.WatchlistSortDefault)(); msg.(watchlistMsg).err != nil {
+	if msg := fetchWatchlistCmd(client, "jane", 1, letterboxd.WatchlistFilter{})(); msg.(watchlistMsg).err != nil {
 		t.Fatalf("unexpected watchlist error")
 	}
 	if msg := fetchActivityCmd(client, "jane", tabActivity, "")(); msg.(activityMsg).err != nil {
@@ -117,7 +117,7 @@ func TestSaveDiaryEntryCmd(t *testing.T) {
 		return newHTTPResponse(http.StatusOK, "ok"), nil
 	})
 	req := letterboxd.DiaryEntryRequest{ViewingUID: "film:123"}
-	if msg := saveDiaryEntryCmd(client, req)(); msg.(logResultMsg).err != nil {
+	if msg := saveDiaryEntryCmd(client, letterboxd.Film{}, req)(); msg.(logResultMsg).err != nil {
 		t.Fatalf("unexpected save diary error")
 	}
 }
@@ -127,11 +127,29 @@ func TestSetWatchlistCmd(t *testing.T) {
 		return newHTTPResponse(http.StatusOK, "ok"), nil
 	})
 	req := letterboxd.WatchlistRequest{WatchlistID: "lid123"}
-	if msg := setWatchlistCmd(client, req, true)(); msg.(watchlistResultMsg).err != nil {
+	if msg := setWatchlistCmd(client, letterboxd.Film{}, req, true)(); msg.(watchlistResultMsg).err != nil {
 		t.Fatalf("unexpected watchlist error")
 	}
 }
 
+func TestPeopleCmds(t *testing.T) {
+	var paths []string
+	client := newStubClient(func(req *http.Request) (*http.Response, error) {
+		paths = append(paths, req.URL.Path)
+		return newHTTPResponse(http.StatusOK, `<div class="person-summary"><a class="name" href="/alice/">Alice</a></div>`), nil
+	})
+	msg := fetchPeopleCmd(client, "jane", letterboxd.PeopleFollowers, 2)().(peopleMsg)
+	if msg.err != nil || len(msg.items) != 1 || msg.kind != letterboxd.PeopleFollowers {
+		t.Fatalf("unexpected people msg: %+v", msg)
+	}
+	if res := setFollowingCmd(client, "alice", true)().(followResultMsg); res.err != nil || !res.follow {
+		t.Fatalf("unexpected follow result: %+v", res)
+	}
+	if len(paths) != 2 || paths[0] != "/jane/followers/page/2/" || paths[1] != "/alice/follow/" {
+		t.Fatalf("unexpected request paths: %v", paths)
+	}
+}
+
 func TestOpenBrowserCmd(t *testing.T) {
 	if msg := openBrowserCmd("")().(openMsg); msg.err == nil {
 		t.Fatalf("expected error for missing URL")
@@ -0,0 +1,168 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+const compareListLimit = 10
+
+type compareMsg struct {
+	cmp   letterboxd.Comparison
+	err   error
+	userA string
+	userB string
+}
+
+func fetchCompareCmd(client *letterboxd.Client, userA, userB string) tea.Cmd {
+	return func() tea.Msg {
+		cmp, err := client.Compare(userA, userB)
+		return compareMsg{cmp: cmp, err: err, userA: userA, userB: userB}
+	}
+}
+
+func (m Model) openCompare(userA, userB string) Model {
+	if userA == "" || userB == "" || userA == userB {
+		return m
+	}
+	if m.activeTab != tabCompare {
+		m.compareReturn = m.activeTab
+	}
+	m.profileModal = false
+	m.compareA = userA
+	m.compareB = userB
+	m.comparison = letterboxd.Comparison{}
+	m.compareErr = nil
+	m.compareLoading = true
+	m.activeTab = tabCompare
+	m.resetTabPosition()
+	return m
+}
+
+func (m Model) closeCompare() Model {
+	m.activeTab = m.compareReturn
+	if m.activeTab == tabCompare || m.activeTab == tabFilm {
+		m.activeTab = tabProfile
+	}
+	m.resetTabPosition()
+	return m
+}
+
+func compareTabLabel(userA, userB string) string {
+	return fmt.Sprintf("Compare: @%s vs @%s", userA, userB)
+}
+
+func renderCompare(m Model, theme themeStyles) string {
+	if m.compareErr != nil {
+		return theme.dim.Render("Error: " + errorText(m.compareErr))
+	}
+	if m.compareLoading {
+		return theme.dim.Render(fmt.Sprintf("Loading films for @%s and @%s…", m.compareA, m.compareB))
+	}
+	return renderComparison(m.comparison, m.width, theme)
+}
+
+// ComparisonReport renders cmp as the same two-column report shown in the
+// compare view, for use outside the TUI.
+func ComparisonReport(cmp letterboxd.Comparison, width int) string {
+	return renderComparison(cmp, width, newTheme())
+}
+
+func renderComparison(cmp letterboxd.Comparison, width int, theme themeStyles) string {
+	colWidth := max(24, (max(40, width)-4)/2)
+	var rows []string
+	rows = append(rows, compareColumns(
+		[]string{
+			theme.user.Render("@" + cmp.UserA),
+			fmt.Sprintf("%d films watched", cmp.WatchedA),
+			fmt.Sprintf("%d rated", cmp.RatedA),
+		},
+		[]string{
+			theme.user.Render("@" + cmp.UserB),
+			fmt.Sprintf("%d films watched", cmp.WatchedB),
+			fmt.Sprintf("%d rated", cmp.RatedB),
+		},
+		colWidth,
+	))
+
+	rows = append(rows, "", theme.header.Render("Overlap"))
+	rows = append(rows, fmt.Sprintf("%d films in common, %d rated by both", len(cmp.Shared), cmp.BothRated))
+	if cmp.CorrelationOK {
+		rows = append(rows, fmt.Sprintf("Rating correlation: %.2f (%s)", cmp.Correlation, describeCorrelation(cmp.Correlation)))
+	} else {
+		rows = append(rows, theme.dim.Render("Rating correlation: not enough shared ratings"))
+	}
+
+	rows = append(rows, "", theme.header.Render("Biggest disagreements"))
+	if len(cmp.Disagreements) == 0 {
+		rows = append(rows, theme.dim.Render("None."))
+	}
+	for _, film := range cmp.Disagreements[:min(compareListLimit, len(cmp.Disagreements))] {
+		title := truncate(filmLabel(film.Title, film.Year), colWidth-8)
+		left := []string{title + " " + styleRating(film.RatingA, theme)}
+		right := []string{styleRating(film.RatingB, theme)}
+		rows = append(rows, compareColumns(left, right, colWidth))
+	}
+
+	rows = append(rows, "", compareColumns(
+		compareRecs(fmt.Sprintf("Loved by @%s, on @%s's watchlist", cmp.UserB, cmp.UserA), cmp.LovedByBForA, colWidth, theme),
+		compareRecs(fmt.Sprintf("Loved by @%s, on @%s's watchlist", cmp.UserA, cmp.UserB), cmp.LovedByAForB, colWidth, theme),
+		colWidth,
+	))
+	if cmp.Truncated {
+		rows = append(rows, "", theme.dim.Render("Some lists were too long to read in full, so these cover only part of them."))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func compareRecs(title string, films []letterboxd.UserFilm, width int, theme themeStyles) []string {
+	rows := []string{theme.header.Render(truncate(title, width))}
+	if len(films) == 0 {
+		return append(rows, theme.dim.Render("None."))
+	}
+	for _, film := range films[:min(compareListLimit, len(films))] {
+		line := truncate(filmLabel(film.Title, film.Year), width-8)
+		if film.Rating != "" {
+			line += " " + styleRating(film.Rating, theme)
+		} else if film.Liked {
+			line += " ♥"
+		}
+		rows = append(rows, line)
+	}
+	return rows
+}
+
+func compareColumns(left, right []string, width int) string {
+	col := lipgloss.NewStyle().Width(width)
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		col.Render(lipgloss.JoinVertical(lipgloss.Left, left...)),
+		"  ",
+		col.Render(lipgloss.JoinVertical(lipgloss.Left, right...)),
+	)
+}
+
+func filmLabel(title, year string) string {
+	if year == "" {
+		return title
+	}
+	return fmt.Sprintf("%s (%s)", title, year)
+}
+
+func describeCorrelation(r float64) string {
+	switch {
+	case r >= 0.7:
+		return "very similar taste"
+	case r >= 0.4:
+		return "similar taste"
+	case r >= 0.1:
+		return "slightly similar"
+	case r > -0.1:
+		return "unrelated"
+	default:
+		return "opposite taste"
+	}
+}
@@ -0,0 +1,288 @@
+package ui
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+	"github.com/solean/letterboxd-tui/internal/logging"
+)
+
+// maxDebugEvents is how many requests, warnings and errors the debug panel
+// keeps.
+const maxDebugEvents = 200
+
+type debugEventMsg letterboxd.Event
+
+// subscribeDebug routes the client's events into a channel the model reads
+// one message at a time. Events are dropped rather than blocking a request
+// when the UI falls behind.
+func subscribeDebug(client *letterboxd.Client) chan letterboxd.Event {
+	events := make(chan letterboxd.Event, 64)
+	client.OnEvent = func(ev letterboxd.Event) {
+		select {
+		case events <- ev:
+		default:
+		}
+	}
+	return events
+}
+
+// waitDebugEventCmd waits for the next event until stop is closed, which
+// happens when the model is swapped out for another account's.
+func waitDebugEventCmd(events chan letterboxd.Event, stop chan struct{}) tea.Cmd {
+	if events == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		select {
+		case ev := <-events:
+			return debugEventMsg(ev)
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+// recordDebugEvent adds ev to the front of the panel's list, keeping the
+// selection on the event it was on.
+func (m *Model) recordDebugEvent(ev letterboxd.Event) {
+	m.debugLog = append([]letterboxd.Event{ev}, m.debugLog...)
+	if len(m.debugLog) > maxDebugEvents {
+		m.debugLog = m.debugLog[:maxDebugEvents]
+	}
+	if m.debugPanel && m.debugList.selected > 0 {
+		m.debugList.selected = min(m.debugList.selected+1, len(m.debugLog)-1)
+	}
+}
+
+// logError logs err and adds it to the debug panel. Errors from a request
+// are there already, as that request's event.
+func (m *Model) logError(context string, err error) {
+	logging.LogError(context, err)
+	if letterboxd.RequestID(err) != "" {
+		return
+	}
+	m.recordDebugEvent(letterboxd.Event{Kind: letterboxd.EventError, Time: time.Now(), Message: context, Err: err})
+}
+
+func (m Model) openDebugPanel() (tea.Model, tea.Cmd) {
+	m.debugPanel = true
+	m.debugDetail = false
+	m.debugList.selected = 0
+	m.debugScroll = 0
+	return m, nil
+}
+
+func (m Model) updateDebugPanel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.QuitAll):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Debug):
+		m.debugPanel = false
+	case key.Matches(msg, m.keys.Cancel, m.keys.ModalBack, m.keys.Back):
+		if m.debugDetail {
+			m.debugDetail = false
+		} else {
+			m.debugPanel = false
+		}
+	case m.debugDetail && key.Matches(msg, m.keys.Down):
+		lines := debugDetailLines(m.debugLog[m.debugList.selected], modalContentWidth(m.width, m.height), m.theme)
+		m.debugScroll = min(m.debugScroll+1, max(0, len(lines)-1))
+	case m.debugDetail && key.Matches(msg, m.keys.Up):
+		m.debugScroll = max(0, m.debugScroll-1)
+	case key.Matches(msg, m.keys.Down):
+		m.debugList.selected = min(m.debugList.selected+1, max(0, len(m.debugLog)-1))
+	case key.Matches(msg, m.keys.Up):
+		m.debugList.selected = max(0, m.debugList.selected-1)
+	case key.Matches(msg, m.keys.JumpTop):
+		m.debugList.selected = 0
+		m.debugScroll = 0
+	case !m.debugDetail && key.Matches(msg, m.keys.Select) && len(m.debugLog) > 0:
+		m.debugDetail = true
+		m.debugScroll = 0
+	}
+	return m, nil
+}
+
+func renderDebugPanel(m Model, theme themeStyles) string {
+	width, height := modalDimensions(m.width, m.height)
+	innerWidth := width - 4
+	innerHeight := height - 2
+	legend := renderHelp(m, theme, innerWidth)
+	bodyHeight := max(1, innerHeight-lipgloss.Height(legend)-1)
+
+	var lines []string
+	if m.debugDetail && m.debugList.selected < len(m.debugLog) {
+		lines = debugDetailLines(m.debugLog[m.debugList.selected], innerWidth, theme)
+		start := clamp(m.debugScroll, 0, max(0, len(lines)-bodyHeight))
+		lines = lines[start:]
+	} else {
+		lines = debugListLines(m, innerWidth, bodyHeight, theme)
+	}
+	if len(lines) > bodyHeight {
+		lines = lines[:bodyHeight]
+	}
+	body := lipgloss.Place(innerWidth, bodyHeight, lipgloss.Left, lipgloss.Top, strings.Join(lines, "\n"))
+	content := lipgloss.JoinVertical(lipgloss.Left, body, "", legend)
+
+	panel := theme.panel.
+		Width(width).
+		Height(height).
+		Padding(1, 2)
+	panelContent := lipgloss.Place(innerWidth, innerHeight, lipgloss.Left, lipgloss.Top, content)
+	return panel.Render(panelContent)
+}
+
+// debugListLines lists the recent events, newest first, scrolled to keep
+// the selection in view.
+func debugListLines(m Model, width, height int, theme themeStyles) []string {
+	lines := []string{theme.header.Render("Debug") + " " + theme.subtle.Render(fmt.Sprintf("%d recent requests, warnings and errors", len(m.debugLog)))}
+	if len(m.debugLog) == 0 {
+		return append(lines, "", theme.dim.Render("Nothing yet."))
+	}
+	rows := max(1, height-1)
+	start := clamp(m.debugList.selected-rows+1, 0, max(0, len(m.debugLog)-rows))
+	for i := start; i < len(m.debugLog) && i < start+rows; i++ {
+		ev := m.debugLog[i]
+		if i == m.debugList.selected {
+			lines = append(lines, renderSelectableLine(debugSummary(ev), true, width, theme))
+			continue
+		}
+		style := theme.item
+		switch {
+		case ev.Err != nil:
+			style = theme.rateLow
+		case ev.Kind == letterboxd.EventWarning:
+			style = theme.rateMid
+		case ev.CacheHit:
+			style = theme.dim
+		}
+		lines = append(lines, style.Render(truncate("  "+debugSummary(ev), width)))
+	}
+	return lines
+}
+
+func debugSummary(ev letterboxd.Event) string {
+	when := ev.Time.Format("15:04:05")
+	switch ev.Kind {
+	case letterboxd.EventWarning:
+		return fmt.Sprintf("%s WARN %s · %s", when, debugPath(ev.URL), ev.Message)
+	case letterboxd.EventError:
+		return fmt.Sprintf("%s ERROR %s · %s", when, ev.Message, errorText(ev.Err))
+	}
+	status := "---"
+	if ev.Status != 0 {
+		status = fmt.Sprint(ev.Status)
+	}
+	parts := []string{when, ev.Method, status}
+	if ev.CacheHit {
+		parts = append(parts, "cache")
+	} else {
+		parts = append(parts, ev.Duration.Round(time.Millisecond).String())
+	}
+	if ev.Retries > 0 {
+		parts = append(parts, fmt.Sprintf("retries:%d", ev.Retries))
+	}
+	if ev.Fallback {
+		parts = append(parts, "fallback")
+	}
+	parts = append(parts, debugPath(ev.URL))
+	if ev.Err != nil {
+		parts = append(parts, "· "+errorText(ev.Err))
+	}
+	return strings.Join(parts, " ")
+}
+
+// debugPath shortens Letterboxd URLs to their path; others stay whole.
+func debugPath(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Host != "letterboxd.com" && !strings.HasSuffix(u.Host, ".letterboxd.com")) {
+		return raw
+	}
+	if u.RawQuery != "" {
+		return u.Path + "?" + u.RawQuery
+	}
+	return u.Path
+}
+
+func debugDetailLines(ev letterboxd.Event, width int, theme themeStyles) []string {
+	title := "Request"
+	switch ev.Kind {
+	case letterboxd.EventWarning:
+		title = "Parse warning"
+	case letterboxd.EventError:
+		title = "Error"
+	}
+	lines := []string{theme.header.Render(title), ""}
+	field := func(label, value string) {
+		if value != "" {
+			lines = append(lines, theme.subtle.Render(label+": ")+theme.item.Render(value))
+		}
+	}
+	field("ID", ev.ID)
+	field("Time", ev.Time.Format("2006-01-02 15:04:05"))
+	if ev.Kind == letterboxd.EventError {
+		field("Context", ev.Message)
+	}
+	field("URL", strings.TrimSpace(ev.Method+" "+ev.URL))
+	if ev.Kind == letterboxd.EventRequest {
+		if ev.Status != 0 {
+			field("Status", fmt.Sprintf("%d %s", ev.Status, http.StatusText(ev.Status)))
+		}
+		if !ev.CacheHit {
+			field("Duration", ev.Duration.Round(time.Millisecond).String())
+			field("Retries", fmt.Sprint(ev.Retries))
+			field("Fallback", fmt.Sprint(ev.Fallback))
+		}
+		field("Cache hit", fmt.Sprint(ev.CacheHit))
+	}
+	if ev.Message != "" && ev.Kind != letterboxd.EventError {
+		lines = append(lines, "")
+		for _, line := range strings.Split(wrapText(ev.Message, width), "\n") {
+			lines = append(lines, theme.rateMid.Render(line))
+		}
+	}
+	if ev.Err != nil {
+		lines = append(lines, "", theme.subtle.Render("Error"))
+		for _, text := range strings.Split(logging.Redact(ev.Err.Error()), "\n") {
+			for _, line := range strings.Split(wrapText(text, width), "\n") {
+				lines = append(lines, theme.rateLow.Render(line))
+			}
+		}
+	}
+	lines = append(lines, debugHeaderLines("Request headers", ev.RequestHeader, width, theme)...)
+	lines = append(lines, debugHeaderLines("Response headers", ev.ResponseHeader, width, theme)...)
+	return lines
+}
+
+func debugHeaderLines(title string, header http.Header, width int, theme themeStyles) []string {
+	if len(header) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := []string{"", theme.subtle.Render(title)}
+	for _, name := range names {
+		lines = append(lines, theme.item.Render(truncate(name+": "+strings.Join(header[name], ", "), width)))
+	}
+	return lines
+}
+
+// errorText is the first line of err, for showing in place of content;
+// the rest, such as -debug's headers and stack, is in the debug panel.
+func errorText(err error) string {
+	text, _, _ := strings.Cut(strings.TrimSpace(err.Error()), "\n")
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+func TestDebugPanelListsRequestsWithRedactedDetail(t *testing.T) {
+	client := newStubClient(func(req *http.Request) (*http.Response, error) {
+		return newHTTPResponse(http.StatusNotFound, "missing"), nil
+	})
+	m := NewModel("jane", client)
+	m.width, m.height = 120, 40
+
+	if _, err := client.Profile("jane"); err == nil {
+		t.Fatalf("expected the stub's 404 to fail")
+	}
+	cmd := waitDebugEventCmd(m.debugEvents, m.debugStop)
+	updated, next := m.update(cmd())
+	m = updated.(Model)
+	if next == nil || len(m.debugLog) != 1 {
+		t.Fatalf("expected the event recorded and the next one awaited, got %d events", len(m.debugLog))
+	}
+	m.recordDebugEvent(letterboxd.Event{Kind: letterboxd.EventWarning, Time: time.Now(), URL: "https://letterboxd.com/jane/films/diary/", Message: "2 of 5 diary rows had no film title"})
+
+	updated, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+	m = updated.(Model)
+	view := stripANSI(m.View())
+	if !m.debugPanel || !strings.Contains(view, "WARN /jane/films/diary/") || !strings.Contains(view, "GET 404") {
+		t.Fatalf("expected the panel to list the warning and request, got %q", view)
+	}
+
+	updated, _ = m.update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	updated, _ = m.update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	view = stripANSI(m.View())
+	if !m.debugDetail || !strings.Contains(view, "Cookie: <redacted>") || strings.Contains(view, "csrf123") {
+		t.Fatalf("expected request detail with the cookie redacted, got %q", view)
+	}
+
+	updated, _ = m.update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	updated, _ = m.update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.debugDetail || m.debugPanel {
+		t.Fatalf("expected Esc to go back to the list and then close the panel")
+	}
+}
+
+func TestErrorTextShowsFirstLine(t *testing.T) {
+	err := errors.New("unexpected status 500 for https://letterboxd.com/\nresponse headers: Server=cloudflare\ngoroutine 1 [running]:")
+	if got := errorText(err); got != "unexpected status 500 for https://letterboxd.com/" {
+		t.Fatalf("unexpected error text %q", got)
+	}
+}
+
+func TestDebugPanelListsAppErrors(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.width, m.height = 120, 40
+	updated, _ := m.update(storeSavedMsg{err: errors.New("disk full\nwhile renaming store.json")})
+	m = updated.(Model)
+	if len(m.debugLog) != 1 || m.debugLog[0].Kind != letterboxd.EventError {
+		t.Fatalf("expected the save error in the panel, got %+v", m.debugLog)
+	}
+
+	m.debugPanel = true
+	if view := stripANSI(m.View()); !strings.Contains(view, "ERROR store save · disk full") {
+		t.Fatalf("expected the error listed, got %q", view)
+	}
+	m.debugDetail = true
+	if view := stripANSI(m.View()); !strings.Contains(view, "while renaming store.json") {
+		t.Fatalf("expected the detail to show the whole error, got %q", view)
+	}
+}
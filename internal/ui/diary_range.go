@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+var errDiaryPeriod = errors.New("use a year (2024), a month (2024-03 or Mar 2024), or a span (2024-01..2024-03)")
+
+type diaryRangeMsg struct {
+	items []letterboxd.DiaryEntry
+	err   error
+	from  time.Time
+	to    time.Time
+}
+
+func fetchDiaryRangeCmd(client *letterboxd.Client, username string, from, to time.Time) tea.Cmd {
+	return func() tea.Msg {
+		items, err := client.DiaryRange(username, from, to)
+		return diaryRangeMsg{items: items, err: err, from: from, to: to}
+	}
+}
+
+func (m Model) diaryRangeActive() bool {
+	return !m.diaryFrom.IsZero()
+}
+
+// diaryFetchCmd reloads the Diary tab: the jumped-to span when one is set,
+// otherwise the first page in the current sort.
+func (m *Model) diaryFetchCmd() tea.Cmd {
+	if m.diaryRangeActive() {
+		return fetchDiaryRangeCmd(m.client, m.username, m.diaryFrom, m.diaryTo)
+	}
+	return fetchDiaryCmd(m.client, m.username, 1, m.diarySortParam())
+}
+
+func newDiaryJumpForm(from, to time.Time) filterForm {
+	form := filterForm{
+		id:    filterDiaryJump,
+		title: "Jump to date",
+		fields: []filterField{
+			newFilterTextField("period", "Year or month", "e.g. 2024-03", diaryPeriodLabel(from, to, "2006-01")),
+		},
+	}
+	form.focusField(0)
+	return form
+}
+
+func (m Model) applyDiaryJump() (tea.Model, tea.Cmd) {
+	from, to, err := parseDiaryPeriod(m.filterForm.value("period"))
+	if err != nil {
+		m.filterForm.status = err.Error()
+		m = m.showFilterForm(m.filterForm)
+		return m, nil
+	}
+	m.resetDiaryList()
+	m.diaryFrom, m.diaryTo = from, to
+	return m, m.diaryFetchCmd()
+}
+
+var diaryMonthLayouts = []string{"2006-01", "2006/01", "Jan 2006", "January 2006", "01/2006"}
+
+// parseDiaryPeriod turns "2024", "2024-03" or "2024-01..2024-03" into an
+// inclusive day span. An empty value clears the jump.
+func parseDiaryPeriod(value string) (time.Time, time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, time.Time{}, nil
+	}
+	first, last, isSpan := strings.Cut(value, "..")
+	from, to, err := parseDiaryPeriodPart(first)
+	if err == nil && isSpan {
+		_, to, err = parseDiaryPeriodPart(last)
+	}
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errDiaryPeriod
+	}
+	return from, to, nil
+}
+
+func parseDiaryPeriodPart(value string) (time.Time, time.Time, error) {
+	value = strings.TrimSpace(value)
+	if year, err := time.Parse("2006", value); err == nil {
+		return year, year.AddDate(1, 0, -1), nil
+	}
+	for _, layout := range diaryMonthLayouts {
+		if month, err := time.Parse(layout, value); err == nil {
+			return month, month.AddDate(0, 1, -1), nil
+		}
+	}
+	return time.Time{}, time.Time{}, errDiaryPeriod
+}
+
+func diaryPeriodLabel(from, to time.Time, monthLayout string) string {
+	switch {
+	case from.IsZero():
+		return ""
+	case from.Year() == to.Year() && from.Month() == time.January && to.Month() == time.December:
+		return from.Format("2006")
+	case from.Year() == to.Year() && from.Month() == to.Month():
+		return from.Format(monthLayout)
+	}
+	return from.Format(monthLayout) + ".." + to.Format(monthLayout)
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+func TestParseDiaryPeriod(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	cases := []struct {
+		in       string
+		from, to time.Time
+		label    string
+	}{
+		{in: "2024", from: day(2024, time.January, 1), to: day(2024, time.December, 31), label: "2024"},
+		{in: "2024-02", from: day(2024, time.February, 1), to: day(2024, time.February, 29), label: "2024-02"},
+		{in: "Mar 2023", from: day(2023, time.March, 1), to: day(2023, time.March, 31), label: "2023-03"},
+		{in: "2023-11..2024-01", from: day(2023, time.November, 1), to: day(2024, time.January, 31), label: "2023-11..2024-01"},
+	}
+	for _, tc := range cases {
+		from, to, err := parseDiaryPeriod(tc.in)
+		if err != nil {
+			t.Fatalf("parseDiaryPeriod(%q) error: %v", tc.in, err)
+		}
+		if !from.Equal(tc.from) || !to.Equal(tc.to) {
+			t.Fatalf("parseDiaryPeriod(%q) = %v..%v", tc.in, from, to)
+		}
+		if got := diaryPeriodLabel(from, to, "2006-01"); got != tc.label {
+			t.Fatalf("diaryPeriodLabel(%q) = %q", tc.in, got)
+		}
+	}
+	for _, in := range []string{"soon", "2024-13", "2024-03..2023-01"} {
+		if _, _, err := parseDiaryPeriod(in); err == nil {
+			t.Fatalf("expected error for %q", in)
+		}
+	}
+	if from, _, err := parseDiaryPeriod(" "); err != nil || !from.IsZero() {
+		t.Fatalf("expected empty period to clear, got %v %v", from, err)
+	}
+}
+
+func TestDiaryJumpPrompt(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.activeTab = tabDiary
+	m.loading = false
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'J'}})
+	m = model.(Model)
+	if !m.filterModal || m.filterForm.id != filterDiaryJump {
+		t.Fatalf("expected jump prompt to open")
+	}
+	for _, r := range "nope" {
+		model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = model.(Model)
+	}
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(Model)
+	if !m.filterModal || m.filterForm.status == "" {
+		t.Fatalf("expected invalid period to keep the prompt open with a message")
+	}
+
+	m.filterForm.fields[0].input.SetValue("2024-03")
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(Model)
+	if m.filterModal || cmd == nil {
+		t.Fatalf("expected prompt to close and fetch the range")
+	}
+	if !m.diaryFrom.Equal(time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)) || !m.diaryTo.Equal(time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected range: %v..%v", m.diaryFrom, m.diaryTo)
+	}
+
+	model, _ = m.Update(diaryMsg{items: []letterboxd.DiaryEntry{{Title: "Stale"}}, page: 1})
+	m = model.(Model)
+	if len(m.diary) != 0 {
+		t.Fatalf("expected paged diary results to be ignored while jumped")
+	}
+	model, _ = m.Update(diaryRangeMsg{
+		items: []letterboxd.DiaryEntry{{Title: "Heat", Date: time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC)}},
+		from:  m.diaryFrom,
+		to:    m.diaryTo,
+	})
+	m = model.(Model)
+	if len(m.diary) != 1 || !m.diaryDone {
+		t.Fatalf("expected range results, got %+v", m.diary)
+	}
+	m.width = 80
+	view := stripANSI(renderDiary(m, newTheme()))
+	if !strings.Contains(view, "Showing Mar 2024") || !strings.Contains(view, "Heat") {
+		t.Fatalf("unexpected diary view: %q", view)
+	}
+}
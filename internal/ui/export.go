@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+type exportedMsg struct {
+	path string
+	rows int
+	err  error
+}
+
+func (m Model) exportName() string {
+	switch m.activeTab {
+	case tabDiary:
+		return "diary"
+	case tabWatchlist:
+		return "watchlist"
+	case tabFilms:
+		return "films"
+	}
+	return ""
+}
+
+func (m Model) canExport() bool {
+	return !m.modalOpen() && m.exportName() != "" && len(m.exportRows()) > 1
+}
+
+func newExportForm(name string) filterForm {
+	path := newFilterTextField("path", "File", "e.g. ~/letterboxd-"+name+".csv", "letterboxd-"+name+".csv")
+	path.input.CharLimit = 0
+	form := filterForm{id: filterExport, title: "Export " + name, fields: []filterField{path}}
+	form.focusField(0)
+	return form
+}
+
+func (m Model) openExport() Model {
+	m.exportStatus = ""
+	return m.showFilterForm(newExportForm(m.exportName()))
+}
+
+func (m Model) applyExport() (tea.Model, tea.Cmd) {
+	path := m.filterForm.value("path")
+	if path == "" {
+		m.filterForm.status = "Enter a file to write."
+		m = m.showFilterForm(m.filterForm)
+		return m, nil
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	m.exportStatus = "exporting..."
+	return m, exportCmd(path, m.exportRows())
+}
+
+// exportRows is the loaded rows of the current tab as CSV, with the column
+// names Letterboxd's importer reads. Rows not yet paged in aren't included.
+func (m Model) exportRows() [][]string {
+	var rows [][]string
+	switch m.activeTab {
+	case tabDiary:
+		rows = append(rows, []string{"Title", "Letterboxd URI", "WatchedDate", "Rating", "Rewatch"})
+		for _, entry := range m.diary {
+			date := ""
+			if !entry.Date.IsZero() {
+				date = entry.Date.Format("2006-01-02")
+			}
+			rows = append(rows, []string{entry.Title, filmURI(entry.FilmURL), date, exportRating(entry.Rating), strconv.FormatBool(entry.Rewatch)})
+		}
+	case tabWatchlist:
+		rows = append(rows, []string{"Title", "Year", "Letterboxd URI"})
+		for _, item := range m.watchlist {
+			rows = append(rows, []string{item.Title, item.Year, filmURI(item.FilmURL)})
+		}
+	case tabFilms:
+		rows = append(rows, []string{"Title", "Year", "Letterboxd URI", "Rating", "Liked"})
+		for _, film := range m.films {
+			rows = append(rows, []string{film.Title, film.Year, filmURI(film.FilmURL), exportRating(film.Rating), strconv.FormatBool(film.Liked)})
+		}
+	}
+	return rows
+}
+
+func filmURI(href string) string {
+	if strings.HasPrefix(href, "/") {
+		return letterboxd.BaseURL + href
+	}
+	return href
+}
+
+// exportRating turns stars into the 0.5–5 number the importer expects.
+func exportRating(rating string) string {
+	if value := starsToValue(rating); value > 0 {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return ""
+}
+
+func exportCmd(path string, rows [][]string) tea.Cmd {
+	return func() tea.Msg {
+		return exportedMsg{path: path, rows: len(rows) - 1, err: writeCSV(path, rows)}
+	}
+}
+
+func writeCSV(path string, rows [][]string) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, file.Close())
+	}()
+	w := csv.NewWriter(file)
+	if err := w.WriteAll(rows); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+func (m *Model) applyExported(msg exportedMsg) {
+	if msg.err != nil {
+		m.logError("export", msg.err)
+		m.exportStatus = "export failed: " + firstLine(errorText(msg.err))
+		return
+	}
+	m.exportStatus = fmt.Sprintf("exported %d rows to %s", msg.rows, msg.path)
+}
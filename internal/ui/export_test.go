@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+func TestPaletteExportsTheLoadedDiary(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.activeTab = tabDiary
+	m.diary = []letterboxd.DiaryEntry{
+		{Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Title: "Heat, Again", FilmURL: "/film/heat/", Rating: "★★★★½", Rewatch: true},
+		{Title: "Alien", FilmURL: "/film/alien/"},
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	m = typeKeys(updated.(Model), "export")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if !m.filterModal || m.filterForm.id != filterExport || m.filterForm.value("path") != "letterboxd-diary.csv" {
+		t.Fatalf("expected the export form, got open=%v %q", m.filterModal, m.filterForm.title)
+	}
+
+	path := filepath.Join(t.TempDir(), "diary.csv")
+	m.filterForm.fields[0].input.SetValue(path)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatalf("expected an export command")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if !strings.Contains(m.exportStatus, "exported 2 rows") {
+		t.Fatalf("expected the export reported, got %q", m.exportStatus)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	want := "Title,Letterboxd URI,WatchedDate,Rating,Rewatch\n" +
+		"\"Heat, Again\",https://letterboxd.com/film/heat/,2024-03-01,4.5,true\n" +
+		"Alien,https://letterboxd.com/film/alien/,,,false\n"
+	if string(data) != want {
+		t.Fatalf("unexpected export:\n%s", data)
+	}
+}
+
+func TestPaletteLetsResultsThrough(t *testing.T) {
+	m := NewModel("jane", nil)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	m = updated.(Model)
+	updated, _ = m.Update(exportedMsg{path: "out.csv", rows: 3})
+	m = updated.(Model)
+	if !m.paletteModal || m.exportStatus != "exported 3 rows to out.csv" {
+		t.Fatalf("expected the result handled with the palette open, got %q", m.exportStatus)
+	}
+}
@@ -0,0 +1,304 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+type filterFieldKind int
+
+const (
+	filterFieldText filterFieldKind = iota
+	filterFieldChoice
+)
+
+type filterField struct {
+	key     string
+	label   string
+	kind    filterFieldKind
+	input   textinput.Model
+	options []string
+	index   int
+}
+
+type filterFormID int
+
+const (
+	filterFilms filterFormID = iota
+	filterWatchlist
+	filterRoulette
+	filterDiaryJump
+	filterQueueEdit
+	filterExport
+)
+
+type filterForm struct {
+	id     filterFormID
+	title  string
+	fields []filterField
+	focus  int
+	status string
+}
+
+func newFilterTextField(key, label, placeholder, value string) filterField {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	input.CharLimit = 40
+	input.Width = 24
+	input.SetValue(value)
+	return filterField{key: key, label: label, kind: filterFieldText, input: input}
+}
+
+func newFilterChoiceField(key, label string, options []string, selected string) filterField {
+	field := filterField{key: key, label: label, kind: filterFieldChoice, options: options}
+	for i, option := range options {
+		if option == selected {
+			field.index = i
+		}
+	}
+	return field
+}
+
+func newFilterToggleField(key, label string, on bool) filterField {
+	selected := "no"
+	if on {
+		selected = "yes"
+	}
+	return newFilterChoiceField(key, label, []string{"no", "yes"}, selected)
+}
+
+func (f filterForm) applyIndex() int {
+	return len(f.fields)
+}
+
+func (f filterForm) clearIndex() int {
+	return len(f.fields) + 1
+}
+
+func (f *filterForm) focusField(idx int) {
+	idx = clamp(idx, 0, f.clearIndex())
+	f.focus = idx
+	for i := range f.fields {
+		if f.fields[i].kind != filterFieldText {
+			continue
+		}
+		if i == idx {
+			f.fields[i].input.Focus()
+		} else {
+			f.fields[i].input.Blur()
+		}
+	}
+}
+
+func (f *filterForm) cycle(delta int) bool {
+	if f.focus < 0 || f.focus >= len(f.fields) {
+		return false
+	}
+	field := &f.fields[f.focus]
+	if field.kind != filterFieldChoice || len(field.options) == 0 {
+		return false
+	}
+	field.index = (field.index + delta + len(field.options)) % len(field.options)
+	return true
+}
+
+func (f *filterForm) update(msg tea.Msg) tea.Cmd {
+	if f.focus < 0 || f.focus >= len(f.fields) {
+		return nil
+	}
+	field := &f.fields[f.focus]
+	if field.kind != filterFieldText {
+		return nil
+	}
+	var cmd tea.Cmd
+	field.input, cmd = field.input.Update(msg)
+	return cmd
+}
+
+func (f *filterForm) clear() {
+	for i := range f.fields {
+		f.fields[i].input.SetValue("")
+		f.fields[i].index = 0
+	}
+}
+
+func (f filterForm) value(key string) string {
+	for _, field := range f.fields {
+		if field.key != key {
+			continue
+		}
+		if field.kind == filterFieldText {
+			return strings.TrimSpace(field.input.Value())
+		}
+		if len(field.options) == 0 {
+			return ""
+		}
+		return field.options[clamp(field.index, 0, len(field.options)-1)]
+	}
+	return ""
+}
+
+func (f filterForm) toggled(key string) bool {
+	return f.value(key) == "yes"
+}
+
+func (f *filterForm) setWidth(width int) {
+	target := max(16, min(40, width-24))
+	for i := range f.fields {
+		f.fields[i].input.Width = target
+	}
+}
+
+var ratedOptions = []string{"Any", "5", "4.5+", "4+", "3.5+", "3+", "2+", "1+", "Unrated"}
+
+func ratedSegment(option string) string {
+	switch option {
+	case "", "Any":
+		return ""
+	case "Unrated":
+		return "none"
+	}
+	orHigher := strings.HasSuffix(option, "+")
+	value, err := strconv.ParseFloat(strings.TrimSuffix(option, "+"), 64)
+	if err != nil {
+		return ""
+	}
+	return letterboxd.RatedSegment(value, orHigher)
+}
+
+func ratedOption(segment string) string {
+	for _, option := range ratedOptions {
+		if ratedSegment(option) == segment {
+			return option
+		}
+	}
+	return "Any"
+}
+
+func newFilmsFilterForm(filter letterboxd.FilmsFilter) filterForm {
+	form := filterForm{
+		id:    filterFilms,
+		title: "Filter films",
+		fields: []filterField{
+			newFilterTextField("decade", "Decade", "e.g. 1990s", filter.Decade),
+			newFilterTextField("year", "Year", "e.g. 1994", filter.Year),
+			newFilterTextField("genre", "Genre", "e.g. horror", filter.Genre),
+			newFilterChoiceField("rated", "Rated", ratedOptions, ratedOption(filter.Rated)),
+			newFilterTextField("service", "Service", "e.g. netflix-us", filter.Service),
+			newFilterToggleField("liked", "Liked only", filter.Liked),
+		},
+	}
+	form.focusField(0)
+	return form
+}
+
+func filmsFilterFromForm(form filterForm) letterboxd.FilmsFilter {
+	return letterboxd.FilmsFilter{
+		Decade:  form.value("decade"),
+		Year:    form.value("year"),
+		Genre:   form.value("genre"),
+		Rated:   ratedSegment(form.value("rated")),
+		Service: form.value("service"),
+		Liked:   form.toggled("liked"),
+	}
+}
+
+var releaseOptions = []string{"Any", "Released", "Unreleased"}
+
+func releaseOption(release letterboxd.WatchlistRelease) string {
+	switch release {
+	case letterboxd.WatchlistReleased:
+		return "Released"
+	case letterboxd.WatchlistUnreleased:
+		return "Unreleased"
+	default:
+		return "Any"
+	}
+}
+
+func watchlistRelease(option string) letterboxd.WatchlistRelease {
+	switch option {
+	case "Released":
+		return letterboxd.WatchlistReleased
+	case "Unreleased":
+		return letterboxd.WatchlistUnreleased
+	default:
+		return letterboxd.WatchlistReleaseAny
+	}
+}
+
+func newWatchlistFilterForm(filter letterboxd.WatchlistFilter) filterForm {
+	form := filterForm{
+		id:    filterWatchlist,
+		title: "Filter watchlist",
+		fields: []filterField{
+			newFilterTextField("decade", "Decade", "e.g. 1990s", filter.Decade),
+			newFilterTextField("year", "Year", "e.g. 1994", filter.Year),
+			newFilterTextField("genre", "Genre", "e.g. horror", filter.Genre),
+			newFilterTextField("service", "Service", "e.g. netflix-us", filter.Service),
+			newFilterChoiceField("release", "Release", releaseOptions, releaseOption(filter.Release)),
+		},
+	}
+	form.focusField(0)
+	return form
+}
+
+func watchlistFilterFromForm(form filterForm) letterboxd.WatchlistFilter {
+	return letterboxd.WatchlistFilter{
+		Decade:  form.value("decade"),
+		Year:    form.value("year"),
+		Genre:   form.value("genre"),
+		Service: form.value("service"),
+		Release: watchlistRelease(form.value("release")),
+	}
+}
+
+func filmsFilterSummary(filter letterboxd.FilmsFilter) string {
+	var parts []string
+	if filter.Liked {
+		parts = append(parts, "liked")
+	}
+	parts = append(parts, filterSummaryParts(filter.Decade, filter.Year, filter.Genre, "")...)
+	if filter.Rated != "" {
+		parts = append(parts, "rated "+ratedOption(filter.Rated))
+	}
+	if filter.Service != "" {
+		parts = append(parts, "on "+filter.Service)
+	}
+	if len(parts) == 0 {
+		return "All films"
+	}
+	return strings.Join(parts, " · ")
+}
+
+func watchlistFilterSummary(filter letterboxd.WatchlistFilter) string {
+	parts := filterSummaryParts(filter.Decade, filter.Year, filter.Genre, filter.Service)
+	if filter.Release != letterboxd.WatchlistReleaseAny {
+		parts = append(parts, strings.ToLower(releaseOption(filter.Release)))
+	}
+	if len(parts) == 0 {
+		return "All films"
+	}
+	return strings.Join(parts, " · ")
+}
+
+func filterSummaryParts(decade, year, genre, service string) []string {
+	var parts []string
+	if year != "" {
+		parts = append(parts, year)
+	} else if decade != "" {
+		parts = append(parts, decade)
+	}
+	if genre != "" {
+		parts = append(parts, genre)
+	}
+	if service != "" {
+		parts = append(parts, "on "+service)
+	}
+	return parts
+}
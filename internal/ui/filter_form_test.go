@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+func TestRatedSegment(t *testing.T) {
+	cases := map[string]string{
+		"Any":     "",
+		"5":       "5",
+		"4.5+":    "4.5-5",
+		"3+":      "3-5",
+		"Unrated": "none",
+	}
+	for option, want := range cases {
+		if got := ratedSegment(option); got != want {
+			t.Fatalf("ratedSegment(%q) = %q, want %q", option, got, want)
+		}
+		if got := ratedOption(want); got != option {
+			t.Fatalf("ratedOption(%q) = %q, want %q", want, got, option)
+		}
+	}
+}
+
+func TestFilmsFilterForm(t *testing.T) {
+	filter := letterboxd.FilmsFilter{Decade: "1990s", Genre: "horror", Rated: "4-5", Liked: true}
+	form := newFilmsFilterForm(filter)
+	if got := filmsFilterFromForm(form); got != filter {
+		t.Fatalf("round trip mismatch: %+v", got)
+	}
+	form.focusField(2)
+	form.update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if form.value("genre") != "horro" {
+		t.Fatalf("expected genre edit, got %q", form.value("genre"))
+	}
+	form.focusField(3)
+	if !form.cycle(1) || form.value("rated") != "3.5+" {
+		t.Fatalf("expected rated cycle, got %q", form.value("rated"))
+	}
+	form.clear()
+	if got := filmsFilterFromForm(form); got.Active() {
+		t.Fatalf("expected cleared filter, got %+v", got)
+	}
+}
+
+func TestFilmsFilterSummary(t *testing.T) {
+	if got := filmsFilterSummary(letterboxd.FilmsFilter{}); got != "All films" {
+		t.Fatalf("unexpected empty summary: %q", got)
+	}
+	got := filmsFilterSummary(letterboxd.FilmsFilter{Year: "1994", Decade: "1990s", Genre: "drama", Rated: "5", Liked: true})
+	if got != "liked · 1994 · drama · rated 5" {
+		t.Fatalf("unexpected summary: %q", got)
+	}
+}
+
+func TestWatchlistFilterForm(t *testing.T) {
+	filter := letterboxd.WatchlistFilter{Decade: "1970s", Service: "mubi", Release: letterboxd.WatchlistUnreleased}
+	form := newWatchlistFilterForm(filter)
+	if got := watchlistFilterFromForm(form); got != filter {
+		t.Fatalf("round trip mismatch: %+v", got)
+	}
+	if got := watchlistFilterSummary(filter); got != "1970s · on mubi · unreleased" {
+		t.Fatalf("unexpected summary: %q", got)
+	}
+	if got := watchlistFilterSummary(letterboxd.WatchlistFilter{}); got != "All films" {
+		t.Fatalf("unexpected empty summary: %q", got)
+	}
+}
@@ -0,0 +1,428 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+// The in-list filter narrows whatever the current tab has loaded, without
+// fetching anything. Selection indexes stay relative to the full list so
+// opening a film works the same with or without a filter.
+
+func newFindInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = "/"
+	input.Placeholder = "filter"
+	input.CharLimit = 80
+	return input
+}
+
+func findableTab(m Model) bool {
+	switch m.activeTab {
+	case tabProfile, tabWatchlist, tabActivity, tabFollowing, tabFilms, tabPeople:
+		return true
+	case tabDiary:
+		return !m.diaryHeatmap
+	}
+	return false
+}
+
+func (m Model) findQuery() string {
+	if m.findTab != m.activeTab || !findableTab(m) {
+		return ""
+	}
+	return strings.TrimSpace(m.findInput.Value())
+}
+
+func (m Model) findShown() bool {
+	return m.findQuery() != "" || (m.findTyping && m.findTab == m.activeTab)
+}
+
+func (m *Model) clearFind() {
+	m.findTyping = false
+	m.findInput.Reset()
+	m.findInput.Blur()
+}
+
+func (m Model) startFind() (tea.Model, tea.Cmd) {
+	if m.findTab != m.activeTab {
+		m.clearFind()
+		m.findTab = m.activeTab
+	}
+	m.findTyping = true
+	m.findInput.Focus()
+	m.resizeViewport()
+	m.syncViewportToSelection()
+	return m, textinput.Blink
+}
+
+func (m *Model) handleFindKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.modalOpen() || !findableTab(*m) {
+		return nil, false
+	}
+	if m.findTyping && m.findTab == m.activeTab {
+		switch {
+		case key.Matches(msg, m.keys.QuitAll):
+			return nil, false
+		case key.Matches(msg, m.keys.Select):
+			m.findTyping = false
+			m.findInput.Blur()
+		case key.Matches(msg, m.keys.Cancel):
+			m.clearFind()
+		default:
+			var cmd tea.Cmd
+			m.findInput, cmd = m.findInput.Update(msg)
+			m.snapToMatch()
+			m.syncViewportToSelection()
+			return cmd, true
+		}
+		m.resizeViewport()
+		m.syncViewportToSelection()
+		return nil, true
+	}
+	switch {
+	case m.findQuery() == "":
+		return nil, false
+	case key.Matches(msg, m.keys.NextMatch):
+		m.jumpMatch(1)
+	case key.Matches(msg, m.keys.PrevMatch):
+		m.jumpMatch(-1)
+	case key.Matches(msg, m.keys.Cancel):
+		m.clearFind()
+		m.resizeViewport()
+	default:
+		return nil, false
+	}
+	m.syncViewportToSelection()
+	return nil, true
+}
+
+// findHits matches text against the active filter. It reports true with no
+// hits when no filter is active.
+func (m Model) findHits(text string) ([]int, bool) {
+	query := m.findQuery()
+	if query == "" {
+		return nil, true
+	}
+	return fuzzyMatch(text, query)
+}
+
+// findMatches lists the selectable indexes on the current tab that match the
+// filter. The second result is false when no filter is active.
+func (m Model) findMatches() ([]int, bool) {
+	if m.findQuery() == "" {
+		return nil, false
+	}
+	matches := []int{}
+	match := func(i int, ok bool) {
+		if ok {
+			matches = append(matches, i)
+		}
+	}
+	switch m.activeTab {
+	case tabProfile:
+		// The profile view only keeps matching recents, so every recent entry
+		// left in it is a match.
+		matches := []int{}
+		for i, entry := range profileSelectionEntries(m.profileView()) {
+			if entry.recent {
+				matches = append(matches, i)
+			}
+		}
+		return matches, true
+	case tabDiary:
+		for i, entry := range m.diary {
+			_, _, ok := m.findFilm(entry.Title, entry.FilmURL)
+			match(i, ok)
+		}
+	case tabWatchlist:
+		for i, item := range m.watchlist {
+			_, _, ok := m.findFilm(filmLabel(item.Title, item.Year), item.FilmURL)
+			match(i, ok)
+		}
+	case tabFilms:
+		for i, film := range m.films {
+			_, _, ok := m.findFilm(filmLabel(film.Title, film.Year), film.FilmURL)
+			match(i, ok)
+		}
+	case tabActivity:
+		for i, item := range m.activity {
+			_, ok := m.findHits(activityText(item))
+			match(i, ok)
+		}
+	case tabFollowing:
+		for i, item := range m.following {
+			_, ok := m.findHits(activityText(item))
+			match(i, ok)
+		}
+	case tabPeople:
+		for i, member := range m.people {
+			_, ok := m.findHits(memberText(member))
+			match(i, ok)
+		}
+	}
+	return matches, true
+}
+
+// findFilm matches a film row by its label or, failing that, by the cast of
+// the film's saved details. A cast match returns the actor instead of hits
+// in label.
+func (m Model) findFilm(label, filmURL string) ([]int, string, bool) {
+	hits, ok := m.findHits(label)
+	if ok || m.store == nil {
+		return hits, "", ok
+	}
+	film, stored := m.store.Film(filmURL)
+	if !stored {
+		return nil, "", false
+	}
+	for _, actor := range film.Cast {
+		if _, ok := m.findHits(actor); ok {
+			return nil, actor, true
+		}
+	}
+	return nil, "", false
+}
+
+// memberText is a People row as text: the display name, if it differs, then
+// the @username.
+func memberText(member letterboxd.Member) string {
+	if member.DisplayName != "" && member.DisplayName != member.Username {
+		return member.DisplayName + " @" + member.Username
+	}
+	return "@" + member.Username
+}
+
+func (m *Model) findList() *listState {
+	switch m.activeTab {
+	case tabProfile:
+		return &m.profileList
+	case tabDiary:
+		return &m.diaryList
+	case tabWatchlist:
+		return &m.watchList
+	case tabActivity:
+		return &m.actList
+	case tabFollowing:
+		return &m.followList
+	case tabFilms:
+		return &m.filmsList
+	case tabPeople:
+		return &m.peopleList
+	}
+	return nil
+}
+
+// matchPosition returns the position of the first match at or after
+// selected, and whether selected is itself a match.
+func matchPosition(matches []int, selected int) (int, bool) {
+	pos, exact := slices.BinarySearch(matches, selected)
+	return min(pos, len(matches)-1), exact
+}
+
+// moveMatch moves the selection by delta matches, clamping at either end. A
+// selection that isn't a match snaps to the nearest one first.
+func (m *Model) moveMatch(matches []int, delta int) {
+	list := m.findList()
+	if list == nil || len(matches) == 0 {
+		return
+	}
+	pos, exact := matchPosition(matches, list.selected)
+	if exact {
+		pos += delta
+	}
+	list.selected = matches[clamp(pos, 0, len(matches)-1)]
+}
+
+// jumpMatch moves to the next (dir > 0) or previous match, wrapping around.
+func (m *Model) jumpMatch(dir int) {
+	matches, _ := m.findMatches()
+	list := m.findList()
+	if list == nil || len(matches) == 0 {
+		return
+	}
+	pos, exact := matchPosition(matches, list.selected)
+	switch {
+	case dir > 0 && (exact || matches[pos] < list.selected):
+		pos++
+	case dir < 0 && (exact || matches[pos] > list.selected):
+		pos--
+	}
+	list.selected = matches[(pos+len(matches))%len(matches)]
+}
+
+func (m *Model) snapToMatch() {
+	if matches, ok := m.findMatches(); ok {
+		m.moveMatch(matches, 0)
+	}
+}
+
+// profileView is the profile as shown on the Profile tab, with recents
+// narrowed to those matching the filter.
+func (m Model) profileView() letterboxd.Profile {
+	if m.findQuery() == "" {
+		return m.profile
+	}
+	profile := m.profile
+	profile.Recent = nil
+	for _, recent := range m.profile.Recent {
+		if _, ok := m.findHits(profileRecentText(recent)); ok {
+			profile.Recent = append(profile.Recent, recent)
+		}
+	}
+	return profile
+}
+
+func profileRecentText(recent letterboxd.ProfileRecent) string {
+	if recent.Summary != "" {
+		return recent.Summary
+	}
+	return recent.FilmURL
+}
+
+// activityText is the plain text of an activity summary, laid out the same way
+// renderSummary lays out its styled parts.
+func activityText(item letterboxd.ActivityItem) string {
+	if len(item.Parts) == 0 {
+		if s := compactSpaces(item.Summary); s != "" {
+			return s
+		}
+		return compactSpaces(strings.Join([]string{item.Actor, describeKind(item.Kind), item.Title}, " "))
+	}
+	var out strings.Builder
+	for _, part := range item.Parts {
+		if text := compactSpaces(part.Text); text != "" {
+			appendWithSpacing(&out, text)
+		}
+	}
+	return strings.TrimSpace(out.String())
+}
+
+// filterActivity narrows items to the filter's matches and maps selected to
+// its position among them.
+func (m Model) filterActivity(items []letterboxd.ActivityItem, selected int) ([]letterboxd.ActivityItem, int) {
+	if m.findQuery() == "" {
+		return items, selected
+	}
+	var shown []letterboxd.ActivityItem
+	position := -1
+	for i, item := range items {
+		if _, ok := m.findHits(activityText(item)); !ok {
+			continue
+		}
+		if i == selected {
+			position = len(shown)
+		}
+		shown = append(shown, item)
+	}
+	return shown, position
+}
+
+func renderFindLine(m Model, theme themeStyles) string {
+	if !m.findShown() {
+		return ""
+	}
+	line := m.findInput.View()
+	if query := m.findQuery(); query != "" {
+		matches, _ := m.findMatches()
+		line += theme.subtle.Render(fmt.Sprintf(" · %d %s", len(matches), pluralMatches(len(matches))))
+		if !m.findTyping {
+			line += theme.subtle.Render(fmt.Sprintf(" · %s/%s next/prev · %s to clear", keyHint(m.keys.NextMatch), keyHint(m.keys.PrevMatch), keyHint(m.keys.Cancel)))
+		}
+	}
+	return line
+}
+
+func pluralMatches(n int) string {
+	if n == 1 {
+		return "match"
+	}
+	return "matches"
+}
+
+// fuzzyMatch matches each whitespace-separated term of query against text,
+// case-insensitively, as an in-order run of characters. A term must land
+// within three times its own length so short queries don't match nearly
+// every line. It returns the matched rune positions.
+func fuzzyMatch(text, query string) ([]int, bool) {
+	runes := []rune(text)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	var hits []int
+	for _, term := range strings.Fields(query) {
+		positions, ok := matchTerm(runes, []rune(strings.ToLower(term)))
+		if !ok {
+			return nil, false
+		}
+		hits = append(hits, positions...)
+	}
+	slices.Sort(hits)
+	return slices.Compact(hits), true
+}
+
+func matchTerm(text, term []rune) ([]int, bool) {
+	var best []int
+	for start := range text {
+		if text[start] != term[0] {
+			continue
+		}
+		positions := []int{start}
+		for i := start + 1; i < len(text) && len(positions) < len(term); i++ {
+			if text[i] == term[len(positions)] {
+				positions = append(positions, i)
+			}
+		}
+		if len(positions) < len(term) {
+			break
+		}
+		if best == nil || positions[len(positions)-1]-start < best[len(best)-1]-best[0] {
+			best = positions
+		}
+	}
+	if best == nil || best[len(best)-1]-best[0]+1 > 3*len(term) {
+		return nil, false
+	}
+	return best, true
+}
+
+// markMatches renders text with base, picking out the runes at hits (offset
+// by offset) with mark.
+func markMatches(text string, hits []int, offset int, base, mark lipgloss.Style) string {
+	if len(hits) == 0 {
+		return base.Render(text)
+	}
+	var out strings.Builder
+	var run []rune
+	runMarked := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if runMarked {
+			out.WriteString(mark.Render(string(run)))
+		} else {
+			out.WriteString(base.Render(string(run)))
+		}
+		run = run[:0]
+	}
+	for i, r := range []rune(text) {
+		_, marked := slices.BinarySearch(hits, offset+i)
+		if marked != runMarked {
+			flush()
+			runMarked = marked
+		}
+		run = append(run, r)
+	}
+	flush()
+	return out.String()
+}
@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+	"github.com/solean/letterboxd-tui/internal/store"
+)
+
+func typeKeys(m Model, keys string) Model {
+	for _, r := range keys {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	return m
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	hits, ok := fuzzyMatch("The Godfather Part II", "gdfthr")
+	if !ok || !slices.Equal(hits, []int{4, 6, 7, 9, 10, 12}) {
+		t.Fatalf("unexpected hits %v %v", hits, ok)
+	}
+	if _, ok := fuzzyMatch("Heat", "HEAT"); !ok {
+		t.Fatalf("expected case-insensitive match")
+	}
+	if _, ok := fuzzyMatch("jane watched Alien", "alien jane"); !ok {
+		t.Fatalf("expected terms to match independently")
+	}
+	if _, ok := fuzzyMatch("a long line ending in t", "at"); ok {
+		t.Fatalf("expected a scattered match to be rejected")
+	}
+	if _, ok := fuzzyMatch("Heat", "heats"); ok {
+		t.Fatalf("expected a longer query not to match")
+	}
+}
+
+func TestFindFiltersDiaryAndJumpsBetweenMatches(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.width = 80
+	m.activeTab = tabDiary
+	m.lastTab = tabDiary
+	m.diary = []letterboxd.DiaryEntry{
+		{Title: "Alien", FilmURL: "https://letterboxd.com/film/alien/"},
+		{Title: "Heat", FilmURL: "https://letterboxd.com/film/heat/"},
+		{Title: "Aliens", FilmURL: "https://letterboxd.com/film/aliens/"},
+		{Title: "Alien 3", FilmURL: "https://letterboxd.com/film/alien-3/"},
+	}
+	m.diaryList.selected = 1
+
+	m = typeKeys(m, "/alien")
+	if m.diaryList.selected != 2 {
+		t.Fatalf("expected selection to snap to the next match, got %d", m.diaryList.selected)
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.findTyping || m.findQuery() != "alien" {
+		t.Fatalf("expected enter to keep the filter, got typing=%v query=%q", m.findTyping, m.findQuery())
+	}
+	view := stripANSI(renderDiary(m, newTheme()))
+	if strings.Contains(view, "Heat") || !strings.Contains(view, "> ") || !strings.Contains(view, "Aliens") {
+		t.Fatalf("unexpected filtered diary: %q", view)
+	}
+
+	m = typeKeys(m, "n")
+	if m.diaryList.selected != 3 {
+		t.Fatalf("expected next match, got %d", m.diaryList.selected)
+	}
+	m = typeKeys(m, "n")
+	if m.diaryList.selected != 0 {
+		t.Fatalf("expected n to wrap, got %d", m.diaryList.selected)
+	}
+	m = typeKeys(m, "N")
+	if m.diaryList.selected != 3 {
+		t.Fatalf("expected N to wrap back, got %d", m.diaryList.selected)
+	}
+	m = typeKeys(m, "k")
+	if m.diaryList.selected != 2 {
+		t.Fatalf("expected k to skip non-matches, got %d", m.diaryList.selected)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.activeTab != tabFilm || m.film.URL != "https://letterboxd.com/film/aliens/" {
+		t.Fatalf("expected the selected match to open, got %v %q", m.activeTab, m.film.URL)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.activeTab != tabDiary || m.findQuery() != "alien" {
+		t.Fatalf("expected the filter to survive the film view, got %v %q", m.activeTab, m.findQuery())
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.findQuery() != "" || !strings.Contains(stripANSI(renderDiary(m, newTheme())), "Heat") {
+		t.Fatalf("expected esc to clear the filter")
+	}
+}
+
+func TestFindFiltersActivityAndProfileRecents(t *testing.T) {
+	m := NewModel("jane", nil)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = updated.(Model)
+	m.activeTab = tabActivity
+	m.activity = []letterboxd.ActivityItem{
+		{Parts: []letterboxd.SummaryPart{{Kind: "user", Text: "bob"}, {Text: "watched"}, {Kind: "movie", Text: "Heat"}}},
+		{Parts: []letterboxd.SummaryPart{{Kind: "user", Text: "carol"}, {Text: "liked"}, {Kind: "movie", Text: "Alien"}}},
+	}
+	m = typeKeys(m, "/carol")
+	if m.actList.selected != 1 {
+		t.Fatalf("expected the actor match to be selected, got %d", m.actList.selected)
+	}
+	view := stripANSI(m.View())
+	if strings.Contains(view, "Heat") || !strings.Contains(view, "> ") || !strings.Contains(view, "carol liked Alien") {
+		t.Fatalf("unexpected filtered activity: %q", view)
+	}
+
+	m = NewModel("jane", nil)
+	m.width = 100
+	m.profile = letterboxd.Profile{Recent: []letterboxd.ProfileRecent{
+		{Summary: "jane watched Heat", FilmURL: "https://letterboxd.com/film/heat/"},
+		{Summary: "jane watched Alien", FilmURL: "https://letterboxd.com/film/alien/"},
+	}}
+	m = typeKeys(m, "/alien")
+	if entry, ok := m.selectedProfileEntry(); !ok || entry.filmURL != "https://letterboxd.com/film/alien/" {
+		t.Fatalf("expected the matching recent to be selected, got %+v", entry)
+	}
+	if view := stripANSI(renderProfile(m, newTheme())); strings.Contains(view, "Heat") {
+		t.Fatalf("expected non-matching recents to be hidden: %q", view)
+	}
+}
+
+func TestSearchTabStillReachableFromFilterableTabs(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.activeTab = tabDiary
+	m = typeKeys(m, "S")
+	if m.activeTab != tabSearch || !m.searchFocusInput {
+		t.Fatalf("expected S to open search, got %v", m.activeTab)
+	}
+	m = typeKeys(m, "S/")
+	if m.searchInput.Value() != "S/" {
+		t.Fatalf("expected search input to accept S and /, got %q", m.searchInput.Value())
+	}
+}
+
+func TestFindMatchesSavedCastAndFilmsAndPeopleTabs(t *testing.T) {
+	s, err := store.OpenFile(filepath.Join(t.TempDir(), "jane.json"), "jane")
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	s.PutFilm(letterboxd.Film{Title: "Heat", URL: "https://letterboxd.com/film/heat/", Cast: []string{"Al Pacino", "Robert De Niro"}})
+	m := NewModel("jane", nil)
+	m.store = s
+	m.width = 80
+	m.activeTab = tabFilms
+	m.lastTab = tabFilms
+	m.films = []letterboxd.UserFilm{
+		{Title: "Alien", FilmURL: "https://letterboxd.com/film/alien/"},
+		{Title: "Heat", FilmURL: "https://letterboxd.com/film/heat/"},
+	}
+
+	m = typeKeys(m, "/pacino")
+	if matches, _ := m.findMatches(); !slices.Equal(matches, []int{1}) || m.filmsList.selected != 1 {
+		t.Fatalf("expected Heat to match through its cast, got %v at %d", matches, m.filmsList.selected)
+	}
+	if view := stripANSI(renderFilms(m, m.theme)); !strings.Contains(view, "Heat · Al Pacino") || strings.Contains(view, "Alien") {
+		t.Fatalf("expected only Heat, noting the actor, got %q", view)
+	}
+
+	m.clearFind()
+	m.activeTab = tabPeople
+	m.people = []letterboxd.Member{{Username: "bob"}, {Username: "ann", DisplayName: "Ann Lee"}}
+	m = typeKeys(m, "/ann")
+	if matches, _ := m.findMatches(); !slices.Equal(matches, []int{1}) {
+		t.Fatalf("expected the member filter to match ann, got %v", matches)
+	}
+}
@@ -0,0 +1,376 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/cancelreader"
+	"golang.org/x/term"
+)
+
+// Graphics is how posters are drawn in the film view.
+type Graphics int
+
+const (
+	GraphicsOff Graphics = iota
+	// GraphicsBlocks draws two pixels per cell with half-block characters
+	// and works in any terminal with color.
+	GraphicsBlocks
+	GraphicsKitty
+	GraphicsITerm
+	GraphicsSixel
+)
+
+var graphicsNames = map[Graphics]string{
+	GraphicsOff:    "off",
+	GraphicsBlocks: "blocks",
+	GraphicsKitty:  "kitty",
+	GraphicsITerm:  "iterm",
+	GraphicsSixel:  "sixel",
+}
+
+func (g Graphics) String() string {
+	return graphicsNames[g]
+}
+
+// ParseGraphics reads the config's posters setting. An empty name or "auto"
+// reports auto, meaning the caller should use DetectGraphics.
+func ParseGraphics(name string) (g Graphics, auto bool, err error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == "auto" {
+		return GraphicsOff, true, nil
+	}
+	for g, known := range graphicsNames {
+		if known == name {
+			return g, false, nil
+		}
+	}
+	return GraphicsOff, false, fmt.Errorf("posters: unknown mode %q (have auto, off, blocks, kitty, iterm, sixel)", name)
+}
+
+// DetectGraphics picks the best protocol the terminal is known to support
+// from its environment, asking querySixel only when that says nothing.
+func DetectGraphics(getenv func(string) string, querySixel func() bool) Graphics {
+	termName := strings.ToLower(getenv("TERM"))
+	program := strings.ToLower(getenv("TERM_PROGRAM"))
+	switch {
+	case getenv("NO_COLOR") != "":
+		return GraphicsOff
+	case getenv("TMUX") != "" || strings.HasPrefix(termName, "screen"):
+		// Multiplexers need passthrough for images and redraw over them.
+		return GraphicsBlocks
+	case getenv("KITTY_WINDOW_ID") != "" || termName == "xterm-kitty" ||
+		termName == "xterm-ghostty" || program == "ghostty":
+		return GraphicsKitty
+	case program == "iterm.app" || program == "wezterm":
+		return GraphicsITerm
+	case strings.HasPrefix(termName, "foot") || strings.Contains(termName, "mlterm"):
+		return GraphicsSixel
+	case querySixel != nil && querySixel():
+		return GraphicsSixel
+	}
+	return GraphicsBlocks
+}
+
+// QuerySixel asks the terminal for its device attributes and reports
+// whether sixel graphics are among them. It must run before the program
+// takes over the terminal.
+func QuerySixel(in, out *os.File) bool {
+	if runtime.GOOS == "windows" {
+		// Reads there can't be cancelled, so a silent terminal would leave a
+		// reader behind that steals keys.
+		return false
+	}
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return false
+	}
+	defer term.Restore(int(in.Fd()), state)
+	reader, err := cancelreader.NewReader(in)
+	if err != nil {
+		return false
+	}
+	defer reader.Close()
+	if _, err := out.WriteString("\x1b[c"); err != nil {
+		return false
+	}
+	reply := make(chan string, 1)
+	go func() {
+		var resp []byte
+		buf := make([]byte, 64)
+		for {
+			n, err := reader.Read(buf)
+			resp = append(resp, buf[:n]...)
+			if err != nil || bytes.IndexByte(resp, 'c') >= 0 {
+				reply <- string(resp)
+				return
+			}
+		}
+	}()
+	select {
+	case resp := <-reply:
+		return da1HasSixel(resp)
+	case <-time.After(200 * time.Millisecond):
+		reader.Cancel()
+		<-reply
+		return false
+	}
+}
+
+// da1HasSixel looks for attribute 4 in a primary device attributes reply
+// such as "\x1b[?62;4;22c".
+func da1HasSixel(resp string) bool {
+	start := strings.Index(resp, "\x1b[?")
+	if start < 0 {
+		return false
+	}
+	resp = resp[start+3:]
+	end := strings.IndexByte(resp, 'c')
+	if end < 0 {
+		return false
+	}
+	for _, attr := range strings.Split(resp[:end], ";") {
+		if attr == "4" {
+			return true
+		}
+	}
+	return false
+}
+
+// Terminals rarely report their cell size, so images are scaled for a
+// typical one; kitty and iTerm fit them to the cells anyway.
+const (
+	cellPixelWidth  = 10
+	cellPixelHeight = 20
+)
+
+// kittyImageID tags the poster image; only one is ever on screen, so a new
+// poster simply replaces the last.
+const kittyImageID = 0x4c4254
+
+// kittyDiacritics encode row numbers for kitty's Unicode placeholders, in
+// the order kitty's rowcolumn-diacritics table gives them.
+var kittyDiacritics = []rune{
+	0x0305, 0x030D, 0x030E, 0x0310, 0x0312, 0x033D, 0x033E, 0x033F,
+	0x0346, 0x034A, 0x034B, 0x034C, 0x0350, 0x0351, 0x0352, 0x0357,
+	0x035B, 0x0363, 0x0364, 0x0365, 0x0366, 0x0367, 0x0368, 0x0369,
+	0x036A, 0x036B, 0x036C, 0x036D, 0x036E, 0x036F, 0x0483, 0x0484,
+	0x0485, 0x0486, 0x0487, 0x0592, 0x0593, 0x0594, 0x0595, 0x0597,
+}
+
+// fitPoster sizes img in cells to fit within maxCols by maxRows, cells
+// being about twice as tall as they are wide.
+func fitPoster(img image.Image, maxCols, maxRows int) (int, int) {
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 || maxCols <= 0 || maxRows <= 0 {
+		return 0, 0
+	}
+	aspect := float64(bounds.Dy()) / float64(bounds.Dx())
+	cols := maxCols
+	rows := int(math.Round(float64(cols) * aspect / 2))
+	if rows > maxRows {
+		rows = maxRows
+		cols = int(math.Round(float64(rows) * 2 / aspect))
+	}
+	return max(1, cols), max(1, rows)
+}
+
+// renderPoster draws img over cols by rows cells. Every line is exactly cols
+// wide as far as layout is concerned; protocol escapes take no width.
+func renderPoster(img image.Image, g Graphics, cols, rows int) string {
+	if img == nil || cols <= 0 || rows <= 0 {
+		return ""
+	}
+	switch g {
+	case GraphicsBlocks:
+		return renderHalfBlocks(scaleImage(img, cols, rows*2))
+	case GraphicsKitty:
+		return renderKitty(scaleImage(img, cols*cellPixelWidth, rows*cellPixelHeight), cols, rows)
+	case GraphicsITerm:
+		data := encodePNG(scaleImage(img, cols*cellPixelWidth, rows*cellPixelHeight))
+		seq := fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=0:%s\a",
+			len(data), cols, rows, base64.StdEncoding.EncodeToString(data))
+		return overlayImage(seq, cols, rows)
+	case GraphicsSixel:
+		return overlayImage(encodeSixel(scaleImage(img, cols*cellPixelWidth, rows*cellPixelHeight)), cols, rows)
+	}
+	return ""
+}
+
+// overlayImage reserves the cells with spaces and draws the image over them
+// from the end of the last row: text written later over the image's cells
+// would erase it, so the image has to come after all of them.
+func overlayImage(seq string, cols, rows int) string {
+	blank := strings.Repeat(" ", cols)
+	lines := make([]string, rows)
+	for i := range lines {
+		lines[i] = blank
+	}
+	move := fmt.Sprintf("\x1b[%dD", cols)
+	if rows > 1 {
+		move = fmt.Sprintf("\x1b[%dA", rows-1) + move
+	}
+	lines[rows-1] += "\x1b7" + move + seq + "\x1b8"
+	return strings.Join(lines, "\n")
+}
+
+// renderKitty sends the image as a virtual placement and lays it out with
+// Unicode placeholders, which redraw and clear like ordinary text.
+func renderKitty(img image.Image, cols, rows int) string {
+	rows = min(rows, len(kittyDiacritics))
+	payload := base64.StdEncoding.EncodeToString(encodePNG(img))
+	var b strings.Builder
+	const chunk = 4096
+	for i := 0; i < len(payload); i += chunk {
+		end := min(len(payload), i+chunk)
+		more := 0
+		if end < len(payload) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,q=2,f=100,U=1,i=%d,c=%d,r=%d,m=%d;%s\x1b\\", kittyImageID, cols, rows, more, payload[i:end])
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, payload[i:end])
+		}
+	}
+	transmit := b.String()
+	fg := fmt.Sprintf("\x1b[38;2;%d;%d;%dm", kittyImageID>>16&0xff, kittyImageID>>8&0xff, kittyImageID&0xff)
+	lines := make([]string, rows)
+	for row := range lines {
+		// The first cell names its row and column; the rest of the row
+		// continues from it.
+		line := fg + string([]rune{0x10EEEE, kittyDiacritics[row], kittyDiacritics[0]}) +
+			strings.Repeat(string(rune(0x10EEEE)), cols-1) + "\x1b[39m"
+		if row == 0 {
+			line = transmit + line
+		}
+		lines[row] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderHalfBlocks(img image.Image) string {
+	bounds := img.Bounds()
+	var lines []string
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
+		var b strings.Builder
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			style := lipgloss.NewStyle().Foreground(hexColor(img.At(x, y)))
+			if y+1 < bounds.Max.Y {
+				style = style.Background(hexColor(img.At(x, y+1)))
+			}
+			b.WriteString(style.Render("▀"))
+		}
+		lines = append(lines, b.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func hexColor(c color.Color) lipgloss.Color {
+	r, g, b, _ := c.RGBA()
+	return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8))
+}
+
+// scaleImage resizes img to w by h by averaging the source pixels under
+// each target pixel, which keeps downscaled posters from shimmering.
+func scaleImage(img image.Image, w, h int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	src := img.Bounds()
+	for y := 0; y < h; y++ {
+		y0 := src.Min.Y + y*src.Dy()/h
+		y1 := max(y0+1, src.Min.Y+(y+1)*src.Dy()/h)
+		for x := 0; x < w; x++ {
+			x0 := src.Min.X + x*src.Dx()/w
+			x1 := max(x0+1, src.Min.X+(x+1)*src.Dx()/w)
+			var r, g, b, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, _ := img.At(sx, sy).RGBA()
+					r, g, b, n = r+uint64(cr), g+uint64(cg), b+uint64(cb), n+1
+				}
+			}
+			dst.Set(x, y, color.RGBA{uint8(r / n >> 8), uint8(g / n >> 8), uint8(b / n >> 8), 0xff})
+		}
+	}
+	return dst
+}
+
+func encodePNG(img image.Image) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// encodeSixel writes img as sixel data using a 6×6×6 color cube, which is
+// plenty at poster sizes.
+func encodeSixel(img *image.RGBA) string {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	index := func(x, y int) int {
+		c := img.RGBAAt(x, y)
+		return int(c.R)*5/255*36 + int(c.G)*5/255*6 + int(c.B)*5/255
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\x1bP0;1;0q\"1;1;%d;%d", w, h)
+	for i := 0; i < 216; i++ {
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, i/36*20, i/6%6*20, i%6*20)
+	}
+	for band := 0; band < h; band += 6 {
+		used := map[int]bool{}
+		var order []int
+		for y := band; y < min(h, band+6); y++ {
+			for x := 0; x < w; x++ {
+				if c := index(x, y); !used[c] {
+					used[c] = true
+					order = append(order, c)
+				}
+			}
+		}
+		for i, c := range order {
+			if i > 0 {
+				b.WriteByte('$')
+			}
+			fmt.Fprintf(&b, "#%d", c)
+			run, last := 0, byte(0)
+			flush := func() {
+				switch {
+				case run > 3:
+					fmt.Fprintf(&b, "!%d%c", run, last)
+				case run > 0:
+					b.WriteString(strings.Repeat(string(last), run))
+				}
+			}
+			for x := 0; x < w; x++ {
+				bits := 0
+				for dy := 0; dy < 6 && band+dy < h; dy++ {
+					if index(x, band+dy) == c {
+						bits |= 1 << dy
+					}
+				}
+				ch := byte(63 + bits)
+				if ch == last && run > 0 {
+					run++
+					continue
+				}
+				flush()
+				run, last = 1, ch
+			}
+			flush()
+		}
+		b.WriteByte('-')
+	}
+	b.WriteString("\x1b\\")
+	return b.String()
+}
@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+func testPoster() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 60))
+	for y := 0; y < 60; y++ {
+		for x := 0; x < 40; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 6), uint8(y * 4), 128, 0xff})
+		}
+	}
+	return img
+}
+
+func TestParseGraphics(t *testing.T) {
+	if _, auto, err := ParseGraphics(""); !auto || err != nil {
+		t.Fatalf("expected empty to mean auto, got %v %v", auto, err)
+	}
+	if g, auto, err := ParseGraphics(" Kitty "); g != GraphicsKitty || auto || err != nil {
+		t.Fatalf("unexpected kitty parse: %v %v %v", g, auto, err)
+	}
+	if g, _, err := ParseGraphics("off"); g != GraphicsOff || err != nil {
+		t.Fatalf("unexpected off parse: %v %v", g, err)
+	}
+	if _, _, err := ParseGraphics("ascii"); err == nil {
+		t.Fatalf("expected unknown mode to fail")
+	}
+}
+
+func TestDetectGraphics(t *testing.T) {
+	cases := []struct {
+		env   map[string]string
+		sixel bool
+		want  Graphics
+	}{
+		{map[string]string{"TERM": "xterm-kitty"}, false, GraphicsKitty},
+		{map[string]string{"TERM": "xterm-256color", "KITTY_WINDOW_ID": "1"}, false, GraphicsKitty},
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, false, GraphicsITerm},
+		{map[string]string{"TERM": "foot"}, false, GraphicsSixel},
+		{map[string]string{"TERM": "xterm-256color"}, true, GraphicsSixel},
+		{map[string]string{"TERM": "xterm-256color"}, false, GraphicsBlocks},
+		{map[string]string{"TERM": "xterm-kitty", "TMUX": "/tmp/tmux"}, true, GraphicsBlocks},
+		{map[string]string{"TERM": "xterm-kitty", "NO_COLOR": "1"}, false, GraphicsOff},
+	}
+	for _, tc := range cases {
+		getenv := func(key string) string { return tc.env[key] }
+		if got := DetectGraphics(getenv, func() bool { return tc.sixel }); got != tc.want {
+			t.Fatalf("DetectGraphics(%v) = %v, want %v", tc.env, got, tc.want)
+		}
+	}
+}
+
+func TestDA1HasSixel(t *testing.T) {
+	if !da1HasSixel("\x1b[?62;4;6;22c") {
+		t.Fatalf("expected attribute 4 to mean sixel")
+	}
+	if da1HasSixel("\x1b[?62;22;44c") || da1HasSixel("garbage") {
+		t.Fatalf("expected no sixel")
+	}
+}
+
+func TestRenderPosterKeepsCellSize(t *testing.T) {
+	img := testPoster()
+	cols, rows := fitPoster(img, 20, 40)
+	if cols != 20 || rows != 15 {
+		t.Fatalf("expected 20x15 cells, got %dx%d", cols, rows)
+	}
+	if cols, rows := fitPoster(img, 20, 6); rows != 6 || cols != 8 {
+		t.Fatalf("expected height to limit the poster, got %dx%d", cols, rows)
+	}
+	for _, g := range []Graphics{GraphicsBlocks, GraphicsKitty, GraphicsITerm, GraphicsSixel} {
+		art := renderPoster(img, g, cols, rows)
+		lines := strings.Split(art, "\n")
+		if len(lines) != rows {
+			t.Fatalf("%v: expected %d lines, got %d", g, rows, len(lines))
+		}
+		for _, line := range lines {
+			if w := lipgloss.Width(line); w != cols {
+				t.Fatalf("%v: expected lines %d wide, got %d", g, cols, w)
+			}
+		}
+	}
+	if art := renderPoster(img, GraphicsKitty, cols, rows); !strings.Contains(art, "\x1b_Ga=T") || !strings.Contains(art, "\U0010EEEE") {
+		t.Fatalf("expected kitty transmission and placeholders")
+	}
+	if art := renderPoster(img, GraphicsSixel, cols, rows); !strings.Contains(art, "\x1bP0;1;0q") {
+		t.Fatalf("expected sixel data")
+	}
+}
+
+func TestFilmViewShowsPosterBesideText(t *testing.T) {
+	updated, _ := NewModel("jane", nil).WithPosters(nil, GraphicsBlocks).Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m := updated.(Model)
+	m = m.openFilm("https://letterboxd.com/film/heat/")
+	film := letterboxd.Film{Title: "Heat", URL: "https://letterboxd.com/film/heat/", PosterURL: "https://a.ltrbxd.com/heat.jpg"}
+	updated, _ = m.Update(filmMsg{film: film})
+	m = updated.(Model)
+	before := m.modalVP.Width
+
+	updated, _ = m.Update(posterMsg{url: "https://a.ltrbxd.com/other.jpg", img: testPoster()})
+	m = updated.(Model)
+	if m.modalVP.Width != before {
+		t.Fatalf("expected a poster for another film to be ignored")
+	}
+	updated, _ = m.Update(posterMsg{url: film.PosterURL, img: testPoster()})
+	m = updated.(Model)
+	if m.modalVP.Width >= before || !strings.Contains(stripANSI(m.View()), "▀▀▀") {
+		t.Fatalf("expected the poster to sit beside the text, width %d -> %d", before, m.modalVP.Width)
+	}
+
+	m.graphics = GraphicsOff
+	m.refreshModalViewport()
+	if m.modalVP.Width != before || strings.Contains(stripANSI(m.View()), "▀") {
+		t.Fatalf("expected no poster with posters off")
+	}
+}
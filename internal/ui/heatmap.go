@@ -0,0 +1,203 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+const heatmapMaxWeeks = 53
+
+var heatmapNow = time.Now
+
+func calendarDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func heatmapToday() time.Time {
+	return calendarDay(heatmapNow())
+}
+
+func (m *Model) toggleHeatmap() tea.Cmd {
+	m.diaryHeatmap = !m.diaryHeatmap
+	m.heatmapDayOpen = false
+	m.viewport.YOffset = 0
+	if !m.diaryHeatmap {
+		return nil
+	}
+	if m.heatmapCursor.IsZero() {
+		m.heatmapCursor = heatmapToday()
+	}
+	return m.loadAllDiaryCmd()
+}
+
+func (m Model) heatmapDayEntries(day time.Time) []letterboxd.DiaryEntry {
+	var entries []letterboxd.DiaryEntry
+	for _, entry := range m.allDiary {
+		if entry.Date.Equal(day) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func (m *Model) moveHeatmapCursor(days int) {
+	next := m.heatmapCursor.AddDate(0, 0, days)
+	if today := heatmapToday(); next.After(today) {
+		next = today
+	}
+	m.heatmapCursor = next
+}
+
+func (m *Model) handleHeatmapKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.activeTab != tabDiary || !m.diaryHeatmap || m.modalOpen() {
+		return nil, false
+	}
+	if m.heatmapDayOpen {
+		entries := m.heatmapDayEntries(m.heatmapCursor)
+		switch {
+		case key.Matches(msg, m.keys.Down):
+			m.heatmapDayList.selected = clamp(m.heatmapDayList.selected+1, 0, max(0, len(entries)-1))
+		case key.Matches(msg, m.keys.Up):
+			m.heatmapDayList.selected = clamp(m.heatmapDayList.selected-1, 0, max(0, len(entries)-1))
+		case key.Matches(msg, m.keys.Select):
+			if len(entries) == 0 {
+				return nil, true
+			}
+			*m = m.openFilm(entries[clamp(m.heatmapDayList.selected, 0, len(entries)-1)].FilmURL)
+			if m.activeTab == tabFilm {
+				return fetchFilmCmd(m.client, m.film.URL, m.username), true
+			}
+		case key.Matches(msg, m.keys.Cancel, m.keys.Back):
+			m.heatmapDayOpen = false
+		default:
+			return nil, false
+		}
+		return nil, true
+	}
+	switch {
+	case key.Matches(msg, m.keys.WeekPrev):
+		m.moveHeatmapCursor(-7)
+	case key.Matches(msg, m.keys.WeekNext):
+		m.moveHeatmapCursor(7)
+	case key.Matches(msg, m.keys.Down):
+		m.moveHeatmapCursor(1)
+	case key.Matches(msg, m.keys.Up):
+		m.moveHeatmapCursor(-1)
+	case key.Matches(msg, m.keys.Select):
+		if len(m.heatmapDayEntries(m.heatmapCursor)) > 0 {
+			m.heatmapDayOpen = true
+			m.heatmapDayList.selected = 0
+		}
+	case key.Matches(msg, m.keys.Cancel):
+		return m.toggleHeatmap(), true
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// heatmapWindow returns the Sunday starting the first column, keeping the
+// current week in the last column until the cursor moves past the left edge.
+func heatmapWindow(cursor, today time.Time, weeks int) time.Time {
+	end := today.AddDate(0, 0, 6-int(today.Weekday()))
+	start := end.AddDate(0, 0, 1-weeks*7)
+	if cursor.Before(start) {
+		start = cursor.AddDate(0, 0, -int(cursor.Weekday()))
+	}
+	return start
+}
+
+func heatLevel(count int) int {
+	return clamp(count, 0, 4)
+}
+
+func renderDiaryHeatmap(m Model, theme themeStyles) string {
+	if m.allDiaryErr != nil {
+		return theme.dim.Render("Error: " + errorText(m.allDiaryErr))
+	}
+	if !m.allDiaryLoaded {
+		return theme.dim.Render("Loading full diary…")
+	}
+	counts := map[time.Time]int{}
+	for _, entry := range m.allDiary {
+		if !entry.Date.IsZero() {
+			counts[entry.Date]++
+		}
+	}
+	today := heatmapToday()
+	weeks := clamp((m.width-6)/2, 4, heatmapMaxWeeks)
+	start := heatmapWindow(m.heatmapCursor, today, weeks)
+
+	var months strings.Builder
+	months.WriteString("    ")
+	lastMonth := time.Month(0)
+	for w := 0; w < weeks; w++ {
+		weekStart := start.AddDate(0, 0, w*7)
+		if months.Len() > 4+w*2 {
+			continue
+		}
+		if weekStart.Month() != lastMonth {
+			lastMonth = weekStart.Month()
+			months.WriteString(weekStart.Format("Jan") + " ")
+			continue
+		}
+		months.WriteString("  ")
+	}
+
+	rows := []string{theme.subtle.Render(months.String())}
+	total := 0
+	for weekday := 0; weekday < 7; weekday++ {
+		label := "    "
+		if weekday%2 == 1 {
+			label = time.Weekday(weekday).String()[:3] + " "
+		}
+		var line strings.Builder
+		line.WriteString(theme.subtle.Render(label))
+		for w := 0; w < weeks; w++ {
+			day := start.AddDate(0, 0, w*7+weekday)
+			if day.After(today) {
+				line.WriteString("  ")
+				continue
+			}
+			count := counts[day]
+			total += count
+			cell := theme.heat[heatLevel(count)].Render("■")
+			if day.Equal(m.heatmapCursor) {
+				cell = theme.user.Render("◆")
+			}
+			line.WriteString(cell + " ")
+		}
+		rows = append(rows, line.String())
+	}
+
+	legend := theme.subtle.Render("Less ")
+	for _, style := range theme.heat {
+		legend += style.Render("■") + " "
+	}
+	legend += theme.subtle.Render("More")
+	rows = append(rows, "", legend+theme.subtle.Render(fmt.Sprintf(" · %d films shown", total)))
+
+	entries := m.heatmapDayEntries(m.heatmapCursor)
+	summary := fmt.Sprintf("%s · %d logged", formatDiaryDate(m.heatmapCursor), len(entries))
+	rows = append(rows, "", theme.header.Render(summary))
+	width := max(40, m.width-2)
+	for i, entry := range entries {
+		line := entry.Title
+		if entry.Rating != "" {
+			line += " " + styleRating(entry.Rating, theme)
+		}
+		if entry.Rewatch {
+			line += " ↺"
+		}
+		rows = append(rows, renderSelectableLine(line, m.heatmapDayOpen && i == m.heatmapDayList.selected, width, theme))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+func TestHeatmapNavigationAndDayList(t *testing.T) {
+	oldNow := heatmapNow
+	defer func() { heatmapNow = oldNow }()
+	heatmapNow = func() time.Time { return time.Date(2024, time.March, 5, 20, 0, 0, 0, time.Local) }
+
+	m := NewModel("jane", nil)
+	m.width = 80
+	m.activeTab = tabDiary
+	m.allDiaryLoaded = true
+	m.allDiary = []letterboxd.DiaryEntry{
+		{Date: time.Date(2024, time.February, 27, 0, 0, 0, 0, time.UTC), Title: "Alien", FilmURL: "https://letterboxd.com/film/alien/"},
+		{Date: time.Date(2024, time.February, 27, 0, 0, 0, 0, time.UTC), Title: "Heat", FilmURL: "https://letterboxd.com/film/heat/"},
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	m = updated.(Model)
+	if !m.diaryHeatmap || !m.heatmapCursor.Equal(time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected heatmap on today, got %v %v", m.diaryHeatmap, m.heatmapCursor)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m = updated.(Model)
+	if !m.heatmapCursor.Equal(time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected cursor to stay on today, got %v", m.heatmapCursor)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	m = updated.(Model)
+	if !m.heatmapCursor.Equal(time.Date(2024, time.February, 27, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected cursor a week back, got %v", m.heatmapCursor)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if !m.heatmapDayOpen {
+		t.Fatalf("expected day list to open")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m = updated.(Model)
+	view := stripANSI(renderDiary(m, newTheme()))
+	if !strings.Contains(view, "Feb 27 2024 · 2 logged") || !strings.Contains(view, "> Heat") {
+		t.Fatalf("unexpected heatmap view: %q", view)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.activeTab != tabFilm || m.film.URL != "https://letterboxd.com/film/heat/" || cmd == nil {
+		t.Fatalf("expected film to open, got tab=%v url=%q", m.activeTab, m.film.URL)
+	}
+}
+
+func TestHeatmapWindowFollowsCursor(t *testing.T) {
+	today := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+	start := heatmapWindow(today, today, 4)
+	if !start.Equal(time.Date(2024, time.February, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected window start: %v", start)
+	}
+	cursor := time.Date(2024, time.January, 3, 0, 0, 0, 0, time.UTC)
+	start = heatmapWindow(cursor, today, 4)
+	if !start.Equal(time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected shifted window start: %v", start)
+	}
+}
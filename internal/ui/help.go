@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"strings"
+
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 )
@@ -22,64 +24,98 @@ func newHelpKeyMap(short []key.Binding) helpKeyMap {
 	return helpKeyMap{short: short, full: [][]key.Binding{short}}
 }
 
-func helpBinding(binding key.Binding, helpKey, desc string) key.Binding {
-	binding.SetHelp(helpKey, desc)
+func helpBinding(binding key.Binding, desc string) key.Binding {
+	binding.SetHelp(binding.Help().Key, desc)
 	return binding
 }
 
-func tabHelp(desc string) key.Binding {
+// joinHelp shows several bindings as one help entry, e.g. j/k for down and up.
+func joinHelp(desc string, bindings ...key.Binding) key.Binding {
+	var keys, hints []string
+	for _, binding := range bindings {
+		keys = append(keys, binding.Keys()...)
+		hints = append(hints, keyHint(binding))
+	}
 	return key.NewBinding(
-		key.WithKeys("tab", "shift+tab"),
-		key.WithHelp("tab/shift+tab", desc),
+		key.WithKeys(keys...),
+		key.WithHelp(strings.Join(hints, "/"), desc),
 	)
 }
 
-func navHelp(desc string) key.Binding {
-	return key.NewBinding(
-		key.WithKeys("j", "k", "down", "up"),
-		key.WithHelp("j/k", desc),
-	)
+func (k keyMap) tabHelp(desc string) key.Binding {
+	return joinHelp(desc, k.NextTab, k.PrevTab)
 }
 
-func pageHelp(desc string) key.Binding {
-	return key.NewBinding(
-		key.WithKeys("ctrl+b", "ctrl+f"),
-		key.WithHelp("ctrl+b/ctrl+f", desc),
-	)
+func (k keyMap) navHelp(desc string) key.Binding {
+	return joinHelp(desc, k.Down, k.Up)
 }
 
-func backHelp(helpKey string, keys ...string) key.Binding {
-	return key.NewBinding(
-		key.WithKeys(keys...),
-		key.WithHelp(helpKey, "back"),
-	)
+func (k keyMap) pageHelp(desc string) key.Binding {
+	return joinHelp(desc, k.PageUp, k.PageDown)
+}
+
+func (k keyMap) backHelp(bindings ...key.Binding) key.Binding {
+	return joinHelp("back", bindings...)
 }
 
 func (m Model) helpMap() helpKeyMap {
 	keys := m.keys
-	navScroll := navHelp("scroll")
-	navMove := navHelp("move")
-	page := pageHelp("page")
+	navScroll := keys.navHelp("scroll")
+	navMove := keys.navHelp("move")
+	page := keys.pageHelp("page")
 	helpToggle := keys.Help
 	if m.help.ShowAll {
-		helpToggle = helpBinding(keys.Help, "?", "hide help")
+		helpToggle = helpBinding(keys.Help, "hide help")
 	}
 
 	switch {
 	case m.logModal:
-		tabFields := tabHelp("next/prev field")
-		enter := helpBinding(keys.Select, "enter", "toggle/submit")
+		tabFields := keys.tabHelp("next/prev field")
+		enter := helpBinding(keys.Select, "toggle/submit")
 		toggle := keys.Toggle
 		submit := keys.Submit
-		back := backHelp("esc/q", "esc", "q")
+		back := keys.backHelp(keys.Cancel, keys.ModalBack)
 		return newHelpKeyMap([]key.Binding{tabFields, enter, toggle, submit, back, helpToggle, keys.QuitAll})
+	case m.filterModal:
+		tabFields := keys.tabHelp("next/prev field")
+		enter := helpBinding(keys.Select, "cycle/apply")
+		toggle := helpBinding(keys.Toggle, "cycle")
+		apply := helpBinding(keys.Submit, "apply")
+		back := keys.backHelp(keys.Cancel)
+		return newHelpKeyMap([]key.Binding{tabFields, enter, toggle, apply, back, keys.QuitAll})
+	case m.debugPanel:
+		enter := helpBinding(keys.Select, "details")
+		back := keys.backHelp(keys.Cancel, keys.Debug)
+		if m.debugDetail {
+			return newHelpKeyMap([]key.Binding{navScroll, back, keys.QuitAll})
+		}
+		return newHelpKeyMap([]key.Binding{navMove, enter, back, keys.QuitAll})
+	case m.paletteModal:
+		move := key.NewBinding(key.WithKeys("up", "down"), key.WithHelp("up/down", "move"))
+		enter := helpBinding(keys.Select, "run")
+		back := keys.backHelp(keys.Cancel)
+		return newHelpKeyMap([]key.Binding{move, enter, back, keys.QuitAll})
 	case m.profileModal:
-		back := backHelp("b/esc/q", "b", "esc", "q")
+		back := keys.backHelp(keys.Back, keys.Cancel, keys.ModalBack)
 		nav := navScroll
 		short := []key.Binding{nav, page}
 		if m.modalProfileSelectableCount() > 0 {
 			nav = navMove
-			short = []key.Binding{nav, page, helpBinding(keys.Select, "enter", "view film")}
+			enter := helpBinding(keys.Select, "view film")
+			if entry, ok := m.selectedModalProfileEntry(); ok && entry.peopleKind != "" {
+				enter = helpBinding(keys.Select, "view members")
+			}
+			short = []key.Binding{nav, page, enter}
+		}
+		if username, followed, known := m.followTarget(); username != "" && m.hasCookie() {
+			if known && followed {
+				short = append(short, keys.Unfollow)
+			} else {
+				short = append(short, keys.Follow)
+			}
+		}
+		if m.modalUser != "" && m.modalUser != m.username {
+			short = append(short, keys.Compare)
 		}
 		short = append(short, keys.JumpTop, keys.JumpBottom, keys.Open, back, helpToggle, keys.QuitAll)
 		return newHelpKeyMap(short)
@@ -88,26 +124,74 @@ func (m Model) helpMap() helpKeyMap {
 		if inWatchlist, ok := m.watchlistState(); ok && inWatchlist {
 			watchHint = keys.WatchlistRemove
 		}
-		back := tabHelp("back")
-		modalBack := backHelp("esc/q", "esc", "q")
+		back := keys.tabHelp("back")
+		modalBack := keys.backHelp(keys.Cancel, keys.ModalBack)
 		short := []key.Binding{navScroll, page, keys.JumpTop, keys.JumpBottom}
 		if m.hasCookie() {
 			short = append(short, keys.Log, watchHint)
 		}
-		short = append(short, keys.Open, back, modalBack, helpToggle, keys.QuitAll)
+		if m.rouletteActive {
+			short = append(short, helpBinding(keys.Roulette, "reroll"))
+		}
+		short = append(short, keys.Open, back, modalBack, keys.Palette, helpToggle, keys.QuitAll)
+		return newHelpKeyMap(short)
+	case m.activeTab == tabCompare:
+		back := keys.backHelp(keys.Back, keys.Cancel)
+		return newHelpKeyMap([]key.Binding{navScroll, page, keys.JumpTop, keys.JumpBottom, back, helpToggle, keys.Quit, keys.QuitAll})
+	case m.activeTab == tabQueue:
+		retry := helpBinding(keys.Refresh, "retry now")
+		back := keys.backHelp(keys.Back, keys.Cancel)
+		return newHelpKeyMap([]key.Binding{navMove, keys.Edit, keys.Discard, retry, back, helpToggle, keys.Quit, keys.QuitAll})
+	case m.findTyping && m.findShown():
+		enter := helpBinding(keys.Select, "done")
+		escape := helpBinding(keys.Cancel, "clear filter")
+		return newHelpKeyMap([]key.Binding{enter, escape, keys.QuitAll})
+	case m.activeTab == tabPeople:
+		enter := helpBinding(keys.Select, "view profile")
+		back := keys.backHelp(keys.Back, keys.Cancel)
+		short := []key.Binding{navMove, page, keys.JumpTop, keys.JumpBottom, enter}
+		if m.hasCookie() {
+			short = append(short, keys.Follow, keys.Unfollow)
+		}
+		short = append(short, keys.Find)
+		if m.findQuery() != "" {
+			short = append(short, keys.NextMatch, keys.PrevMatch, helpBinding(keys.Cancel, "clear filter"))
+		}
+		short = append(short, keys.Open, back, helpToggle, keys.Quit, keys.QuitAll)
 		return newHelpKeyMap(short)
 	case m.activeTab == tabSearch:
-		switchTabs := tabHelp("switch tab")
+		switchTabs := keys.tabHelp("switch tab")
 		if m.searchFocusInput {
-			enter := helpBinding(keys.Select, "enter", "search")
-			escape := helpBinding(keys.Cancel, "esc", "results")
+			enter := helpBinding(keys.Select, "search")
+			escape := helpBinding(keys.Cancel, "results")
 			return newHelpKeyMap([]key.Binding{enter, escape, switchTabs, helpToggle, keys.Quit, keys.QuitAll})
 		}
-		enter := helpBinding(keys.Select, "enter", "view")
-		search := helpBinding(keys.SearchTab, "/", "edit query")
-		return newHelpKeyMap([]key.Binding{navMove, page, keys.JumpTop, keys.JumpBottom, enter, search, switchTabs, helpToggle, keys.Quit, keys.QuitAll})
+		enter := helpBinding(keys.Select, "view")
+		search := helpBinding(keys.SearchTab, "edit query")
+		short := []key.Binding{navMove, page, keys.JumpTop, keys.JumpBottom, enter, search}
+		if m.width >= splitMinWidth {
+			short = append(short, keys.Preview)
+		}
+		short = append(short, switchTabs, helpToggle, keys.Quit, keys.QuitAll)
+		return newHelpKeyMap(short)
+	case m.activeTab == tabDiary && m.diaryHeatmap:
+		if m.heatmapDayOpen {
+			enter := helpBinding(keys.Select, "view film")
+			return newHelpKeyMap([]key.Binding{navMove, enter, keys.backHelp(keys.Cancel, keys.Back), helpToggle, keys.Quit, keys.QuitAll})
+		}
+		days := keys.navHelp("day")
+		enter := helpBinding(keys.Select, "list day")
+		list := joinHelp("list view", keys.Calendar, keys.Cancel)
+		return newHelpKeyMap([]key.Binding{keys.WeekPrev, keys.WeekNext, days, enter, list, keys.tabHelp("switch tab"), keys.Refresh, helpToggle, keys.Quit, keys.QuitAll})
 	default:
-		switchTabs := tabHelp("switch tab")
+		switchTabs := keys.tabHelp("switch tab")
+		find := []key.Binding{}
+		if findableTab(m) {
+			find = append(find, keys.Find)
+			if m.findQuery() != "" {
+				find = append(find, keys.NextMatch, keys.PrevMatch, helpBinding(keys.Cancel, "clear filter"))
+			}
+		}
 		switch m.activeTab {
 		case tabProfile:
 			nav := navScroll
@@ -115,32 +199,50 @@ func (m Model) helpMap() helpKeyMap {
 				nav = navMove
 			}
 			short := []key.Binding{nav, page}
-			if m.profileSelectableCount() > 0 {
-				short = append(short, helpBinding(keys.Select, "enter", "view film"))
-			}
-			if len(m.profileStack) > 0 {
-				short = append(short, keys.Back)
+			if entry, ok := m.selectedProfileEntry(); ok {
+				if entry.peopleKind != "" {
+					short = append(short, helpBinding(keys.Select, "view members"))
+				} else {
+					short = append(short, helpBinding(keys.Select, "view film"))
+				}
 			}
-			short = append(short, keys.JumpTop, keys.JumpBottom, keys.Open, keys.SearchTab, switchTabs, keys.Refresh, helpToggle, keys.Quit, keys.QuitAll)
+			short = append(short, find...)
+			short = append(short, keys.JumpTop, keys.JumpBottom, keys.Open, keys.SearchTab, switchTabs, keys.Palette, keys.Refresh, helpToggle, keys.Quit, keys.QuitAll)
 			return newHelpKeyMap(short)
-		case tabDiary, tabWatchlist, tabActivity, tabFollowing:
+		case tabDiary, tabFilms, tabWatchlist, tabActivity, tabFollowing:
 			enter := keys.Select
 			if m.activeTab == tabFollowing {
-				enter = helpBinding(keys.Select, "enter", "view profile")
+				enter = helpBinding(keys.Select, "view profile")
 			} else {
-				enter = helpBinding(keys.Select, "enter", "view film")
+				enter = helpBinding(keys.Select, "view film")
 			}
 			short := []key.Binding{navMove, page, keys.JumpTop, keys.JumpBottom, enter}
 			if m.activeTab == tabDiary {
-				short = append(short, helpBinding(keys.Sort, "s", "sort: "+m.diarySortLabel()))
+				short = append(short, helpBinding(keys.Sort, "sort: "+m.diarySortLabel()), keys.DiaryJump, keys.Calendar)
 			}
 			if m.activeTab == tabWatchlist {
-				short = append(short, helpBinding(keys.Sort, "s", "sort: "+m.watchlistSortLabel()))
+				short = append(short, helpBinding(keys.Sort, "sort: "+m.watchlistSortLabel()), keys.Filter, keys.Roulette)
 			}
-			short = append(short, keys.SearchTab, switchTabs, keys.Refresh, helpToggle, keys.Quit, keys.QuitAll)
+			if m.activeTab == tabFilms {
+				short = append(short, helpBinding(keys.Sort, "sort: "+m.filmsSortLabel()), keys.Filter)
+			}
+			if m.width >= splitMinWidth && previewTab(m) {
+				short = append(short, keys.Preview)
+			}
+			if m.canExport() {
+				short = append(short, keys.Export)
+			}
+			short = append(short, find...)
+			short = append(short, keys.SearchTab, switchTabs, keys.Palette, keys.Refresh, helpToggle, keys.Quit, keys.QuitAll)
 			return newHelpKeyMap(short)
+		case tabStats:
+			review := keys.YearReview
+			if m.statsReview {
+				review = helpBinding(keys.YearReview, "dashboard")
+			}
+			return newHelpKeyMap([]key.Binding{navScroll, page, keys.JumpTop, keys.JumpBottom, keys.PrevYear, keys.NextYear, review, switchTabs, keys.Palette, keys.Refresh, helpToggle, keys.Quit, keys.QuitAll})
 		default:
-			return newHelpKeyMap([]key.Binding{keys.JumpTop, keys.JumpBottom, keys.SearchTab, switchTabs, keys.Refresh, helpToggle, keys.Quit, keys.QuitAll})
+			return newHelpKeyMap([]key.Binding{keys.JumpTop, keys.JumpBottom, keys.SearchTab, switchTabs, keys.Palette, keys.Refresh, helpToggle, keys.Quit, keys.QuitAll})
 		}
 	}
 }
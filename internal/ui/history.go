@@ -0,0 +1,242 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+const maxHistory = 50
+
+// location is one place in the navigation history: a tab, a film, a profile
+// popup, a followers/following list or a comparison, plus where the
+// selection and scroll were when it was left.
+type location struct {
+	tab        tab
+	base       tab // tab under a film or profile popup
+	filmURL    string
+	modalUser  string
+	peopleUser string
+	peopleKind letterboxd.PeopleKind
+	compareA   string
+	compareB   string
+	selected   int
+	yOffset    int
+}
+
+func (m Model) location() location {
+	loc := location{tab: m.activeTab, base: m.activeTab}
+	switch {
+	case m.activeTab == tabFilm:
+		loc.base = m.filmBase()
+		loc.filmURL = m.film.URL
+		loc.yOffset = m.modalVP.YOffset
+		return loc
+	case m.profileModal:
+		loc.modalUser = m.modalUser
+		loc.selected = m.modalProfileList.selected
+		loc.yOffset = m.modalVP.YOffset
+		return loc
+	case m.activeTab == tabPeople:
+		loc.base = m.peopleReturn
+		loc.peopleUser, loc.peopleKind = m.peopleUser, m.peopleKind
+	case m.activeTab == tabCompare:
+		loc.base = m.compareReturn
+		loc.compareA, loc.compareB = m.compareA, m.compareB
+	}
+	loc.selected = m.selectionList().selected
+	loc.yOffset = m.viewport.YOffset
+	return loc
+}
+
+// underFilm is the newest history entry that isn't a film: what the film on
+// screen was opened over.
+func (m Model) underFilm() location {
+	for i := len(m.back) - 1; i >= 0; i-- {
+		if m.back[i].tab != tabFilm {
+			return m.back[i]
+		}
+	}
+	return location{tab: tabProfile, base: tabProfile}
+}
+
+func (m Model) filmBase() tab {
+	return m.underFilm().base
+}
+
+// closeFilm goes back to what the film was opened over, where its selection
+// and scroll were. Content still loaded is kept rather than refetched.
+func (m Model) closeFilm() (Model, tea.Cmd) {
+	under := m.underFilm()
+	probe := m
+	probe.activeTab = under.tab
+	probe.profileModal = under.modalUser != ""
+	if !probe.location().samePlace(under) {
+		return m.goTo(under)
+	}
+	m = probe
+	m.resetTabPosition()
+	m.restorePosition(under)
+	m.resizeViewport()
+	return m, nil
+}
+
+// samePlace reports whether two locations show the same thing, wherever
+// their selection and scroll are.
+func (l location) samePlace(other location) bool {
+	l.base, other.base = 0, 0
+	l.selected, other.selected = 0, 0
+	l.yOffset, other.yOffset = 0, 0
+	return l == other
+}
+
+// Update records a history entry whenever handling msg moves to a different
+// place, so back and forward work across every way of navigating. Command
+// results from before an account switch are dropped.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if tagged, ok := msg.(accountMsg); ok {
+		if tagged.session != m.session {
+			return m, nil
+		}
+		msg = tagged.msg
+	}
+	if loaded, ok := msg.(accountLoadedMsg); ok {
+		next, cmd := m.switchedAccount(loaded)
+		return next, accountCmd(next.session, cmd)
+	}
+	if updated, cmd, handled := m.updatePreview(msg); handled {
+		return updated, accountCmd(m.session, cmd)
+	}
+	next, cmd := m.navigate(msg)
+	next.fitListWidth()
+	return next, accountCmd(next.session, tea.Batch(cmd, next.schedulePreview()))
+}
+
+func (m Model) navigate(msg tea.Msg) (Model, tea.Cmd) {
+	if ev, ok := msg.(tea.KeyMsg); ok && !m.typing() {
+		switch {
+		case key.Matches(ev, m.keys.HistoryBack):
+			return m.stepHistory(false)
+		case key.Matches(ev, m.keys.HistoryForward):
+			return m.stepHistory(true)
+		}
+	}
+	before := m.location()
+	updated, cmd := m.update(msg)
+	next := updated.(Model)
+	// A loaded film may come back under its canonical URL; that isn't a move.
+	if _, loaded := msg.(filmMsg); !loaded && !before.samePlace(next.location()) {
+		next.back = pushLocation(next.back, before)
+		next.forward = nil
+		next.restoring = nil
+	}
+	if next.restoring != nil && next.restoreReady() {
+		next.restorePosition(*next.restoring)
+		next.restoring = nil
+	}
+	return next, cmd
+}
+
+// typing reports whether keys are going into a text field, where the history
+// keys may mean something else.
+func (m Model) typing() bool {
+	return m.cookieModal || m.logModal || m.filterModal || m.paletteModal ||
+		(m.findTyping && m.findTab == m.activeTab) ||
+		(m.activeTab == tabSearch && m.searchFocusInput)
+}
+
+func pushLocation(stack []location, loc location) []location {
+	stack = append(stack, loc)
+	if len(stack) > maxHistory {
+		stack = stack[len(stack)-maxHistory:]
+	}
+	return stack
+}
+
+// stepHistory moves one entry back or forward, saving the current location
+// on the other stack.
+func (m Model) stepHistory(forward bool) (Model, tea.Cmd) {
+	from, to := &m.back, &m.forward
+	if forward {
+		from, to = to, from
+	}
+	if len(*from) == 0 {
+		return m, nil
+	}
+	loc := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+	*to = pushLocation(*to, m.location())
+	return m.goTo(loc)
+}
+
+// goTo shows loc again, refetching what it needs. Its selection and scroll
+// come back once the content is there.
+func (m Model) goTo(loc location) (Model, tea.Cmd) {
+	m.profileModal = false
+	m.activeTab = loc.base
+	m.resetTabPosition()
+	var cmd tea.Cmd
+	switch {
+	case loc.tab == tabFilm:
+		m = m.openFilm(loc.filmURL)
+		cmd = fetchFilmCmd(m.client, m.film.URL, m.username)
+	case loc.modalUser != "":
+		m = m.openProfileModal(loc.modalUser)
+		cmd = fetchProfileModalCmd(m.client, loc.modalUser)
+	case loc.tab == tabPeople:
+		m = m.openPeople(loc.peopleUser, loc.peopleKind)
+		cmd = fetchPeopleCmd(m.client, loc.peopleUser, loc.peopleKind, 1)
+	case loc.tab == tabCompare:
+		m = m.openCompare(loc.compareA, loc.compareB)
+		cmd = fetchCompareCmd(m.client, loc.compareA, loc.compareB)
+	default:
+		m.activeTab = loc.tab
+		m.resetTabPosition()
+		cmd = m.maybeFillCmd()
+	}
+	m.restoring = &loc
+	if m.restoreReady() {
+		m.restorePosition(loc)
+		m.restoring = nil
+	}
+	return m, cmd
+}
+
+// restoreReady reports whether the content restoring waits on has arrived.
+func (m Model) restoreReady() bool {
+	loc := m.restoring
+	if loc == nil || !loc.samePlace(m.location()) {
+		return false
+	}
+	switch {
+	case loc.tab == tabFilm:
+		return !m.loading
+	case loc.modalUser != "":
+		return !m.modalLoading
+	case loc.tab == tabPeople:
+		return !m.loading
+	case loc.tab == tabCompare:
+		return !m.compareLoading
+	}
+	return true
+}
+
+func (m *Model) restorePosition(loc location) {
+	if loc.tab == tabFilm {
+		m.refreshModalViewport()
+		m.modalVP.SetYOffset(loc.yOffset)
+		return
+	}
+	if loc.modalUser != "" {
+		m.modalProfileList.selected = clamp(loc.selected, 0, max(0, m.modalProfileSelectableCount()-1))
+		m.refreshModalViewport()
+		m.modalVP.SetYOffset(loc.yOffset)
+		return
+	}
+	list := m.selectionList()
+	list.selected = clamp(loc.selected, 0, max(0, m.selectableCount()-1))
+	m.refreshViewport()
+	m.viewport.YOffset = max(0, loc.yOffset)
+	m.syncViewportToSelection()
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+func press(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+	updated, cmd := m.Update(msg)
+	return updated.(Model), cmd
+}
+
+func TestHistoryBackAndForwardAcrossFilmAndTabs(t *testing.T) {
+	m := sizedModel(t, 20)
+	m.loading = false
+	m.activeTab = tabDiary
+	m.lastTab = tabDiary
+	m.diary = []letterboxd.DiaryEntry{
+		{Title: "Alien", FilmURL: "https://letterboxd.com/film/alien/"},
+		{Title: "Heat", FilmURL: "https://letterboxd.com/film/heat/"},
+		{Title: "Ran", FilmURL: "https://letterboxd.com/film/ran/"},
+	}
+	m.diaryList.selected = 2
+
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	updated, _ := m.Update(filmMsg{film: letterboxd.Film{Title: "Ran", URL: "https://letterboxd.com/film/ran/"}})
+	m = updated.(Model)
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyTab})
+	if m.activeTab == tabDiary || len(m.back) != 3 {
+		t.Fatalf("expected three history entries, got %d on %v", len(m.back), m.activeTab)
+	}
+
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyCtrlO})
+	if m.activeTab != tabDiary || m.diaryList.selected != 2 {
+		t.Fatalf("expected back to restore the diary selection, got %v %d", m.activeTab, m.diaryList.selected)
+	}
+	m, cmd := press(m, tea.KeyMsg{Type: tea.KeyCtrlO})
+	if m.activeTab != tabFilm || m.film.URL != "https://letterboxd.com/film/ran/" || m.location().base != tabDiary || cmd == nil {
+		t.Fatalf("expected back to reopen the film, got %v %q", m.activeTab, m.film.URL)
+	}
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyCtrlO})
+	if m.activeTab != tabDiary || len(m.back) != 0 || len(m.forward) != 3 {
+		t.Fatalf("expected to reach the start, got %v back=%d forward=%d", m.activeTab, len(m.back), len(m.forward))
+	}
+
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyRight, Alt: true})
+	if m.activeTab != tabFilm {
+		t.Fatalf("expected forward to go to the film, got %v", m.activeTab)
+	}
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if len(m.forward) != 0 {
+		t.Fatalf("expected a new move to drop the forward history")
+	}
+}
+
+func TestHistoryRestoresPeopleSelectionAfterReload(t *testing.T) {
+	m := sizedModel(t, 20)
+	m.loading = false
+	m = m.openPeople("bob", letterboxd.PeopleFollowers)
+	members := []letterboxd.Member{{Username: "a"}, {Username: "b"}, {Username: "c"}}
+	updated, _ := m.Update(peopleMsg{items: members, page: 1, user: "bob", kind: letterboxd.PeopleFollowers})
+	m = updated.(Model)
+	m.peopleList.selected = 2
+
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.profileModal || m.modalUser != "c" {
+		t.Fatalf("expected the member's profile, got %v %q", m.profileModal, m.modalUser)
+	}
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyCtrlO})
+	if m.profileModal || m.activeTab != tabPeople || !m.loading {
+		t.Fatalf("expected back to reload the followers list, got modal=%v %v", m.profileModal, m.activeTab)
+	}
+	updated, _ = m.Update(peopleMsg{items: members, page: 1, user: "bob", kind: letterboxd.PeopleFollowers})
+	m = updated.(Model)
+	if m.peopleList.selected != 2 || m.restoring != nil {
+		t.Fatalf("expected the selection back once loaded, got %d", m.peopleList.selected)
+	}
+}
+
+func TestClosingFilmReturnsToProfilePopup(t *testing.T) {
+	m := sizedModel(t, 20)
+	m.loading = false
+	m.activeTab = tabFollowing
+	m.lastTab = tabFollowing
+	m = m.openProfileModal("alice")
+	m.modalLoading = false
+	m.modalProfile = letterboxd.Profile{
+		Favorites: []letterboxd.FavoriteFilm{
+			{Title: "Alien", FilmURL: letterboxd.BaseURL + "/film/alien/"},
+			{Title: "Heat", FilmURL: letterboxd.BaseURL + "/film/heat/"},
+		},
+	}
+	m.modalProfileList.selected = 1
+
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.activeTab != tabFilm || m.profileModal {
+		t.Fatalf("expected the film to open over the popup, got %v popup=%v", m.activeTab, m.profileModal)
+	}
+	m, cmd := press(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.activeTab != tabFollowing || !m.profileModal || m.modalUser != "alice" || m.modalProfileList.selected != 1 {
+		t.Fatalf("expected the popup back with its selection, got %v popup=%v %q %d", m.activeTab, m.profileModal, m.modalUser, m.modalProfileList.selected)
+	}
+	if cmd != nil || len(m.modalProfile.Favorites) != 2 {
+		t.Fatalf("expected the loaded popup to be kept rather than refetched")
+	}
+}
@@ -1,6 +1,14 @@
 package ui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+)
 
 type keyMap struct {
 	Quit            key.Binding
@@ -27,6 +35,33 @@ type keyMap struct {
 	Submit          key.Binding
 	Toggle          key.Binding
 	Sort            key.Binding
+	Follow          key.Binding
+	Unfollow        key.Binding
+	Filter          key.Binding
+	Roulette        key.Binding
+	Compare         key.Binding
+	PrevYear        key.Binding
+	NextYear        key.Binding
+	YearReview      key.Binding
+	Calendar        key.Binding
+	DiaryJump       key.Binding
+	WeekPrev        key.Binding
+	WeekNext        key.Binding
+	Queue           key.Binding
+	Edit            key.Binding
+	Discard         key.Binding
+	Find            key.Binding
+	NextMatch       key.Binding
+	PrevMatch       key.Binding
+	Palette         key.Binding
+	Theme           key.Binding
+	HistoryBack     key.Binding
+	HistoryForward  key.Binding
+	Preview         key.Binding
+	Accounts        key.Binding
+	Cookie          key.Binding
+	Debug           key.Binding
+	Export          key.Binding
 }
 
 func newKeyMap() keyMap {
@@ -108,8 +143,8 @@ func newKeyMap() keyMap {
 			key.WithHelp("u", "remove from watchlist"),
 		),
 		SearchTab: key.NewBinding(
-			key.WithKeys("/"),
-			key.WithHelp("/", "search"),
+			key.WithKeys("S", "/"),
+			key.WithHelp("S", "search"),
 		),
 		Cancel: key.NewBinding(
 			key.WithKeys("esc"),
@@ -127,5 +162,275 @@ func newKeyMap() keyMap {
 			key.WithKeys("s"),
 			key.WithHelp("s", "sort"),
 		),
+		Follow: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "follow"),
+		),
+		Unfollow: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "unfollow"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "filter"),
+		),
+		Roulette: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "roulette"),
+		),
+		Compare: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "compare"),
+		),
+		PrevYear: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "prev year"),
+		),
+		NextYear: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next year"),
+		),
+		YearReview: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "year in review"),
+		),
+		DiaryJump: key.NewBinding(
+			key.WithKeys("J"),
+			key.WithHelp("J", "jump to date"),
+		),
+		Calendar: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "calendar"),
+		),
+		WeekPrev: key.NewBinding(
+			key.WithKeys("h"),
+			key.WithHelp("h", "prev week"),
+		),
+		WeekNext: key.NewBinding(
+			key.WithKeys("l"),
+			key.WithHelp("l", "next week"),
+		),
+		Queue: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "pending changes"),
+		),
+		Edit: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "edit"),
+		),
+		Discard: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "discard"),
+		),
+		Find: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter list"),
+		),
+		NextMatch: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next match"),
+		),
+		PrevMatch: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "prev match"),
+		),
+		Palette: key.NewBinding(
+			key.WithKeys(":", "ctrl+p"),
+			key.WithHelp(":", "commands"),
+		),
+		Theme: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "theme"),
+		),
+		HistoryBack: key.NewBinding(
+			key.WithKeys("ctrl+o", "alt+left"),
+			key.WithHelp("ctrl+o", "history back"),
+		),
+		// Terminals send ctrl+i as tab, so forward can't pair with ctrl+o the
+		// way it does in vim.
+		HistoryForward: key.NewBinding(
+			key.WithKeys("alt+right", "ctrl+]"),
+			key.WithHelp("alt+right", "history forward"),
+		),
+		Preview: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "preview"),
+		),
+		Accounts: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "switch account"),
+		),
+		Cookie: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "update cookie"),
+		),
+		Debug: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "debug panel"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "export"),
+		),
+	}
+}
+
+func (k *keyMap) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"quit":             &k.Quit,
+		"quit_all":         &k.QuitAll,
+		"next_tab":         &k.NextTab,
+		"prev_tab":         &k.PrevTab,
+		"down":             &k.Down,
+		"up":               &k.Up,
+		"page_down":        &k.PageDown,
+		"page_up":          &k.PageUp,
+		"refresh":          &k.Refresh,
+		"select":           &k.Select,
+		"back":             &k.Back,
+		"modal_back":       &k.ModalBack,
+		"jump_top":         &k.JumpTop,
+		"jump_bottom":      &k.JumpBottom,
+		"help":             &k.Help,
+		"open":             &k.Open,
+		"log":              &k.Log,
+		"watchlist_add":    &k.WatchlistAdd,
+		"watchlist_remove": &k.WatchlistRemove,
+		"search":           &k.SearchTab,
+		"cancel":           &k.Cancel,
+		"submit":           &k.Submit,
+		"toggle":           &k.Toggle,
+		"sort":             &k.Sort,
+		"follow":           &k.Follow,
+		"unfollow":         &k.Unfollow,
+		"filter":           &k.Filter,
+		"roulette":         &k.Roulette,
+		"compare":          &k.Compare,
+		"prev_year":        &k.PrevYear,
+		"next_year":        &k.NextYear,
+		"year_review":      &k.YearReview,
+		"calendar":         &k.Calendar,
+		"diary_jump":       &k.DiaryJump,
+		"week_prev":        &k.WeekPrev,
+		"week_next":        &k.WeekNext,
+		"queue":            &k.Queue,
+		"edit":             &k.Edit,
+		"discard":          &k.Discard,
+		"find":             &k.Find,
+		"next_match":       &k.NextMatch,
+		"prev_match":       &k.PrevMatch,
+		"palette":          &k.Palette,
+		"theme":            &k.Theme,
+		"history_back":     &k.HistoryBack,
+		"history_forward":  &k.HistoryForward,
+		"preview":          &k.Preview,
+		"accounts":         &k.Accounts,
+		"cookie":           &k.Cookie,
+		"debug":            &k.Debug,
+		"export":           &k.Export,
+	}
+}
+
+// sharedKeys lists actions that are never live in the same view, or where
+// one deliberately takes precedence, so they may be bound to the same key.
+var sharedKeys = [][2]string{
+	{"quit", "modal_back"},
+	{"filter", "follow"},
+	{"search", "find"},
+	{"log", "week_next"},
+}
+
+// WithKeys remaps bindings from the config's keys section, keyed by action
+// name. It fails on unknown actions and on keys bound to two actions.
+func (m Model) WithKeys(overrides map[string][]string) (Model, error) {
+	keys, err := newKeyMapWith(overrides)
+	if err != nil {
+		return m, err
+	}
+	m.keys = keys
+	return m, nil
+}
+
+func CheckKeys(overrides map[string][]string) error {
+	_, err := newKeyMapWith(overrides)
+	return err
+}
+
+func newKeyMapWith(overrides map[string][]string) (keyMap, error) {
+	keys := newKeyMap()
+	named := keys.named()
+	for name, list := range overrides {
+		binding, ok := named[name]
+		if !ok {
+			return keys, fmt.Errorf("keys: unknown action %q", name)
+		}
+		var bound []string
+		for _, k := range list {
+			if k = strings.TrimSpace(k); k != "" {
+				bound = append(bound, k)
+			}
+		}
+		if len(bound) == 0 {
+			return keys, fmt.Errorf("keys: %s has no keys", name)
+		}
+		helpKeys := slices.Clone(bound)
+		if name == "jump_top" {
+			// Jumping to the top takes a double press, like gg.
+			for i, k := range helpKeys {
+				if utf8.RuneCountInString(k) == 1 {
+					helpKeys[i] = k + k
+				}
+			}
+		}
+		binding.SetKeys(bound...)
+		binding.SetHelp(strings.Join(helpKeys, "/"), binding.Help().Desc)
+	}
+	return keys, keys.validate()
+}
+
+func (k keyMap) validate() error {
+	owners := map[string][]string{}
+	for name, binding := range k.named() {
+		for _, bound := range binding.Keys() {
+			owners[bound] = append(owners[bound], name)
+		}
+	}
+	var conflicts []string
+	for bound, names := range owners {
+		sort.Strings(names)
+		for i := range names {
+			for _, other := range names[i+1:] {
+				if !keysShared(names[i], other) {
+					conflicts = append(conflicts, fmt.Sprintf("%q is bound to both %s and %s", bound, names[i], other))
+				}
+			}
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	sort.Strings(conflicts)
+	return fmt.Errorf("keys: %s", strings.Join(conflicts, "; "))
+}
+
+func keysShared(a, b string) bool {
+	for _, pair := range sharedKeys {
+		if (pair[0] == a && pair[1] == b) || (pair[0] == b && pair[1] == a) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyHint is the short form of a binding's key for inline hints: the first
+// of its keys as shown in help. The search starts past the first character so
+// a binding to "/" itself survives.
+func keyHint(b key.Binding) string {
+	help := b.Help().Key
+	if len(help) > 1 {
+		if i := strings.Index(help[1:], "/"); i >= 0 {
+			return help[:i+1]
+		}
 	}
+	return help
 }
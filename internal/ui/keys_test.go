@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+func TestDefaultKeysHaveNoConflicts(t *testing.T) {
+	if err := newKeyMap().validate(); err != nil {
+		t.Fatalf("default keys conflict: %v", err)
+	}
+}
+
+func TestWithKeysRemapsBindingsAndHelp(t *testing.T) {
+	m, err := NewModel("jane", nil).WithKeys(map[string][]string{
+		"down":     {"t", "down"},
+		"up":       {"a", "up"},
+		"jump_top": {"home"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m.activeTab = tabDiary
+	m.diary = []letterboxd.DiaryEntry{{Title: "Alien"}, {Title: "Heat"}}
+
+	m = typeKeys(m, "t")
+	if m.diaryList.selected != 1 {
+		t.Fatalf("expected remapped down key to move, got %d", m.diaryList.selected)
+	}
+	m = typeKeys(m, "j")
+	if m.diaryList.selected != 1 {
+		t.Fatalf("expected the old key to stop working")
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyHome})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyHome})
+	m = updated.(Model)
+	if m.diaryList.selected != 0 {
+		t.Fatalf("expected remapped jump to top, got %d", m.diaryList.selected)
+	}
+
+	help := stripANSI(renderHelp(m, newTheme(), 400))
+	if !strings.Contains(help, "t/a move") || !strings.Contains(help, "home top") {
+		t.Fatalf("expected help to show remapped keys: %q", help)
+	}
+}
+
+func TestWithKeysRejectsConflictsAndUnknownActions(t *testing.T) {
+	_, err := NewModel("jane", nil).WithKeys(map[string][]string{"down": {"s"}})
+	if err == nil || !strings.Contains(err.Error(), `"s" is bound to both down and sort`) {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if _, err := NewModel("jane", nil).WithKeys(map[string][]string{"fly": {"x"}}); err == nil {
+		t.Fatalf("expected unknown action error")
+	}
+	if _, err := NewModel("jane", nil).WithKeys(map[string][]string{"down": {" "}}); err == nil {
+		t.Fatalf("expected empty binding error")
+	}
+	if _, err := NewModel("jane", nil).WithKeys(map[string][]string{"log": {"l"}, "week_next": {"l"}}); err != nil {
+		t.Fatalf("expected keys for actions in separate views to be allowed, got %v", err)
+	}
+}
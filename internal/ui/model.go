@@ -14,7 +14,10 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/solean/letterboxd-tui/internal/config"
 	"github.com/solean/letterboxd-tui/internal/letterboxd"
+	"github.com/solean/letterboxd-tui/internal/secrets"
+	"github.com/solean/letterboxd-tui/internal/store"
 )
 
 type tab int
@@ -27,6 +30,11 @@ const (
 	tabFilm
 	tabFollowing
 	tabActivity
+	tabPeople
+	tabFilms
+	tabCompare
+	tabStats
+	tabQueue
 )
 
 type listState struct {
@@ -36,19 +44,59 @@ type listState struct {
 type Model struct {
 	username                 string
 	profileUser              string
-	profileStack             []string
 	client                   *letterboxd.Client
+	store                    *store.Store
+	offline                  bool
+	queue                    *store.Queue
+	queueList                listState
+	queueReturn              tab
+	queueStatus              string
+	queueEditID              string
+	queueReplaying           bool
+	queueRetryPending        bool
+	queueBackoff             time.Duration
 	width                    int
 	height                   int
 	activeTab                tab
 	lastTab                  tab
 	profile                  letterboxd.Profile
 	diary                    []letterboxd.DiaryEntry
+	films                    []letterboxd.UserFilm
+	filmsFilter              letterboxd.FilmsFilter
+	watchlistFilter          letterboxd.WatchlistFilter
 	watchlist                []letterboxd.WatchlistItem
 	watchlistLoaded          bool
 	activity                 []letterboxd.ActivityItem
 	following                []letterboxd.ActivityItem
 	searchResults            []letterboxd.SearchResult
+	people                   []letterboxd.Member
+	peopleUser               string
+	peopleKind               letterboxd.PeopleKind
+	peopleReturn             tab
+	comparison               letterboxd.Comparison
+	compareA                 string
+	compareB                 string
+	compareErr               error
+	compareLoading           bool
+	compareReturn            tab
+	allDiary                 []letterboxd.DiaryEntry
+	statsFilms               map[string]letterboxd.Film
+	statsYear                int
+	statsReview              bool
+	allDiaryLoaded           bool
+	allDiaryLoading          bool
+	statsEnriching           bool
+	statsFetched             int
+	statsCapped              bool
+	allDiaryErr              error
+	allDiaryTruncated        bool
+	statsEnrichErr           error
+	diaryFrom                time.Time
+	diaryTo                  time.Time
+	diaryHeatmap             bool
+	heatmapCursor            time.Time
+	heatmapDayOpen           bool
+	heatmapDayList           listState
 	film                     letterboxd.Film
 	modalProfile             letterboxd.Profile
 	popReviews               []letterboxd.Review
@@ -63,6 +111,7 @@ type Model struct {
 	friendReviewsMoreErr     error
 	profileErr               error
 	diaryErr                 error
+	filmsErr                 error
 	watchErr                 error
 	activityErr              error
 	followErr                error
@@ -71,38 +120,48 @@ type Model struct {
 	friendReviewsErr         error
 	searchErr                error
 	modalProfileErr          error
+	peopleErr                error
 	loading                  bool
 	modalLoading             bool
 	searchLoading            bool
 	profileList              listState
 	modalProfileList         listState
 	diaryList                listState
+	filmsList                listState
 	watchList                listState
 	actList                  listState
 	followList               listState
 	searchList               listState
+	peopleList               listState
 	diaryPage                int
+	filmsPage                int
 	watchPage                int
+	peoplePage               int
 	diaryLoadingMore         bool
+	filmsLoadingMore         bool
 	watchLoadingMore         bool
 	activityLoadingMore      bool
 	followLoadingMore        bool
+	peopleLoadingMore        bool
 	diaryDone                bool
+	filmsDone                bool
 	watchDone                bool
 	activityDone             bool
 	followDone               bool
+	peopleDone               bool
 	diaryMoreErr             error
+	filmsMoreErr             error
 	watchMoreErr             error
 	activityMoreErr          error
 	followMoreErr            error
+	peopleMoreErr            error
 	viewport                 viewport.Model
 	modalVP                  viewport.Model
-	filmReturn               tab
-	filmReturnProfileModal   bool
-	modalReturnYOffset       int
 	profileModal             bool
 	modalUser                string
 	logModal                 bool
+	filterModal              bool
+	filterForm               filterForm
 	logForm                  logForm
 	logSpinner               spinner.Model
 	cookieModal              bool
@@ -112,10 +171,57 @@ type Model struct {
 	cookiePending            string
 	watchlistStatus          string
 	watchlistPending         bool
+	followStatus             string
+	followPending            bool
 	diarySort                diarySort
 	watchlistSort            watchlistSort
+	filmsSort                filmsSort
+	rouletteOpts             rouletteOptions
+	roulettePool             []letterboxd.WatchlistItem
+	roulettePoolCut          bool
+	roulettePending          bool
+	rouletteActive           bool
+	rouletteStatus           string
 	searchInput              textinput.Model
 	searchFocusInput         bool
+	findTab                  tab
+	findInput                textinput.Model
+	findTyping               bool
+	paletteModal             bool
+	paletteInput             textinput.Model
+	paletteList              listState
+	debugEvents              chan letterboxd.Event
+	debugStop                chan struct{}
+	debugLog                 []letterboxd.Event
+	debugPanel               bool
+	debugDetail              bool
+	debugList                listState
+	debugScroll              int
+	jumpTargets              []jumpTarget
+	back                     []location
+	forward                  []location
+	restoring                *location
+	accounts                 []config.Account
+	account                  string
+	switchTo                 string
+	accountStatus            string
+	exportStatus             string
+	loadAccount              AccountLoader
+	session                  int
+	secrets                  secrets.Store
+	sessionErr               error
+	staleClearance           time.Duration
+	cookieUnchecked          string
+	posters                  *store.Posters
+	graphics                 Graphics
+	poster                   posterState
+	splitView                bool
+	previewWant              string
+	previewSeq               int
+	previews                 map[string]letterboxd.Film
+	previewErr               error
+	theme                    themeStyles
+	themes                   Themes
 	keys                     keyMap
 	help                     help.Model
 	pendingG                 bool
@@ -123,8 +229,11 @@ type Model struct {
 }
 
 type profileSelectionEntry struct {
-	line    int
-	filmURL string
+	line       int
+	filmURL    string
+	peopleKind letterboxd.PeopleKind
+	peopleUser string
+	recent     bool
 }
 
 func NewModel(username string, client *letterboxd.Client) Model {
@@ -136,6 +245,10 @@ func NewModel(username string, client *letterboxd.Client) Model {
 	cookieInput.CharLimit = 0
 	cookieInput.EchoMode = textinput.EchoPassword
 	cookieInput.EchoCharacter = '*'
+	var debugEvents chan letterboxd.Event
+	if client != nil {
+		debugEvents = subscribeDebug(client)
+	}
 	return Model{
 		username:         username,
 		profileUser:      username,
@@ -149,10 +262,15 @@ func NewModel(username string, client *letterboxd.Client) Model {
 		cookieInput:      cookieInput,
 		searchInput:      searchInput,
 		searchFocusInput: true,
+		findInput:        newFindInput(),
+		paletteInput:     newPaletteInput(),
 		keys:             newKeyMap(),
+		theme:            newTheme(),
 		help:             help.New(),
 		diarySort:        diarySortRecent,
 		watchlistSort:    watchlistSortAdded,
+		debugEvents:      debugEvents,
+		debugStop:        make(chan struct{}),
 	}
 }
 
@@ -161,7 +279,13 @@ func profileSelectionEntries(profile letterboxd.Profile) []profileSelectionEntry
 	line := 0
 	line++
 	if len(profile.Stats) > 0 {
-		line += 2 + len(profile.Stats)
+		line += 2
+		for _, stat := range profile.Stats {
+			if kind, ok := letterboxd.PeopleKindFromURL(stat.URL); ok {
+				entries = append(entries, profileSelectionEntry{line: line, peopleKind: kind, peopleUser: letterboxd.UsernameFromURL(stat.URL)})
+			}
+			line++
+		}
 	}
 	if len(profile.Favorites) > 0 {
 		line += 2
@@ -176,7 +300,7 @@ func profileSelectionEntries(profile letterboxd.Profile) []profileSelectionEntry
 		line += 2
 		for _, recent := range profile.Recent {
 			if recent.FilmURL != "" {
-				entries = append(entries, profileSelectionEntry{line: line, filmURL: recent.FilmURL})
+				entries = append(entries, profileSelectionEntry{line: line, filmURL: recent.FilmURL, recent: true})
 			}
 			line++
 		}
@@ -199,7 +323,7 @@ func profileLineCount(profile letterboxd.Profile) int {
 }
 
 func (m Model) profileSelectableCount() int {
-	return len(profileSelectionEntries(m.profile))
+	return len(profileSelectionEntries(m.profileView()))
 }
 
 func (m Model) modalProfileSelectableCount() int {
@@ -255,7 +379,56 @@ func (m *Model) sanitizeCloudflare(err error) error {
 	return errors.New(hint)
 }
 
+// selectionList is the list state of the current tab; tabs without a
+// selection get a throwaway one.
+func (m *Model) selectionList() *listState {
+	switch m.activeTab {
+	case tabSearch:
+		return &m.searchList
+	case tabPeople:
+		return &m.peopleList
+	case tabFilms:
+		return &m.filmsList
+	case tabQueue:
+		return &m.queueList
+	}
+	if list := m.findList(); list != nil {
+		return list
+	}
+	return &listState{}
+}
+
+// selectableCount is how many entries the current tab's selection ranges
+// over, ignoring any filter.
+func (m Model) selectableCount() int {
+	switch m.activeTab {
+	case tabProfile:
+		return m.profileSelectableCount()
+	case tabDiary:
+		return len(m.diary)
+	case tabWatchlist:
+		return len(m.watchlist)
+	case tabActivity:
+		return len(m.activity)
+	case tabFollowing:
+		return len(m.following)
+	case tabSearch:
+		return len(m.searchResults)
+	case tabPeople:
+		return len(m.people)
+	case tabFilms:
+		return len(m.films)
+	case tabQueue:
+		return m.pendingOps()
+	}
+	return 0
+}
+
 func (m *Model) moveSelection(delta int) {
+	if matches, ok := m.findMatches(); ok {
+		m.moveMatch(matches, delta)
+		return
+	}
 	switch m.activeTab {
 	case tabProfile:
 		count := m.profileSelectableCount()
@@ -288,6 +461,27 @@ func (m *Model) moveSelection(delta int) {
 			return
 		}
 		m.searchList.selected = clamp(m.searchList.selected+delta, 0, len(m.searchResults)-1)
+	case tabPeople:
+		if len(m.people) == 0 {
+			return
+		}
+		m.peopleList.selected = clamp(m.peopleList.selected+delta, 0, len(m.people)-1)
+	case tabFilms:
+		if len(m.films) == 0 {
+			return
+		}
+		m.filmsList.selected = clamp(m.filmsList.selected+delta, 0, len(m.films)-1)
+	case tabQueue:
+		if count := m.pendingOps(); count > 0 {
+			m.queueList.selected = clamp(m.queueList.selected+delta, 0, count-1)
+		}
+	case tabCompare, tabStats:
+		m.refreshViewport()
+		if delta > 0 {
+			m.viewport.LineDown(delta)
+		} else {
+			m.viewport.LineUp(-delta)
+		}
 	}
 }
 
@@ -306,6 +500,9 @@ func (m *Model) resetTabPosition() {
 	m.viewport.YOffset = 0
 	m.searchInput.Blur()
 	m.searchFocusInput = false
+	if m.activeTab != tabFilm {
+		m.clearFind()
+	}
 	switch m.activeTab {
 	case tabProfile:
 		m.profileList.selected = 0
@@ -323,7 +520,14 @@ func (m *Model) resetTabPosition() {
 		m.actList.selected = 0
 	case tabFollowing:
 		m.followList.selected = 0
-	}
+	case tabPeople:
+		m.peopleList.selected = 0
+	case tabFilms:
+		m.filmsList.selected = 0
+	case tabQueue:
+		m.queueList.selected = 0
+	}
+	m.snapToMatch()
 	m.lastTab = m.activeTab
 	m.resizeViewport()
 }
@@ -332,8 +536,8 @@ func (m *Model) resizeViewport() {
 	if m.width <= 0 || m.height <= 0 {
 		return
 	}
-	theme := newTheme()
-	header := theme.header.Render("Letterboxd TUI") + " " + theme.subtle.Render("@"+m.username)
+	theme := m.theme
+	header := renderHeader(*m, theme)
 	tabLine := renderTabs(*m, theme)
 	footer := renderHelp(*m, theme, m.width)
 	chromeHeight := lipgloss.Height(header) + lipgloss.Height(tabLine) + lipgloss.Height(footer)
@@ -342,10 +546,27 @@ func (m *Model) resizeViewport() {
 	m.viewport.Width = m.width
 	m.viewport.Height = bodyHeight
 	m.help.Width = m.width
+	m.fitListWidth()
+}
+
+// refreshViewport loads the rendered body into the viewport so tabs without a
+// selection can scroll by line.
+func (m *Model) refreshViewport() {
+	theme := m.theme
+	switch m.activeTab {
+	case tabCompare:
+		m.viewport.SetContent(renderCompare(*m, theme))
+	case tabStats:
+		m.viewport.SetContent(renderStats(*m, theme))
+	}
 }
 
 func (m *Model) pageSelection(dir int) {
 	step := max(1, m.viewport.Height-1)
+	if matches, ok := m.findMatches(); ok {
+		m.moveMatch(matches, dir*step)
+		return
+	}
 	switch m.activeTab {
 	case tabProfile:
 		count := m.profileSelectableCount()
@@ -378,6 +599,27 @@ func (m *Model) pageSelection(dir int) {
 			return
 		}
 		m.searchList.selected = clamp(m.searchList.selected+dir*step, 0, len(m.searchResults)-1)
+	case tabPeople:
+		if len(m.people) == 0 {
+			return
+		}
+		m.peopleList.selected = clamp(m.peopleList.selected+dir*step, 0, len(m.people)-1)
+	case tabFilms:
+		if len(m.films) == 0 {
+			return
+		}
+		m.filmsList.selected = clamp(m.filmsList.selected+dir*step, 0, len(m.films)-1)
+	case tabQueue:
+		if count := m.pendingOps(); count > 0 {
+			m.queueList.selected = clamp(m.queueList.selected+dir*step, 0, count-1)
+		}
+	case tabCompare, tabStats:
+		m.refreshViewport()
+		if dir > 0 {
+			m.viewport.ViewDown()
+		} else {
+			m.viewport.ViewUp()
+		}
 	}
 }
 
@@ -394,12 +636,12 @@ func (m *Model) syncViewportToSelection() {
 	var total, selected int
 	switch m.activeTab {
 	case tabProfile:
-		entries := profileSelectionEntries(m.profile)
+		entries := profileSelectionEntries(m.profileView())
 		if len(entries) == 0 {
 			return
 		}
 		selected = clamp(m.profileList.selected, 0, len(entries)-1)
-		total = profileLineCount(m.profile)
+		total = profileLineCount(m.profileView())
 		selected = entries[selected].line
 	case tabDiary:
 		total = len(m.diary)
@@ -416,12 +658,29 @@ func (m *Model) syncViewportToSelection() {
 	case tabSearch:
 		total = len(m.searchResults)
 		selected = m.searchList.selected
+	case tabPeople:
+		total = len(m.people)
+		selected = m.peopleList.selected
+	case tabFilms:
+		total = len(m.films)
+		selected = m.filmsList.selected
+	case tabQueue:
+		total = m.pendingOps()
+		selected = m.queueList.selected
 	default:
 		return
 	}
+	if matches, ok := m.findMatches(); ok && m.activeTab != tabProfile {
+		total = len(matches)
+		selected, _ = matchPosition(matches, selected)
+	}
 	if total == 0 || m.viewport.Height <= 0 {
 		return
 	}
+	if offset := m.listHeaderLines(); offset > 0 {
+		total += offset
+		selected += offset
+	}
 	top := m.viewport.YOffset
 	bottom := top + m.viewport.Height - 1
 	if selected < top {
@@ -479,7 +738,16 @@ func (m *Model) jumpToTop() {
 		m.modalVP.GotoTop()
 		return
 	}
+	if matches, ok := m.findMatches(); ok {
+		if len(matches) > 0 {
+			m.findList().selected = matches[0]
+			m.syncViewportToSelection()
+		}
+		return
+	}
 	switch m.activeTab {
+	case tabCompare, tabStats:
+		m.viewport.GotoTop()
 	case tabProfile:
 		if m.profileSelectableCount() == 0 {
 			m.viewport.GotoTop()
@@ -517,6 +785,21 @@ func (m *Model) jumpToTop() {
 		}
 		m.searchList.selected = 0
 		m.syncViewportToSelection()
+	case tabPeople:
+		if len(m.people) == 0 {
+			return
+		}
+		m.peopleList.selected = 0
+		m.syncViewportToSelection()
+	case tabFilms:
+		if len(m.films) == 0 {
+			return
+		}
+		m.filmsList.selected = 0
+		m.syncViewportToSelection()
+	case tabQueue:
+		m.queueList.selected = 0
+		m.syncViewportToSelection()
 	default:
 		m.viewport.GotoTop()
 	}
@@ -536,7 +819,17 @@ func (m *Model) jumpToBottom() {
 		m.modalVP.GotoBottom()
 		return
 	}
+	if matches, ok := m.findMatches(); ok {
+		if len(matches) > 0 {
+			m.findList().selected = matches[len(matches)-1]
+			m.syncViewportToSelection()
+		}
+		return
+	}
 	switch m.activeTab {
+	case tabCompare, tabStats:
+		m.refreshViewport()
+		m.viewport.GotoBottom()
 	case tabProfile:
 		count := m.profileSelectableCount()
 		if count == 0 {
@@ -575,14 +868,34 @@ func (m *Model) jumpToBottom() {
 		}
 		m.searchList.selected = len(m.searchResults) - 1
 		m.syncViewportToSelection()
+	case tabPeople:
+		if len(m.people) == 0 {
+			return
+		}
+		m.peopleList.selected = len(m.people) - 1
+		m.syncViewportToSelection()
+	case tabFilms:
+		if len(m.films) == 0 {
+			return
+		}
+		m.filmsList.selected = len(m.films) - 1
+		m.syncViewportToSelection()
+	case tabQueue:
+		m.queueList.selected = max(0, m.pendingOps()-1)
+		m.syncViewportToSelection()
 	default:
 		m.viewport.GotoBottom()
 	}
 }
 
 func (m *Model) resetPagination() {
+	m.roulettePool = nil
 	m.diaryPage = 0
 	m.watchPage = 0
+	m.filmsPage = 0
+	m.filmsLoadingMore = false
+	m.filmsDone = false
+	m.filmsMoreErr = nil
 	m.diaryLoadingMore = false
 	m.watchLoadingMore = false
 	m.activityLoadingMore = false
@@ -609,6 +922,34 @@ func (m *Model) resetDiaryList() {
 	m.loading = true
 }
 
+func (m *Model) resetFilmsList() {
+	m.films = nil
+	m.filmsErr = nil
+	m.filmsPage = 0
+	m.filmsLoadingMore = false
+	m.filmsDone = false
+	m.filmsMoreErr = nil
+	m.filmsList.selected = 0
+	m.viewport.YOffset = 0
+	m.loading = true
+}
+
+func (m Model) listHeaderLines() int {
+	lines := 0
+	if m.findShown() {
+		lines++
+	}
+	switch m.activeTab {
+	case tabFilms, tabWatchlist, tabQueue:
+		lines++
+	case tabDiary:
+		if m.diaryRangeActive() {
+			lines++
+		}
+	}
+	return lines
+}
+
 func (m *Model) resetWatchlist() {
 	m.watchlist = nil
 	m.watchErr = nil
@@ -655,7 +996,7 @@ func (m *Model) maybeLoadMoreCmd() tea.Cmd {
 			page = 2
 		}
 		m.watchLoadingMore = true
-		return fetchWatchlistCmd(m.client, m.username, page, m.watchlistSortParam())
+		return fetchWatchlistCmd(m.client, m.username, page, m.watchlistQuery())
 	case tabActivity:
 		if m.activityLoadingMore || m.activityDone || m.activityMoreErr != nil {
 			return nil
@@ -691,6 +1032,30 @@ func (m *Model) maybeLoadMoreCmd() tea.Cmd {
 		}
 		m.followLoadingMore = true
 		return fetchActivityCmd(m.client, m.username, tabFollowing, after)
+	case tabFilms:
+		if m.filmsLoadingMore || m.filmsDone || m.filmsMoreErr != nil {
+			return nil
+		}
+		if len(m.films) == 0 || m.filmsPage == 0 {
+			return nil
+		}
+		if m.filmsList.selected < len(m.films)-1-threshold {
+			return nil
+		}
+		m.filmsLoadingMore = true
+		return fetchUserFilmsCmd(m.client, m.username, m.filmsQuery(), m.filmsPage+1)
+	case tabPeople:
+		if m.peopleLoadingMore || m.peopleDone || m.peopleMoreErr != nil {
+			return nil
+		}
+		if len(m.people) == 0 || m.peoplePage == 0 {
+			return nil
+		}
+		if m.peopleList.selected < len(m.people)-1-threshold {
+			return nil
+		}
+		m.peopleLoadingMore = true
+		return fetchPeopleCmd(m.client, m.peopleUser, m.peopleKind, m.peoplePage+1)
 	}
 	return nil
 }
@@ -731,7 +1096,7 @@ func (m *Model) maybeFillCmd() tea.Cmd {
 			page = 2
 		}
 		m.watchLoadingMore = true
-		return fetchWatchlistCmd(m.client, m.username, page, m.watchlistSortParam())
+		return fetchWatchlistCmd(m.client, m.username, page, m.watchlistQuery())
 	case tabActivity:
 		if m.activityLoadingMore || m.activityDone || m.activityMoreErr != nil {
 			return nil
@@ -767,10 +1132,51 @@ func (m *Model) maybeFillCmd() tea.Cmd {
 		}
 		m.followLoadingMore = true
 		return fetchActivityCmd(m.client, m.username, tabFollowing, after)
+	case tabFilms:
+		if m.filmsLoadingMore || m.filmsDone || m.filmsMoreErr != nil {
+			return nil
+		}
+		if m.filmsPage == 0 || len(m.films) == 0 {
+			return nil
+		}
+		if len(m.films)+m.listHeaderLines() >= m.viewport.Height {
+			return nil
+		}
+		m.filmsLoadingMore = true
+		return fetchUserFilmsCmd(m.client, m.username, m.filmsQuery(), m.filmsPage+1)
+	case tabPeople:
+		if m.peopleLoadingMore || m.peopleDone || m.peopleMoreErr != nil {
+			return nil
+		}
+		if m.peoplePage == 0 || len(m.people) == 0 {
+			return nil
+		}
+		if len(m.people) >= m.viewport.Height {
+			return nil
+		}
+		m.peopleLoadingMore = true
+		return fetchPeopleCmd(m.client, m.peopleUser, m.peopleKind, m.peoplePage+1)
+	case tabStats:
+		if m.allDiaryLoaded {
+			return m.statsEnrichCmd()
+		}
+		return m.loadAllDiaryCmd()
 	}
 	return nil
 }
 
+func (m *Model) loadAllDiaryCmd() tea.Cmd {
+	if m.allDiaryLoaded || m.allDiaryLoading {
+		return nil
+	}
+	m.allDiaryLoading = true
+	m.allDiaryErr = nil
+	if m.store != nil {
+		return syncAllDiaryCmd(m.client, m.store)
+	}
+	return fetchAllDiaryCmd(m.client, m.username)
+}
+
 func (m Model) hasPopularReviewsSection() bool {
 	return len(m.popReviews) > 0 || m.popReviewsErr != nil || m.popReviewsLoadingMore || m.popReviewsMoreErr != nil
 }
@@ -849,18 +1255,35 @@ func lastActivityID(items []letterboxd.ActivityItem) string {
 }
 
 func (m Model) openSelectedProfile() Model {
-	if m.activeTab != tabFollowing || len(m.following) == 0 {
+	var username string
+	switch m.activeTab {
+	case tabFollowing:
+		if len(m.following) == 0 {
+			return m
+		}
+		item := m.following[m.followList.selected]
+		username = letterboxd.UsernameFromURL(item.ActorURL)
+		if username == "" {
+			username = letterboxd.UsernameFromURL(item.FilmURL)
+		}
+	case tabPeople:
+		if len(m.people) == 0 {
+			return m
+		}
+		username = m.people[clamp(m.peopleList.selected, 0, len(m.people)-1)].Username
+	default:
 		return m
 	}
-	item := m.following[m.followList.selected]
-	username := letterboxd.UsernameFromURL(item.ActorURL)
-	if username == "" {
-		username = letterboxd.UsernameFromURL(item.FilmURL)
-	}
 	if username == "" {
 		return m
 	}
+	return m.openProfileModal(username)
+}
+
+func (m Model) openProfileModal(username string) Model {
+	m.rememberTarget(jumpTarget{title: "@" + username, username: username})
 	m.modalUser = username
+	m.followStatus = ""
 	m.modalProfile = letterboxd.Profile{}
 	m.modalProfileErr = nil
 	m.modalLoading = true
@@ -882,72 +1305,60 @@ func (m Model) openSelectedModalFilm() Model {
 	if filmURL == "" {
 		return m
 	}
-	m.film = letterboxd.Film{URL: filmURL}
-	m.filmErr = nil
-	m.popReviews = nil
-	m.friendReviews = nil
-	m.popReviewsPage = 0
-	m.friendReviewsPage = 0
-	m.popReviewsDone = false
-	m.friendReviewsDone = false
-	m.popReviewsLoadingMore = false
-	m.friendReviewsLoadingMore = false
-	m.popReviewsMoreErr = nil
-	m.friendReviewsMoreErr = nil
-	m.popReviewsErr = nil
-	m.friendReviewsErr = nil
-	m.watchlistPending = false
-	m.watchlistStatus = ""
-	m.filmReturn = m.activeTab
-	m.filmReturnProfileModal = true
-	m.modalReturnYOffset = m.modalVP.YOffset
-	m.activeTab = tabFilm
 	m.profileModal = false
-	m.loading = true
-	m.viewport.YOffset = 0
-	m.modalVP.YOffset = 0
-	m.modalVP.SetContent("")
-	m.refreshModalViewport()
-	(&m).resizeViewport()
-	return m
+	return m.openFilm(filmURL)
 }
 
 func (m Model) openSelectedFilm() Model {
+	return m.openFilm(m.selectedFilmURL())
+}
+
+// selectedFilmURL is the film behind the current tab's selection, if any.
+func (m Model) selectedFilmURL() string {
 	var filmURL string
 	switch m.activeTab {
 	case tabProfile:
-		entries := profileSelectionEntries(m.profile)
+		entries := profileSelectionEntries(m.profileView())
 		if len(entries) == 0 {
-			return m
+			return ""
 		}
 		selected := clamp(m.profileList.selected, 0, len(entries)-1)
 		filmURL = entries[selected].filmURL
 	case tabDiary:
 		if len(m.diary) == 0 {
-			return m
+			return ""
 		}
 		filmURL = m.diary[m.diaryList.selected].FilmURL
 	case tabWatchlist:
 		if len(m.watchlist) == 0 {
-			return m
+			return ""
 		}
 		filmURL = m.watchlist[m.watchList.selected].FilmURL
 	case tabActivity:
 		if len(m.activity) == 0 {
-			return m
+			return ""
 		}
 		filmURL = m.activity[m.actList.selected].FilmURL
 	case tabFollowing:
 		if len(m.following) == 0 {
-			return m
+			return ""
 		}
 		filmURL = m.following[m.followList.selected].FilmURL
 	case tabSearch:
 		if len(m.searchResults) == 0 {
-			return m
+			return ""
 		}
 		filmURL = m.searchResults[m.searchList.selected].FilmURL
+	case tabFilms:
+		if len(m.films) == 0 {
+			return ""
+		}
+		filmURL = m.films[m.filmsList.selected].FilmURL
 	}
+	return filmURL
+}
+
+func (m Model) openFilm(filmURL string) Model {
 	filmURL = letterboxd.NormalizeFilmURL(filmURL)
 	if filmURL == "" {
 		return m
@@ -968,9 +1379,7 @@ func (m Model) openSelectedFilm() Model {
 	m.friendReviewsErr = nil
 	m.watchlistPending = false
 	m.watchlistStatus = ""
-	m.filmReturn = m.activeTab
-	m.filmReturnProfileModal = false
-	m.modalReturnYOffset = 0
+	m.rouletteActive = false
 	m.activeTab = tabFilm
 	m.loading = true
 	m.viewport.YOffset = 0
@@ -981,19 +1390,97 @@ func (m Model) openSelectedFilm() Model {
 	return m
 }
 
-func (m Model) goBackProfile() Model {
-	if len(m.profileStack) == 0 {
+func (m Model) selectedProfileEntry() (profileSelectionEntry, bool) {
+	entries := profileSelectionEntries(m.profileView())
+	if len(entries) == 0 {
+		return profileSelectionEntry{}, false
+	}
+	return entries[clamp(m.profileList.selected, 0, len(entries)-1)], true
+}
+
+func (m Model) selectedModalProfileEntry() (profileSelectionEntry, bool) {
+	entries := profileSelectionEntries(m.modalProfile)
+	if len(entries) == 0 {
+		return profileSelectionEntry{}, false
+	}
+	return entries[clamp(m.modalProfileList.selected, 0, len(entries)-1)], true
+}
+
+func (m Model) openPeople(username string, kind letterboxd.PeopleKind) Model {
+	if username == "" || kind == "" {
 		return m
 	}
-	last := m.profileStack[len(m.profileStack)-1]
-	m.profileStack = m.profileStack[:len(m.profileStack)-1]
-	m.profileUser = last
-	m.activeTab = tabProfile
+	if m.activeTab != tabPeople {
+		m.peopleReturn = m.activeTab
+	}
+	m.peopleUser = username
+	m.peopleKind = kind
+	m.people = nil
+	m.peopleErr = nil
+	m.peoplePage = 0
+	m.peopleLoadingMore = false
+	m.peopleDone = false
+	m.peopleMoreErr = nil
+	m.followStatus = ""
+	m.activeTab = tabPeople
+	m.resetTabPosition()
 	m.loading = true
-	(&m).resizeViewport()
 	return m
 }
 
+func (m Model) closePeople() Model {
+	m.activeTab = m.peopleReturn
+	if m.activeTab == tabPeople || m.activeTab == tabFilm {
+		m.activeTab = tabProfile
+	}
+	m.followStatus = ""
+	m.resetTabPosition()
+	return m
+}
+
+func (m Model) followTarget() (string, bool, bool) {
+	if m.profileModal {
+		if m.modalUser == "" || m.modalUser == m.username {
+			return "", false, false
+		}
+		return m.modalUser, m.modalProfile.Followed, m.modalProfile.FollowOK
+	}
+	if m.activeTab == tabPeople && len(m.people) > 0 {
+		member := m.people[clamp(m.peopleList.selected, 0, len(m.people)-1)]
+		if member.Username == m.username {
+			return "", false, false
+		}
+		return member.Username, member.Followed, member.FollowOK
+	}
+	return "", false, false
+}
+
+func (m Model) modalProfileNote() string {
+	if m.followStatus != "" {
+		return m.followStatus
+	}
+	if !m.hasCookie() || !m.modalProfile.FollowOK || m.modalUser == m.username {
+		return ""
+	}
+	if m.modalProfile.Followed {
+		return "Following"
+	}
+	return "Not following"
+}
+
+func (m *Model) applyFollowState(username string, followed bool) {
+	for i := range m.people {
+		if m.people[i].Username == username {
+			m.people[i].Followed = followed
+			m.people[i].FollowOK = true
+		}
+	}
+	if m.modalUser == username {
+		m.modalProfile.Followed = followed
+		m.modalProfile.FollowOK = true
+	}
+}
+
 func (m Model) startLogModal() Model {
 	if m.film.ViewingUID == "" {
 		m.filmErr = errors.New("cannot log this film (missing id)")
@@ -1075,9 +1562,32 @@ func (m Model) watchlistState() (bool, bool) {
 			return true, true
 		}
 	}
+	if m.watchlistFilter.Active() {
+		return false, false
+	}
 	return false, true
 }
 
+func (m Model) openFilterModal() Model {
+	switch m.activeTab {
+	case tabFilms:
+		return m.showFilterForm(newFilmsFilterForm(m.filmsFilter))
+	case tabWatchlist:
+		return m.showFilterForm(newWatchlistFilterForm(m.watchlistFilter))
+	case tabDiary:
+		return m.showFilterForm(newDiaryJumpForm(m.diaryFrom, m.diaryTo))
+	}
+	return m
+}
+
+func (m Model) showFilterForm(form filterForm) Model {
+	m.filterForm = form
+	m.filterForm.setWidth(m.width)
+	m.filterModal = true
+	(&m).resizeViewport()
+	return m
+}
+
 func (m Model) modalOpen() bool {
-	return m.activeTab == tabFilm || m.profileModal || m.logModal || m.cookieModal
+	return m.activeTab == tabFilm || m.profileModal || m.logModal || m.cookieModal || m.filterModal || m.paletteModal || m.debugPanel
 }
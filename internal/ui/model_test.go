@@ -95,15 +95,6 @@ func TestOpenSelectedModalFilm(t *testing.T) {
 	}
 }
 
-func TestGoBackProfile(t *testing.T) {
-	m := NewModel("jane", nil)
-	m.profileStack = []string{"alice"}
-	m = m.goBackProfile()
-	if m.profileUser != "alice" {
-		t.Fatalf("unexpected profile user: %q", m.profileUser)
-	}
-}
-
 func TestStartLogModal(t *testing.T) {
 	m := NewModel("jane", nil)
 	m = m.startLogModal()
@@ -193,3 +184,51 @@ func TestModalOpen(t *testing.T) {
 		t.Fatalf("expected modal open")
 	}
 }
+
+func TestOpenPeopleFromProfileStat(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.activeTab = tabProfile
+	m.profile = letterboxd.Profile{
+		Stats: []letterboxd.ProfileStat{
+			{Label: "Films", Value: "10", URL: letterboxd.BaseURL + "/jane/films/"},
+			{Label: "Followers", Value: "3", URL: letterboxd.BaseURL + "/jane/followers/"},
+		},
+		Favorites: []letterboxd.FavoriteFilm{{FilmURL: letterboxd.BaseURL + "/film/inception/"}},
+	}
+	entry, ok := m.selectedProfileEntry()
+	if !ok || entry.peopleKind != letterboxd.PeopleFollowers || entry.peopleUser != "jane" {
+		t.Fatalf("unexpected profile entry: %+v", entry)
+	}
+	m = m.openPeople(entry.peopleUser, entry.peopleKind)
+	if m.activeTab != tabPeople || m.peopleReturn != tabProfile || !m.loading {
+		t.Fatalf("unexpected people state: tab=%v return=%v", m.activeTab, m.peopleReturn)
+	}
+	m.people = []letterboxd.Member{{Username: "alice"}}
+	m = m.openSelectedProfile()
+	if !m.profileModal || m.modalUser != "alice" {
+		t.Fatalf("expected profile modal for member, got %q", m.modalUser)
+	}
+	m.profileModal = false
+	m = m.closePeople()
+	if m.activeTab != tabProfile {
+		t.Fatalf("expected return to profile, got %v", m.activeTab)
+	}
+}
+
+func TestFollowTarget(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.activeTab = tabPeople
+	m.people = []letterboxd.Member{{Username: "jane"}, {Username: "alice", Followed: true, FollowOK: true}}
+	if user, _, _ := m.followTarget(); user != "" {
+		t.Fatalf("expected no target for self, got %q", user)
+	}
+	m.peopleList.selected = 1
+	user, followed, known := m.followTarget()
+	if user != "alice" || !followed || !known {
+		t.Fatalf("unexpected follow target: %q %v %v", user, followed, known)
+	}
+	m.applyFollowState("alice", false)
+	if m.people[1].Followed {
+		t.Fatalf("expected follow state to update")
+	}
+}
@@ -0,0 +1,245 @@
+package ui
+
+import (
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const wheelLines = 3
+
+func (m Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+	dir := 0
+	switch msg.Button {
+	case tea.MouseButtonWheelDown:
+		dir = 1
+	case tea.MouseButtonWheelUp:
+		dir = -1
+	case tea.MouseButtonLeft:
+	default:
+		return m, nil
+	}
+	if m.modalOpen() {
+		if dir != 0 {
+			return m.scrollModal(dir)
+		}
+		if !m.inModal(msg.X, msg.Y) {
+			return m.dismissModal()
+		}
+		return m, nil
+	}
+	if dir != 0 {
+		return m.scrollList(dir)
+	}
+	if t, ok := m.tabAt(msg.X, msg.Y); ok {
+		if t == m.activeTab {
+			return m, nil
+		}
+		return goToTab(t)(m)
+	}
+	if list, _ := m.splitWidths(); m.splitActive() && msg.X >= list {
+		return m, nil
+	}
+	if index, ok := m.rowAt(msg.Y); ok {
+		return m.clickRow(index)
+	}
+	return m, nil
+}
+
+func (m Model) scrollModal(dir int) (tea.Model, tea.Cmd) {
+	switch {
+	case m.cookieModal, m.logModal, m.filterModal:
+		return m, nil
+	case m.debugPanel:
+		if dir > 0 {
+			return m.updateDebugPanel(tea.KeyMsg{Type: tea.KeyDown})
+		}
+		return m.updateDebugPanel(tea.KeyMsg{Type: tea.KeyUp})
+	case m.paletteModal:
+		if dir > 0 {
+			return m.updatePalette(tea.KeyMsg{Type: tea.KeyDown})
+		}
+		return m.updatePalette(tea.KeyMsg{Type: tea.KeyUp})
+	case m.profileModal && m.modalProfileSelectableCount() > 0:
+		m.moveModalProfileSelection(dir * wheelLines)
+		m.syncModalViewportToSelection()
+		m.refreshModalViewport()
+		return m, nil
+	}
+	if dir > 0 {
+		m.modalVP.LineDown(wheelLines)
+	} else {
+		m.modalVP.LineUp(wheelLines)
+	}
+	if m.activeTab == tabFilm {
+		return m, m.maybeLoadMoreReviewsCmd()
+	}
+	return m, nil
+}
+
+func (m Model) dismissModal() (tea.Model, tea.Cmd) {
+	binding := m.keys.ModalBack
+	if m.cookieModal || m.paletteModal || m.filterModal || m.logModal || m.debugPanel {
+		binding = m.keys.Cancel
+	}
+	if len(binding.Keys()) == 0 {
+		return m, nil
+	}
+	return m.update(keyMsgFor(binding.Keys()[0]))
+}
+
+func (m Model) scrollList(dir int) (tea.Model, tea.Cmd) {
+	switch {
+	case m.activeTab == tabDiary && m.diaryHeatmap:
+		return m, nil
+	case m.activeTab == tabProfile && m.profileSelectableCount() == 0:
+		if dir > 0 {
+			m.viewport.LineDown(wheelLines)
+		} else {
+			m.viewport.LineUp(wheelLines)
+		}
+		return m, nil
+	}
+	m.moveSelection(dir * wheelLines)
+	m.syncViewportToSelection()
+	return m, m.maybeLoadMoreCmd()
+}
+
+func (m Model) clickRow(index int) (tea.Model, tea.Cmd) {
+	if m.activeTab == tabSearch {
+		m.searchFocusInput = false
+		m.searchInput.Blur()
+	}
+	if m.findTyping {
+		m.findTyping = false
+		m.findInput.Blur()
+	}
+	list := m.selectionList()
+	if list.selected != index {
+		list.selected = index
+		m.syncViewportToSelection()
+		return m, nil
+	}
+	if len(m.keys.Select.Keys()) == 0 {
+		return m, nil
+	}
+	return m.update(keyMsgFor(m.keys.Select.Keys()[0]))
+}
+
+func (m Model) tabAt(x, y int) (tab, bool) {
+	if y != lipgloss.Height(renderHeader(m, m.theme)) {
+		return 0, false
+	}
+	left := 0
+	for _, item := range visibleTabItems(m) {
+		style := m.theme.tab
+		if item.id == m.activeTab {
+			style = m.theme.tabActive
+		}
+		width := lipgloss.Width(style.Render(item.label))
+		if x >= left && x < left+width {
+			return item.id, true
+		}
+		left += width
+	}
+	return 0, false
+}
+
+// rowAt maps a screen row back to the index of the list entry drawn there,
+// going through the viewport offset and any lines above the list.
+func (m Model) rowAt(y int) (int, bool) {
+	line := y - lipgloss.Height(renderHeader(m, m.theme)) - lipgloss.Height(renderTabs(m, m.theme))
+	if line < 0 || line >= m.viewport.Height {
+		return 0, false
+	}
+	line += m.viewport.YOffset - m.listHeaderLines()
+	if m.activeTab == tabProfile {
+		for i, entry := range profileSelectionEntries(m.profileView()) {
+			if entry.line == line {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+	switch m.activeTab {
+	case tabDiary:
+		if m.diaryHeatmap {
+			return 0, false
+		}
+	case tabSearch:
+		line -= m.searchResultsOffset()
+	}
+	if matches, ok := m.findMatches(); ok {
+		if line < 0 || line >= len(matches) {
+			return 0, false
+		}
+		return matches[line], true
+	}
+	if line < 0 || line >= m.selectableCount() {
+		return 0, false
+	}
+	return line, true
+}
+
+func (m Model) searchResultsOffset() int {
+	return lipgloss.Height(lipgloss.JoinVertical(lipgloss.Left, searchHeaderRows(m, m.theme)...))
+}
+
+func (m Model) inModal(x, y int) bool {
+	layers := modalLayers(m)
+	for i := len(layers) - 1; i >= 0; i-- {
+		if !layers[i].open {
+			continue
+		}
+		panel := layers[i].render(m, m.theme)
+		if panel == "" {
+			continue
+		}
+		width, height := lipgloss.Width(panel), lipgloss.Height(panel)
+		left := placeOffset(m.width, width, lipgloss.Center)
+		top := placeOffset(m.height, height, layers[i].vertical)
+		return x >= left && x < left+width && y >= top && y < top+height
+	}
+	return false
+}
+
+func placeOffset(total, size int, pos lipgloss.Position) int {
+	gap := total - size
+	switch {
+	case gap <= 0 || pos <= lipgloss.Top:
+		return 0
+	case pos >= lipgloss.Bottom:
+		return gap
+	}
+	return gap - int(math.Round(float64(gap)*float64(pos)))
+}
+
+func keyMsgFor(k string) tea.KeyMsg {
+	msg := tea.KeyMsg{}
+	if rest, ok := strings.CutPrefix(k, "alt+"); ok {
+		msg.Alt = true
+		k = rest
+	}
+	if runes := []rune(k); len(runes) == 1 {
+		msg.Type = tea.KeyRunes
+		msg.Runes = runes
+		return msg
+	}
+	if k == "space" {
+		msg.Type = tea.KeySpace
+		msg.Runes = []rune{' '}
+		return msg
+	}
+	for t := tea.KeyType(-128); t < 128; t++ {
+		if t.String() == k {
+			msg.Type = t
+			return msg
+		}
+	}
+	return msg
+}
@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+// screenPos finds text on the last screenful of the rendered view, the part
+// a terminal of m.height rows shows.
+func screenPos(t *testing.T, m Model, text string) (int, int) {
+	t.Helper()
+	lines := strings.Split(stripANSI(m.View()), "\n")
+	lines = lines[max(0, len(lines)-m.height):]
+	for y, line := range lines {
+		if x := strings.Index(line, text); x >= 0 {
+			return len([]rune(line[:x])), y
+		}
+	}
+	t.Fatalf("%q not on screen", text)
+	return 0, 0
+}
+
+func click(m Model, x, y int) (Model, tea.Cmd) {
+	updated, cmd := m.Update(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	return updated.(Model), cmd
+}
+
+func wheel(m Model, button tea.MouseButton) (Model, tea.Cmd) {
+	updated, cmd := m.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: button})
+	return updated.(Model), cmd
+}
+
+func sizedModel(t *testing.T, height int) Model {
+	t.Helper()
+	updated, _ := NewModel("jane", nil).Update(tea.WindowSizeMsg{Width: 100, Height: height})
+	return updated.(Model)
+}
+
+func TestClickSelectsThenOpensDiaryRow(t *testing.T) {
+	m := sizedModel(t, 20)
+	m.activeTab = tabDiary
+	m.lastTab = tabDiary
+	m.loading = false
+	m.diary = []letterboxd.DiaryEntry{
+		{Title: "Alien", FilmURL: "https://letterboxd.com/film/alien/"},
+		{Title: "Heat", FilmURL: "https://letterboxd.com/film/heat/"},
+	}
+
+	x, y := screenPos(t, m, "Heat")
+	m, _ = click(m, x, y)
+	if m.diaryList.selected != 1 || m.activeTab != tabDiary {
+		t.Fatalf("expected click to select Heat, got %d", m.diaryList.selected)
+	}
+	m, cmd := click(m, x, y)
+	if m.activeTab != tabFilm || m.film.URL != "https://letterboxd.com/film/heat/" || cmd == nil {
+		t.Fatalf("expected second click to open Heat, got %v %q", m.activeTab, m.film.URL)
+	}
+
+	m, _ = click(m, 0, m.height-1)
+	if m.activeTab != tabDiary {
+		t.Fatalf("expected click outside the film popup to close it, got %v", m.activeTab)
+	}
+}
+
+func TestClickMapsRowsThroughFilterAndScroll(t *testing.T) {
+	m := sizedModel(t, 12)
+	m.activeTab = tabWatchlist
+	m.lastTab = tabWatchlist
+	m.loading = false
+	for _, title := range []string{"Alien", "Heat", "Aliens", "Ran", "Alien 3", "Jaws", "Solaris", "Stalker", "Mirror", "Ikiru", "Tampopo", "Brazil", "Akira", "Yojimbo", "Alphaville"} {
+		m.watchlist = append(m.watchlist, letterboxd.WatchlistItem{Title: title})
+	}
+	m.watchList.selected = len(m.watchlist) - 1
+	m.syncViewportToSelection()
+	if m.viewport.YOffset == 0 {
+		t.Fatalf("expected the list to scroll")
+	}
+	x, y := screenPos(t, m, "Akira")
+	m, _ = click(m, x, y)
+	if m.watchList.selected != 12 {
+		t.Fatalf("expected the scrolled row to map to Akira, got %d", m.watchList.selected)
+	}
+
+	m = typeKeys(m, "/alien")
+	x, y = screenPos(t, m, "Alien 3")
+	m, _ = click(m, x, y)
+	if m.watchList.selected != 4 || m.findTyping {
+		t.Fatalf("expected the filtered row to map to Alien 3, got %d typing=%v", m.watchList.selected, m.findTyping)
+	}
+}
+
+func TestClickTabAndWheel(t *testing.T) {
+	m := sizedModel(t, 20)
+	m.loading = false
+	x, y := screenPos(t, m, "Watchlist")
+	m, _ = click(m, x+2, y)
+	if m.activeTab != tabWatchlist {
+		t.Fatalf("expected the tab click to switch tabs, got %v", m.activeTab)
+	}
+
+	m.activity = make([]letterboxd.ActivityItem, 10)
+	m.activeTab = tabActivity
+	m, _ = wheel(m, tea.MouseButtonWheelDown)
+	if m.actList.selected != wheelLines {
+		t.Fatalf("expected wheel to move the selection, got %d", m.actList.selected)
+	}
+	m, _ = wheel(m, tea.MouseButtonWheelUp)
+	if m.actList.selected != 0 {
+		t.Fatalf("expected wheel up to move back, got %d", m.actList.selected)
+	}
+}
+
+func TestClickInsidePaletteKeepsItOpen(t *testing.T) {
+	m := sizedModel(t, 20)
+	m = typeKeys(m, ":")
+	x, y := screenPos(t, m, "Refresh")
+	m, _ = click(m, x, y)
+	if !m.paletteModal {
+		t.Fatalf("expected a click inside the palette to keep it open")
+	}
+	m, _ = click(m, x, m.height-1)
+	if m.paletteModal {
+		t.Fatalf("expected a click outside the palette to close it")
+	}
+}
+
+func TestClickSearchResultBelowError(t *testing.T) {
+	m := sizedModel(t, 20)
+	m.activeTab = tabSearch
+	m.lastTab = tabSearch
+	m.searchFocusInput = false
+	m.searchErr = errors.New("timeout")
+	m.searchResults = []letterboxd.SearchResult{
+		{Title: "Alien", FilmURL: "https://letterboxd.com/film/alien/"},
+		{Title: "Heat", FilmURL: "https://letterboxd.com/film/heat/"},
+	}
+	x, y := screenPos(t, m, "Heat")
+	m, _ = click(m, x, y)
+	if m.searchList.selected != 1 {
+		t.Fatalf("expected the click to select Heat, got %d", m.searchList.selected)
+	}
+}
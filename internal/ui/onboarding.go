@@ -8,6 +8,8 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/solean/letterboxd-tui/internal/secrets"
 )
 
 type OnboardingOptions struct {
@@ -16,12 +18,23 @@ type OnboardingOptions struct {
 	NeedUser   bool
 	NeedCookie bool
 	ConfigPath string
+	Themes     Themes
+	// NeedStorage asks where cookies should be kept. CookieStore is the
+	// current choice, SecretsPath the encrypted file, which SecretsExist
+	// says is already there, so its passphrase is checked rather than set.
+	NeedStorage      bool
+	KeyringAvailable bool
+	CookieStore      string
+	SecretsPath      string
+	SecretsExist     bool
 }
 
 type OnboardingResult struct {
-	Username  string
-	Cookie    string
-	Cancelled bool
+	Username    string
+	Cookie      string
+	CookieStore string
+	Passphrase  string
+	Cancelled   bool
 }
 
 func RunOnboarding(opts OnboardingOptions) (OnboardingResult, error) {
@@ -42,10 +55,28 @@ type onboardingStage int
 const (
 	stageSplash onboardingStage = iota
 	stageUsername
+	stageStorage
 	stageCookie
 	stageDone
 )
 
+type storageChoice struct {
+	store  string
+	label  string
+	detail string
+}
+
+func storageChoices(keyring bool) []storageChoice {
+	var choices []storageChoice
+	if keyring {
+		choices = append(choices, storageChoice{secrets.BackendKeyring, "System keyring", "Unlocked with your login."})
+	}
+	return append(choices,
+		storageChoice{secrets.BackendFile, "Encrypted file", "Asks for a passphrase at startup, or set LETTERBOXD_PASSPHRASE."},
+		storageChoice{secrets.BackendConfig, "Config file", "Plain text, readable by anything running as you."},
+	)
+}
+
 type onboardingModel struct {
 	stage      onboardingStage
 	steps      []onboardingStage
@@ -60,6 +91,16 @@ type onboardingModel struct {
 	cookieHelp bool
 	cookieWarn string
 	result     OnboardingResult
+	// storageIdx is the highlighted choice; passStep is 1 while the passphrase
+	// is typed and 2 while it is confirmed.
+	storage      []storageChoice
+	storageIdx   int
+	passStep     int
+	passphrase   textinput.Model
+	confirm      textinput.Model
+	cookieStore  string
+	secretsPath  string
+	secretsExist bool
 }
 
 type onboardingStyles struct {
@@ -77,31 +118,57 @@ type onboardingStyles struct {
 	footer     lipgloss.Style
 }
 
-func newOnboardingStyles() onboardingStyles {
-	bg := lipgloss.Color("#0E1114")
-	fg := lipgloss.Color("#E6F0F2")
-	accent := lipgloss.Color("#00E054")
-	orange := lipgloss.Color("#FF8C3A")
-	panelBG := lipgloss.Color("#14181C")
-	panelBorder := lipgloss.Color("#3A4A55")
-	return onboardingStyles{
-		bg:         lipgloss.NewStyle().Background(bg).Foreground(fg),
-		panel:      lipgloss.NewStyle().Background(panelBG).Foreground(fg).Padding(1, 2).Border(lipgloss.RoundedBorder()).BorderForeground(panelBorder),
+func newOnboardingStyles(def themeDef) onboardingStyles {
+	c := def.colors
+	fg := themeColor(c.Text)
+	accent := themeColor(c.Accent)
+	subtle := themeColor(c.Subtle)
+	styles := onboardingStyles{
+		bg:         lipgloss.NewStyle().Background(themeColor(c.Background)).Foreground(fg),
+		panel:      lipgloss.NewStyle().Background(themeColor(c.Panel)).Foreground(fg).Padding(1, 2).Border(lipgloss.RoundedBorder()).BorderForeground(themeColor(c.PanelBorder)),
 		title:      lipgloss.NewStyle().Foreground(accent).Bold(true),
-		subtitle:   lipgloss.NewStyle().Foreground(lipgloss.Color("#9BB0B8")),
+		subtitle:   lipgloss.NewStyle().Foreground(subtle),
 		accent:     lipgloss.NewStyle().Foreground(accent).Bold(true),
-		dim:        lipgloss.NewStyle().Foreground(lipgloss.Color("#7F8D96")),
-		step:       lipgloss.NewStyle().Foreground(lipgloss.Color("#9BB0B8")).Bold(true),
+		dim:        lipgloss.NewStyle().Foreground(themeColor(c.Dim)),
+		step:       lipgloss.NewStyle().Foreground(subtle).Bold(true),
 		label:      lipgloss.NewStyle().Foreground(fg).Bold(true),
 		input:      lipgloss.NewStyle().Foreground(fg),
-		inputFocus: lipgloss.NewStyle().Foreground(fg).Background(lipgloss.Color("#1F2A33")),
-		warning:    lipgloss.NewStyle().Foreground(orange).Bold(true),
-		footer:     lipgloss.NewStyle().Foreground(lipgloss.Color("#9BB0B8")),
+		inputFocus: lipgloss.NewStyle().Foreground(themeColor(c.SelectionText)).Background(themeColor(c.Selection)),
+		warning:    lipgloss.NewStyle().Foreground(themeColor(c.Warning)).Bold(true),
+		footer:     lipgloss.NewStyle().Foreground(subtle),
+	}
+	if c.Selection == "" {
+		styles.inputFocus = styles.inputFocus.Underline(true)
+	}
+	named := styles.named()
+	for name, spec := range def.styles {
+		if style, ok := named[strings.TrimPrefix(name, "onboarding.")]; ok && strings.HasPrefix(name, "onboarding.") {
+			*style = spec.apply(*style)
+		}
+	}
+	return styles
+}
+
+// Keys are the "onboarding.*" style keys without their prefix.
+func (s *onboardingStyles) named() map[string]*lipgloss.Style {
+	return map[string]*lipgloss.Style{
+		"bg":          &s.bg,
+		"panel":       &s.panel,
+		"title":       &s.title,
+		"subtitle":    &s.subtitle,
+		"accent":      &s.accent,
+		"dim":         &s.dim,
+		"step":        &s.step,
+		"label":       &s.label,
+		"input":       &s.input,
+		"input_focus": &s.inputFocus,
+		"warning":     &s.warning,
+		"footer":      &s.footer,
 	}
 }
 
 func newOnboardingModel(opts OnboardingOptions) onboardingModel {
-	styles := newOnboardingStyles()
+	styles := newOnboardingStyles(opts.Themes.selected())
 	spin := spinner.New(spinner.WithSpinner(spinner.Line))
 	spin.Style = styles.accent
 
@@ -117,10 +184,21 @@ func newOnboardingModel(opts OnboardingOptions) onboardingModel {
 	cookieInput.EchoCharacter = '*'
 	cookieInput.SetValue(strings.TrimSpace(opts.Cookie))
 
-	steps := make([]onboardingStage, 0, 2)
+	newSecret := func() textinput.Model {
+		input := textinput.New()
+		input.CharLimit = 0
+		input.EchoMode = textinput.EchoPassword
+		input.EchoCharacter = '*'
+		return input
+	}
+
+	steps := make([]onboardingStage, 0, 3)
 	if opts.NeedUser {
 		steps = append(steps, stageUsername)
 	}
+	if opts.NeedStorage {
+		steps = append(steps, stageStorage)
+	}
 	if opts.NeedCookie {
 		steps = append(steps, stageCookie)
 	}
@@ -133,6 +211,18 @@ func newOnboardingModel(opts OnboardingOptions) onboardingModel {
 		username:   userInput,
 		cookie:     cookieInput,
 		configPath: strings.TrimSpace(opts.ConfigPath),
+
+		storage:      storageChoices(opts.KeyringAvailable),
+		passphrase:   newSecret(),
+		confirm:      newSecret(),
+		cookieStore:  opts.CookieStore,
+		secretsPath:  strings.TrimSpace(opts.SecretsPath),
+		secretsExist: opts.SecretsExist,
+	}
+	for i, choice := range model.storage {
+		if choice.store == opts.CookieStore {
+			model.storageIdx = i
+		}
 	}
 	if strings.TrimSpace(opts.Cookie) != "" && !cookieHasCSRF(opts.Cookie) {
 		model.cookieWarn = "Cookie looks incomplete; include com.xk72.webparts.csrf=..."
@@ -152,13 +242,15 @@ func (m onboardingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		inputWidth := max(20, min(56, m.width-12))
 		m.username.Width = inputWidth
 		m.cookie.Width = inputWidth
+		m.passphrase.Width = inputWidth
+		m.confirm.Width = inputWidth
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
+		// q is left to the inputs, so it can be typed.
+		if msg.String() == "ctrl+c" || msg.String() == "q" && !m.typing() {
 			m.result.Cancelled = true
 			return m, tea.Quit
 		}
@@ -169,6 +261,8 @@ func (m onboardingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateSplash(msg)
 	case stageUsername:
 		return m.updateUsername(msg)
+	case stageStorage:
+		return m.updateStorage(msg)
 	case stageCookie:
 		return m.updateCookie(msg)
 	case stageDone:
@@ -185,6 +279,8 @@ func (m onboardingModel) View() string {
 		return bg.Render(m.renderSplash())
 	case stageUsername:
 		return bg.Render(m.renderUsername())
+	case stageStorage:
+		return bg.Render(m.renderStorage())
 	case stageCookie:
 		return bg.Render(m.renderCookie())
 	case stageDone:
@@ -230,6 +326,83 @@ func (m onboardingModel) updateUsername(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m onboardingModel) updateStorage(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.passStep > 0 {
+		return m.updatePassphrase(msg)
+	}
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "up", "k":
+			m.storageIdx = max(0, m.storageIdx-1)
+		case "down", "j":
+			m.storageIdx = min(len(m.storage)-1, m.storageIdx+1)
+		case "enter":
+			choice := m.storage[m.storageIdx]
+			if choice.store == secrets.BackendFile {
+				m.errorMsg = ""
+				m.passStep = 1
+				m.passphrase.Focus()
+				return m, textinput.Blink
+			}
+			m.result.CookieStore = choice.store
+			m.result.Passphrase = ""
+			m.cookieStore = choice.store
+			return m.advance(), nil
+		case "esc", "shift+tab":
+			if m.hasStep(stageUsername) {
+				m.setStage(stageUsername)
+			} else {
+				m.setStage(stageSplash)
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m onboardingModel) updatePassphrase(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "enter":
+			value := m.passphrase.Value()
+			if value == "" {
+				m.errorMsg = "Please enter a passphrase."
+				return m, nil
+			}
+			if !m.secretsExist && m.passStep == 1 {
+				m.errorMsg = ""
+				m.passStep = 2
+				m.passphrase.Blur()
+				m.confirm.Focus()
+				return m, textinput.Blink
+			}
+			if !m.secretsExist && m.confirm.Value() != value {
+				m.errorMsg = "Passphrases don't match."
+				m.confirm.Reset()
+				return m, nil
+			}
+			m.result.CookieStore = secrets.BackendFile
+			m.result.Passphrase = value
+			m.cookieStore = secrets.BackendFile
+			return m.advance(), nil
+		case "esc":
+			m.passStep = 0
+			m.errorMsg = ""
+			m.passphrase.Reset()
+			m.confirm.Reset()
+			m.passphrase.Blur()
+			m.confirm.Blur()
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	if m.passStep == 2 {
+		m.confirm, cmd = m.confirm.Update(msg)
+	} else {
+		m.passphrase, cmd = m.passphrase.Update(msg)
+	}
+	return m, cmd
+}
+
 func (m onboardingModel) updateCookie(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if key, ok := msg.(tea.KeyMsg); ok {
 		switch key.String() {
@@ -249,6 +422,10 @@ func (m onboardingModel) updateCookie(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.result.Cookie = ""
 			return m.advance(), nil
 		case "shift+tab", "up":
+			if m.hasStep(stageStorage) {
+				m.setStage(stageStorage)
+				return m, nil
+			}
 			if m.hasStep(stageUsername) {
 				m.setStage(stageUsername)
 				return m, nil
@@ -299,6 +476,9 @@ func (m *onboardingModel) setStage(stage onboardingStage) {
 	m.errorMsg = ""
 	m.username.Blur()
 	m.cookie.Blur()
+	m.passphrase.Blur()
+	m.confirm.Blur()
+	m.passStep = 0
 	switch stage {
 	case stageUsername:
 		m.username.Focus()
@@ -368,9 +548,8 @@ func (m onboardingModel) renderCookie() string {
 		input = styles.inputFocus.Render(line)
 	}
 	helper := styles.dim.Render("Paste the full cookie string from your browser.")
-	if m.configPath != "" {
-		path := truncate(m.configPath, max(20, m.width-12))
-		helper = lipgloss.JoinVertical(lipgloss.Left, helper, styles.dim.Render("Saved to: "+path))
+	if saved := m.savedTo(); saved != "" {
+		helper = lipgloss.JoinVertical(lipgloss.Left, helper, styles.dim.Render("Saved to: "+saved))
 	}
 	if m.cookieWarn != "" {
 		helper = lipgloss.JoinVertical(lipgloss.Left, helper, styles.warning.Render(m.cookieWarn))
@@ -380,6 +559,67 @@ func (m onboardingModel) renderCookie() string {
 	return m.renderPanel(step, title, sub, input, helper, footer)
 }
 
+func (m onboardingModel) renderStorage() string {
+	styles := m.styles
+	step := styles.step.Render(m.stepLabel())
+	title := styles.title.Render("Where should your cookie be kept?")
+	sub := styles.subtitle.Render("It signs in as you, so keep it out of plain text if you can.")
+	if m.passStep > 0 {
+		lines := []string{step, title}
+		if m.secretsExist {
+			lines = append(lines, styles.subtitle.Render("Enter the passphrase of your encrypted cookie file."))
+		} else {
+			lines = append(lines, styles.subtitle.Render("Choose a passphrase for the encrypted cookie file."))
+		}
+		lines = append(lines, m.secretLine("Passphrase", m.passphrase))
+		if m.passStep == 2 {
+			lines = append(lines, m.secretLine("Confirm   ", m.confirm))
+		}
+		if m.secretsPath != "" {
+			lines = append(lines, styles.dim.Render("Saved to: "+truncate(m.secretsPath, max(20, m.width-12))))
+		}
+		lines = append(lines, styles.footer.Render("enter continue • esc back"))
+		return m.renderPanel(lines...)
+	}
+	var choices []string
+	for i, choice := range m.storage {
+		line := "  " + choice.label
+		if i == m.storageIdx {
+			line = styles.accent.Render("› " + choice.label)
+		}
+		choices = append(choices, line, styles.dim.Render("    "+choice.detail))
+	}
+	footer := styles.footer.Render("↑/↓ choose • enter continue • esc back")
+	return m.renderPanel(step, title, sub, "", strings.Join(choices, "\n"), "", footer)
+}
+
+func (m onboardingModel) secretLine(label string, input textinput.Model) string {
+	line := fmt.Sprintf("%s %s", m.styles.label.Render(label), input.View())
+	if input.Focused() {
+		return m.styles.inputFocus.Render(line)
+	}
+	return m.styles.input.Render(line)
+}
+
+func (m onboardingModel) savedTo() string {
+	switch m.cookieStore {
+	case secrets.BackendKeyring:
+		return "system keyring"
+	case secrets.BackendFile:
+		if m.secretsPath != "" {
+			return truncate(m.secretsPath, max(20, m.width-24)) + " (encrypted)"
+		}
+	}
+	if m.configPath != "" {
+		return truncate(m.configPath, max(20, m.width-12))
+	}
+	return ""
+}
+
+func (m onboardingModel) typing() bool {
+	return m.stage == stageUsername || m.stage == stageCookie || m.stage == stageStorage && m.passStep > 0
+}
+
 func (m onboardingModel) renderDone() string {
 	styles := m.styles
 	title := styles.title.Render("You're set.")
@@ -398,13 +638,14 @@ func (m onboardingModel) renderCookieHelp() string {
 		"3) Open the Network tab and refresh the page.",
 		"4) Click any request to letterboxd.com, then copy the Cookie request header.",
 		"5) Paste it here. Keep it private.",
+		"",
+		"Or skip this and run: letterboxd auth import -browser firefox (or chromium)",
 	}
 	important := styles.warning.Render("Make sure the cookie includes com.xk72.webparts.csrf=...")
 	footer := styles.footer.Render("esc back • ? back")
 	body := strings.Join(lines, "\n")
-	if m.configPath != "" {
-		path := truncate(m.configPath, max(20, m.width-12))
-		body = body + "\n\nSaved to: " + path
+	if saved := m.savedTo(); saved != "" {
+		body = body + "\n\nSaved to: " + saved
 	}
 	return m.renderPanel(title, styles.dim.Render(body), important, footer)
 }
@@ -422,8 +663,7 @@ func (m onboardingModel) renderPanel(lines ...string) string {
 func (m onboardingModel) place(content string) string {
 	width := max(0, m.width)
 	height := max(0, m.height)
-	bgColor := lipgloss.Color("#0E1114")
-	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, content, lipgloss.WithWhitespaceChars(" "), lipgloss.WithWhitespaceBackground(bgColor))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, content, lipgloss.WithWhitespaceChars(" "), lipgloss.WithWhitespaceBackground(m.styles.bg.GetBackground()))
 }
 
 func (m onboardingModel) stepLabel() string {
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/secrets"
+)
+
+func onboardingKey(m onboardingModel, keys ...string) onboardingModel {
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(onboardingModel)
+	}
+	return m
+}
+
+func TestOnboardingStorageAsksForPassphraseTwice(t *testing.T) {
+	m := newOnboardingModel(OnboardingOptions{NeedStorage: true, KeyringAvailable: true, CookieStore: secrets.BackendFile})
+	m = onboardingKey(m, "enter")
+	if m.stage != stageStorage || m.storage[m.storageIdx].store != secrets.BackendFile {
+		t.Fatalf("expected the storage step with the current choice highlighted, got %v %d", m.stage, m.storageIdx)
+	}
+	// q is part of the passphrase, not a way out.
+	m = onboardingKey(m, "enter", "q", "1", "enter", "x", "enter")
+	if m.result.Cancelled || m.errorMsg == "" || m.stage != stageStorage {
+		t.Fatalf("expected mismatched passphrases to be refused, got %+v %q", m.result, m.errorMsg)
+	}
+	m = onboardingKey(m, "q", "1", "enter")
+	if m.stage != stageDone || m.result.CookieStore != secrets.BackendFile || m.result.Passphrase != "q1" {
+		t.Fatalf("expected the encrypted file with its passphrase, got %v %+v", m.stage, m.result)
+	}
+}
+
+func TestOnboardingStorageWithoutKeyring(t *testing.T) {
+	m := newOnboardingModel(OnboardingOptions{NeedStorage: true, NeedCookie: true})
+	m = onboardingKey(m, "enter", "down", "enter")
+	if m.result.CookieStore != secrets.BackendConfig || m.stage != stageCookie {
+		t.Fatalf("expected plain config then the cookie step, got %v %+v", m.stage, m.result)
+	}
+	if m.savedTo() != "" {
+		t.Fatalf("expected no location without a config path, got %q", m.savedTo())
+	}
+}
@@ -0,0 +1,387 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const maxJumpTargets = 10
+
+// keyCommand is a registry entry for an action with a key binding. Update
+// runs the first entry whose key matches and whose when holds, and the
+// palette offers the same entries, so each action and the state it needs
+// are defined once.
+type keyCommand struct {
+	title   string
+	binding func(keyMap) key.Binding
+	when    func(Model) bool
+	run     func(Model) (tea.Model, tea.Cmd)
+}
+
+// keyCommands is the central list of bound actions. Entries sharing a key
+// are tried in order.
+var keyCommands = []keyCommand{
+	{"Log entry", func(k keyMap) key.Binding { return k.Log }, func(m Model) bool {
+		return m.activeTab == tabFilm && m.hasCookie()
+	}, func(m Model) (tea.Model, tea.Cmd) {
+		m = m.startLogModal()
+		return m, m.logSpinner.Tick
+	}},
+	{"Add to watchlist", func(k keyMap) key.Binding { return k.WatchlistAdd }, func(m Model) bool {
+		inWatchlist, ok := m.watchlistState()
+		return m.activeTab == tabFilm && m.hasCookie() && !m.watchlistPending && !(ok && inWatchlist)
+	}, func(m Model) (tea.Model, tea.Cmd) { return m.setWatchlist(true) }},
+	{"Remove from watchlist", func(k keyMap) key.Binding { return k.WatchlistRemove }, func(m Model) bool {
+		inWatchlist, ok := m.watchlistState()
+		return m.activeTab == tabFilm && m.hasCookie() && !m.watchlistPending && ok && inWatchlist
+	}, func(m Model) (tea.Model, tea.Cmd) { return m.setWatchlist(false) }},
+	{"Reroll roulette", func(k keyMap) key.Binding { return k.Roulette }, func(m Model) bool {
+		return m.activeTab == tabFilm && m.rouletteActive && !m.roulettePending
+	}, func(m Model) (tea.Model, tea.Cmd) { return m.startRoulette(m.rouletteOpts) }},
+	{"Open in browser", func(k keyMap) key.Binding { return k.Open }, func(m Model) bool {
+		return m.browserURL() != ""
+	}, func(m Model) (tea.Model, tea.Cmd) { return m, openBrowserCmd(m.browserURL()) }},
+	{"Filter", func(k keyMap) key.Binding { return k.Filter }, func(m Model) bool {
+		return !m.modalOpen() && (m.activeTab == tabFilms || m.activeTab == tabWatchlist)
+	}, func(m Model) (tea.Model, tea.Cmd) { return m.openFilterModal(), nil }},
+	{"Follow member", func(k keyMap) key.Binding { return k.Follow }, func(m Model) bool {
+		username, followed, known := m.followTarget()
+		return username != "" && m.hasCookie() && !m.followPending && !(known && followed)
+	}, func(m Model) (tea.Model, tea.Cmd) { return m.setFollowing(true) }},
+	{"Unfollow member", func(k keyMap) key.Binding { return k.Unfollow }, func(m Model) bool {
+		username, followed, known := m.followTarget()
+		return username != "" && m.hasCookie() && !m.followPending && known && followed
+	}, func(m Model) (tea.Model, tea.Cmd) { return m.setFollowing(false) }},
+	{"Compare with member", func(k keyMap) key.Binding { return k.Compare }, func(m Model) bool {
+		return m.profileModal && m.modalUser != "" && m.modalUser != m.username
+	}, func(m Model) (tea.Model, tea.Cmd) {
+		m = m.openCompare(m.username, m.modalUser)
+		return m, fetchCompareCmd(m.client, m.compareA, m.compareB)
+	}},
+	{"Watchlist roulette", func(k keyMap) key.Binding { return k.Roulette }, func(m Model) bool {
+		return !m.modalOpen() && m.activeTab == tabWatchlist && !m.roulettePending
+	}, func(m Model) (tea.Model, tea.Cmd) { return m.showFilterForm(newRouletteForm(m.rouletteOpts)), nil }},
+	{"Jump diary to date", func(k keyMap) key.Binding { return k.DiaryJump }, func(m Model) bool {
+		return !m.modalOpen() && m.activeTab == tabDiary && !m.diaryHeatmap
+	}, func(m Model) (tea.Model, tea.Cmd) { return m.openFilterModal(), nil }},
+	{"Toggle calendar", func(k keyMap) key.Binding { return k.Calendar }, func(m Model) bool {
+		return !m.modalOpen() && m.activeTab == tabDiary
+	}, func(m Model) (tea.Model, tea.Cmd) { return m, m.toggleHeatmap() }},
+	{"Year in review", func(k keyMap) key.Binding { return k.YearReview }, func(m Model) bool {
+		return !m.modalOpen() && m.activeTab == tabStats && m.allDiaryLoaded
+	}, func(m Model) (tea.Model, tea.Cmd) {
+		m.toggleStatsReview()
+		return m, m.statsEnrichCmd()
+	}},
+	{"Previous year", func(k keyMap) key.Binding { return k.PrevYear }, func(m Model) bool {
+		return !m.modalOpen() && m.activeTab == tabStats && m.allDiaryLoaded
+	}, func(m Model) (tea.Model, tea.Cmd) {
+		m.cycleStatsYear(-1)
+		return m, m.statsEnrichCmd()
+	}},
+	{"Next year", func(k keyMap) key.Binding { return k.NextYear }, func(m Model) bool {
+		return !m.modalOpen() && m.activeTab == tabStats && m.allDiaryLoaded
+	}, func(m Model) (tea.Model, tea.Cmd) {
+		m.cycleStatsYear(1)
+		return m, m.statsEnrichCmd()
+	}},
+	{"Filter list", func(k keyMap) key.Binding { return k.Find }, func(m Model) bool {
+		return !m.modalOpen() && findableTab(m)
+	}, func(m Model) (tea.Model, tea.Cmd) { return m.startFind() }},
+	{"Export…", func(k keyMap) key.Binding { return k.Export }, Model.canExport, func(m Model) (tea.Model, tea.Cmd) {
+		return m.openExport(), nil
+	}},
+	{"Pending changes", func(k keyMap) key.Binding { return k.Queue }, func(m Model) bool {
+		return m.queue != nil && !m.modalOpen()
+	}, func(m Model) (tea.Model, tea.Cmd) { return m.openQueue(), nil }},
+	{"Retry pending changes", func(k keyMap) key.Binding { return k.Refresh }, func(m Model) bool {
+		return m.activeTab == tabQueue && !m.modalOpen() && m.pendingOps() > 0
+	}, func(m Model) (tea.Model, tea.Cmd) { return m.retryQueue() }},
+	{"Refresh", func(k keyMap) key.Binding { return k.Refresh }, func(m Model) bool {
+		return m.activeTab != tabQueue
+	}, func(m Model) (tea.Model, tea.Cmd) { return m.refreshAll() }},
+	{"Search films", func(k keyMap) key.Binding { return k.SearchTab }, func(Model) bool { return true }, func(m Model) (tea.Model, tea.Cmd) {
+		return m.goToSearch(), nil
+	}},
+	{"Toggle help", func(k keyMap) key.Binding { return k.Help }, func(Model) bool { return true }, func(m Model) (tea.Model, tea.Cmd) {
+		m.help.ShowAll = !m.help.ShowAll
+		m.resizeViewport()
+		return m, nil
+	}},
+	{"Next theme", func(k keyMap) key.Binding { return k.Theme }, func(Model) bool { return true }, func(m Model) (tea.Model, tea.Cmd) {
+		m.cycleTheme()
+		return m, nil
+	}},
+	{"Switch account", func(k keyMap) key.Binding { return k.Accounts }, Model.canSwitchAccount, func(m Model) (tea.Model, tea.Cmd) {
+		return m.openAccounts()
+	}},
+	{"Update cookie", func(k keyMap) key.Binding { return k.Cookie }, func(m Model) bool {
+		return m.client != nil && !m.modalOpen()
+	}, func(m Model) (tea.Model, tea.Cmd) {
+		hint := ""
+		if m.sessionErr != nil {
+			hint = sessionProblem(m.sessionErr)
+		}
+		m.promptCookieUpdate(hint)
+		return m, nil
+	}},
+	{"Debug panel", func(k keyMap) key.Binding { return k.Debug }, func(m Model) bool {
+		return !m.modalOpen()
+	}, Model.openDebugPanel},
+	{"Toggle preview", func(k keyMap) key.Binding { return k.Preview }, func(m Model) bool {
+		return !m.modalOpen() && previewTab(m)
+	}, func(m Model) (tea.Model, tea.Cmd) {
+		m.toggleSplit()
+		return m, nil
+	}},
+	{"Quit", func(k keyMap) key.Binding { return k.Quit }, func(m Model) bool { return !m.modalOpen() }, func(m Model) (tea.Model, tea.Cmd) {
+		return m, tea.Quit
+	}},
+}
+
+// runKeyCommand runs the registry action bound to msg, if one is enabled.
+func (m Model) runKeyCommand(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	for _, command := range keyCommands {
+		if key.Matches(msg, command.binding(m.keys)) && command.when(m) {
+			model, cmd := command.run(m)
+			return model, cmd, true
+		}
+	}
+	return m, nil, false
+}
+
+// paletteCommand is one row of the command palette.
+type paletteCommand struct {
+	title string
+	hint  string
+	run   func(Model) (tea.Model, tea.Cmd)
+}
+
+// jumpTarget is a recently viewed film or profile the palette can go back to.
+type jumpTarget struct {
+	title    string
+	filmURL  string
+	username string
+}
+
+func (m *Model) rememberTarget(target jumpTarget) {
+	targets := []jumpTarget{target}
+	for _, existing := range m.jumpTargets {
+		if existing.filmURL != target.filmURL || existing.username != target.username {
+			targets = append(targets, existing)
+		}
+	}
+	m.jumpTargets = targets[:min(len(targets), maxJumpTargets)]
+}
+
+// paletteCommands lists what the palette offers in the current context:
+// bound actions first, then sort modes, tabs, themes, accounts and recently
+// viewed items.
+func (m Model) paletteCommands() []paletteCommand {
+	m.paletteModal = false
+	var commands []paletteCommand
+	for _, command := range keyCommands {
+		if !command.when(m) {
+			continue
+		}
+		binding := command.binding(m.keys)
+		if len(binding.Keys()) == 0 {
+			continue
+		}
+		commands = append(commands, paletteCommand{
+			title: command.title,
+			hint:  binding.Help().Key,
+			run:   command.run,
+		})
+	}
+	if !m.modalOpen() {
+		commands = append(commands, m.sortCommands()...)
+		for _, item := range visibleTabItems(m) {
+			if item.id != m.activeTab {
+				commands = append(commands, paletteCommand{title: "Go to " + item.label, run: goToTab(item.id)})
+			}
+		}
+	}
+	for i, name := range m.themes.names() {
+		if i != m.themes.current {
+			commands = append(commands, paletteCommand{title: "Theme: " + name, run: useTheme(i)})
+		}
+	}
+	commands = append(commands, m.accountCommands()...)
+	for _, target := range m.jumpTargets {
+		commands = append(commands, jumpCommand(target))
+	}
+	return commands
+}
+
+func (m Model) sortCommands() []paletteCommand {
+	var commands []paletteCommand
+	switch m.activeTab {
+	case tabDiary:
+		for s := diarySortRecent; s < diarySortCount; s++ {
+			probe := m
+			probe.diarySort = s
+			commands = append(commands, paletteCommand{title: "Sort diary by " + probe.diarySortLabel(), run: func(m Model) (tea.Model, tea.Cmd) {
+				m.diarySort = s
+				return m.resortList()
+			}})
+		}
+	case tabWatchlist:
+		for s := watchlistSortAdded; s < watchlistSortCount; s++ {
+			probe := m
+			probe.watchlistSort = s
+			commands = append(commands, paletteCommand{title: "Sort watchlist by " + probe.watchlistSortLabel(), run: func(m Model) (tea.Model, tea.Cmd) {
+				m.watchlistSort = s
+				return m.resortList()
+			}})
+		}
+	case tabFilms:
+		for s := filmsSortWatched; s < filmsSortCount; s++ {
+			probe := m
+			probe.filmsSort = s
+			commands = append(commands, paletteCommand{title: "Sort films by " + probe.filmsSortLabel(), run: func(m Model) (tea.Model, tea.Cmd) {
+				m.filmsSort = s
+				return m.resortList()
+			}})
+		}
+	}
+	return commands
+}
+
+func goToTab(t tab) func(Model) (tea.Model, tea.Cmd) {
+	return func(m Model) (tea.Model, tea.Cmd) {
+		m.activeTab = t
+		m.resetTabPosition()
+		return m, m.maybeFillCmd()
+	}
+}
+
+func useTheme(index int) func(Model) (tea.Model, tea.Cmd) {
+	return func(m Model) (tea.Model, tea.Cmd) {
+		m.setTheme(index)
+		return m, nil
+	}
+}
+
+func jumpCommand(target jumpTarget) paletteCommand {
+	if target.username != "" {
+		return paletteCommand{title: "Profile: " + target.title, run: func(m Model) (tea.Model, tea.Cmd) {
+			if m.activeTab == tabFilm {
+				m.activeTab = m.filmBase()
+				m.resetTabPosition()
+			}
+			m = m.openProfileModal(target.username)
+			return m, fetchProfileModalCmd(m.client, target.username)
+		}}
+	}
+	return paletteCommand{title: "Film: " + target.title, run: func(m Model) (tea.Model, tea.Cmd) {
+		m.profileModal = false
+		m = m.openFilm(target.filmURL)
+		return m, fetchFilmCmd(m.client, target.filmURL, m.username)
+	}}
+}
+
+func newPaletteInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = ": "
+	input.Placeholder = "type a command"
+	input.CharLimit = 80
+	return input
+}
+
+func (m Model) openPalette() (Model, tea.Cmd) {
+	m.paletteModal = true
+	m.paletteList.selected = 0
+	m.paletteInput.Reset()
+	m.paletteInput.Width = max(10, min(60, m.width-16))
+	return m, m.paletteInput.Focus()
+}
+
+func (m Model) closePalette() Model {
+	m.paletteModal = false
+	m.paletteInput.Blur()
+	return m
+}
+
+// paletteMatches filters the palette commands by the typed query.
+func (m Model) paletteMatches() []paletteCommand {
+	query := strings.TrimSpace(m.paletteInput.Value())
+	commands := m.paletteCommands()
+	if query == "" {
+		return commands
+	}
+	var matches []paletteCommand
+	for _, command := range commands {
+		if _, ok := fuzzyMatch(command.title, query); ok {
+			matches = append(matches, command)
+		}
+	}
+	return matches
+}
+
+func (m Model) updatePalette(msg tea.Msg) (tea.Model, tea.Cmd) {
+	typed, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	matches := m.paletteMatches()
+	switch {
+	case key.Matches(typed, m.keys.QuitAll):
+		return m, tea.Quit
+	case key.Matches(typed, m.keys.Cancel):
+		return m.closePalette(), nil
+	case key.Matches(typed, m.keys.Select):
+		if len(matches) == 0 {
+			return m, nil
+		}
+		command := matches[clamp(m.paletteList.selected, 0, len(matches)-1)]
+		return command.run(m.closePalette())
+	case typed.Type == tea.KeyDown || typed.Type == tea.KeyCtrlN:
+		m.paletteList.selected = clamp(m.paletteList.selected+1, 0, max(0, len(matches)-1))
+		return m, nil
+	case typed.Type == tea.KeyUp || typed.Type == tea.KeyCtrlP:
+		m.paletteList.selected = clamp(m.paletteList.selected-1, 0, max(0, len(matches)-1))
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.paletteInput, cmd = m.paletteInput.Update(msg)
+	m.paletteList.selected = 0
+	return m, cmd
+}
+
+func renderPalettePanel(m Model, theme themeStyles) string {
+	width := max(40, min(72, m.width-4))
+	innerWidth := width - 4
+	_, height := modalDimensions(m.width, m.height)
+	listHeight := max(1, height-6)
+
+	matches := m.paletteMatches()
+	query := strings.TrimSpace(m.paletteInput.Value())
+	rows := []string{m.paletteInput.View(), ""}
+	if len(matches) == 0 {
+		rows = append(rows, theme.dim.Render("No matching commands."))
+	}
+	selected := clamp(m.paletteList.selected, 0, max(0, len(matches)-1))
+	start := clamp(selected-listHeight+1, 0, max(0, len(matches)-listHeight))
+	for i := start; i < min(len(matches), start+listHeight); i++ {
+		command := matches[i]
+		hits, _ := fuzzyMatch(command.title, query)
+		title := markMatches(command.title, hits, 0, lipgloss.NewStyle(), theme.match)
+		gap := max(1, innerWidth-4-lipgloss.Width(command.title)-lipgloss.Width(command.hint))
+		line := title + strings.Repeat(" ", gap) + theme.dim.Render(command.hint)
+		rows = append(rows, renderSelectableLine(line, i == selected, innerWidth, theme))
+	}
+	if len(matches) > listHeight {
+		rows = append(rows, theme.dim.Render(fmt.Sprintf("%d of %d", selected+1, len(matches))))
+	}
+
+	panel := theme.panel.
+		Width(width).
+		Padding(1, 2)
+	return panel.Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+func paletteTitles(m Model) []string {
+	var titles []string
+	for _, command := range m.paletteMatches() {
+		titles = append(titles, command.title+" "+command.hint)
+	}
+	return titles
+}
+
+func TestPaletteRunsSortModeFromQuery(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.activeTab = tabDiary
+	m.lastTab = tabDiary
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	m = updated.(Model)
+	if !m.paletteModal {
+		t.Fatalf("expected : to open the palette")
+	}
+	m = typeKeys(m, "sort rating")
+	titles := paletteTitles(m)
+	if len(titles) != 1 || titles[0] != "Sort diary by Rating " {
+		t.Fatalf("unexpected matches: %q", titles)
+	}
+	m.width, m.height = 100, 30
+	if view := stripANSI(m.View()); !strings.Contains(view, "Sort diary by Rating") {
+		t.Fatalf("expected palette in view: %q", view)
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.paletteModal || m.diarySort != diarySortRating || cmd == nil {
+		t.Fatalf("expected sort to apply, got open=%v sort=%v", m.paletteModal, m.diarySort)
+	}
+}
+
+func TestPaletteListsContextCommandsWithKeyHints(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.activeTab = tabDiary
+	titles := strings.Join(paletteTitles(m), "\n")
+	for _, want := range []string{"Jump diary to date J", "Toggle calendar v", "Filter list /", "Refresh r", "Go to Watchlist"} {
+		if !strings.Contains(titles, want) {
+			t.Fatalf("expected %q in palette:\n%s", want, titles)
+		}
+	}
+	if strings.Contains(titles, "Log entry") || strings.Contains(titles, "Sort films") {
+		t.Fatalf("expected commands from other contexts to be hidden:\n%s", titles)
+	}
+}
+
+func TestPaletteRunsBoundAction(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.activeTab = tabDiary
+	m.lastTab = tabDiary
+	m.diary = []letterboxd.DiaryEntry{{Title: "Heat"}}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = updated.(Model)
+	m = typeKeys(m, "filter list")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.paletteModal || !m.findTyping {
+		t.Fatalf("expected the list filter to open, got palette=%v typing=%v", m.paletteModal, m.findTyping)
+	}
+}
+
+func TestKeyAndPaletteShareCommandConditions(t *testing.T) {
+	m := NewModel("jane", &letterboxd.Client{Cookie: "session"})
+	m.activeTab = tabFilm
+	m.film = letterboxd.Film{Title: "Heat", URL: letterboxd.BaseURL + "/film/heat/"}
+	m.watchlistPending = true
+
+	for _, command := range m.paletteCommands() {
+		if command.title == "Add to watchlist" {
+			t.Fatalf("expected no watchlist command while a change is pending")
+		}
+	}
+	updated, cmd := m.Update(keyMsgFor("w"))
+	if cmd != nil || updated.(Model).watchlistStatus != "" {
+		t.Fatalf("expected the watchlist key to do nothing while a change is pending")
+	}
+}
+
+func TestPaletteJumpsToRecentFilm(t *testing.T) {
+	m := NewModel("jane", nil)
+	updated, _ := m.Update(filmMsg{film: letterboxd.Film{Title: "Heat", Year: "1995", URL: "https://letterboxd.com/film/heat/"}})
+	m = updated.(Model)
+	m = m.openProfileModal("bob")
+	m.profileModal = false
+
+	titles := strings.Join(paletteTitles(m), "\n")
+	if !strings.Contains(titles, "Film: Heat (1995)") || !strings.Contains(titles, "Profile: @bob") {
+		t.Fatalf("expected jump targets in palette:\n%s", titles)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	m = updated.(Model)
+	m = typeKeys(m, "heat")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.activeTab != tabFilm || m.film.URL != "https://letterboxd.com/film/heat/" || cmd == nil {
+		t.Fatalf("expected film to open, got %v %q", m.activeTab, m.film.URL)
+	}
+}
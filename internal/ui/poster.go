@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"bytes"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+	"github.com/solean/letterboxd-tui/internal/store"
+)
+
+const (
+	// posterMinWidth is the narrowest film view content that still gets a
+	// poster beside the text.
+	posterMinWidth = 72
+	posterGap      = 2
+)
+
+type posterState struct {
+	url  string
+	img  image.Image
+	art  string
+	cols int
+	rows int
+}
+
+type posterMsg struct {
+	url string
+	img image.Image
+	err error
+}
+
+// WithPosters turns on posters in the film view, drawn with g and cached in
+// cache, which may be nil.
+func (m Model) WithPosters(cache *store.Posters, g Graphics) Model {
+	m.posters = cache
+	m.graphics = g
+	return m
+}
+
+func fetchPosterCmd(client *letterboxd.Client, cache *store.Posters, posterURL string) tea.Cmd {
+	return func() tea.Msg {
+		data, ok := []byte(nil), false
+		if cache != nil {
+			data, ok = cache.Get(posterURL)
+		}
+		if ok && client != nil {
+			client.CacheHit(posterURL)
+		}
+		if !ok {
+			var err error
+			data, err = client.Poster(posterURL)
+			if err != nil {
+				return posterMsg{url: posterURL, err: err}
+			}
+			if cache != nil {
+				_ = cache.Put(posterURL, data)
+			}
+		}
+		img, _, err := image.Decode(bytes.NewReader(data))
+		return posterMsg{url: posterURL, img: img, err: err}
+	}
+}
+
+// posterCmd fetches the open film's poster unless it is already loaded.
+func (m Model) posterCmd() tea.Cmd {
+	url := m.film.PosterURL
+	if m.graphics == GraphicsOff || m.client == nil || url == "" || url == m.poster.url {
+		return nil
+	}
+	return fetchPosterCmd(m.client, m.posters, url)
+}
+
+// posterSize is the poster's size in cells in the film view, zero when there
+// is no room or nothing to show.
+func (m Model) posterSize() (int, int) {
+	if m.graphics == GraphicsOff || m.poster.img == nil || m.poster.url != m.film.PosterURL {
+		return 0, 0
+	}
+	inner := modalContentWidth(m.width, m.height)
+	if inner < posterMinWidth {
+		return 0, 0
+	}
+	return fitPoster(m.poster.img, clamp(inner/4, 12, 24), m.modalVP.Height)
+}
+
+// layoutPoster re-renders the poster when the space for it changes and
+// narrows the film text to sit beside it.
+func (m *Model) layoutPoster() {
+	cols, rows := m.posterSize()
+	if cols == 0 {
+		return
+	}
+	if cols != m.poster.cols || rows != m.poster.rows {
+		m.poster.cols, m.poster.rows = cols, rows
+		m.poster.art = renderPoster(m.poster.img, m.graphics, cols, rows)
+	}
+	m.modalVP.Width -= cols + posterGap
+}
@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+const (
+	// splitMinWidth is the narrowest terminal that still gets the preview
+	// pane; below it the list keeps the full width.
+	splitMinWidth = 110
+	// previewDelay lets the selection settle before a preview is fetched, so
+	// holding j doesn't fetch every film on the way.
+	previewDelay = 250 * time.Millisecond
+)
+
+type previewTickMsg struct {
+	seq int
+	url string
+}
+
+type previewMsg struct {
+	url  string
+	film letterboxd.Film
+	err  error
+}
+
+func fetchPreviewCmd(client *letterboxd.Client, filmURL, username string) tea.Cmd {
+	return func() tea.Msg {
+		film, err := client.Film(filmURL, username)
+		return previewMsg{url: filmURL, film: film, err: err}
+	}
+}
+
+func previewTab(m Model) bool {
+	switch m.activeTab {
+	case tabDiary:
+		return !m.diaryHeatmap
+	case tabWatchlist, tabFilms, tabSearch:
+		return true
+	}
+	return false
+}
+
+func (m Model) splitActive() bool {
+	return m.splitView && previewTab(m) && m.width >= splitMinWidth
+}
+
+// splitWidths divides the width between the list and the preview pane,
+// which includes its one-column border.
+func (m Model) splitWidths() (int, int) {
+	pane := clamp(m.width*2/5, 40, 72)
+	return m.width - pane, pane
+}
+
+func (m Model) previewURL() string {
+	if m.activeTab == tabSearch && m.searchFocusInput {
+		return ""
+	}
+	return letterboxd.NormalizeFilmURL(m.selectedFilmURL())
+}
+
+// schedulePreview starts the debounce for the selected film whenever it
+// changes. Every message goes through it, so moving by key, mouse or
+// history all update the pane.
+func (m *Model) schedulePreview() tea.Cmd {
+	if !m.splitActive() {
+		return nil
+	}
+	url := m.previewURL()
+	if url == m.previewWant {
+		return nil
+	}
+	m.previewWant = url
+	m.previewErr = nil
+	if _, ok := m.previews[url]; ok || url == "" {
+		return nil
+	}
+	m.previewSeq++
+	seq := m.previewSeq
+	return tea.Tick(previewDelay, func(time.Time) tea.Msg {
+		return previewTickMsg{seq: seq, url: url}
+	})
+}
+
+func (m Model) updatePreview(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch ev := msg.(type) {
+	case previewTickMsg:
+		if ev.seq != m.previewSeq || ev.url != m.previewWant {
+			return m, nil, true
+		}
+		return m, fetchPreviewCmd(m.client, ev.url, m.username), true
+	case previewMsg:
+		if ev.url == m.previewWant {
+			m.previewErr = m.logAndSanitize("preview fetch", ev.err)
+		}
+		if ev.err == nil {
+			m.cachePreview(ev.url, ev.film)
+		}
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+func (m *Model) cachePreview(url string, film letterboxd.Film) {
+	if m.previews == nil {
+		m.previews = map[string]letterboxd.Film{}
+	}
+	m.previews[url] = film
+}
+
+func (m *Model) fitListWidth() {
+	if m.width <= 0 {
+		return
+	}
+	m.viewport.Width = m.width
+	if m.splitActive() {
+		m.viewport.Width, _ = m.splitWidths()
+	}
+}
+
+func (m *Model) toggleSplit() {
+	m.splitView = !m.splitView
+	m.previewWant = ""
+	m.resizeViewport()
+}
+
+func renderPreviewPane(m Model, width, height int, theme themeStyles) string {
+	inner := max(10, width-3)
+	var rows []string
+	film, ok := m.previews[m.previewWant]
+	switch {
+	case m.previewWant == "":
+		rows = append(rows, theme.dim.Render("Nothing selected."))
+	case m.previewErr != nil:
+		rows = append(rows, theme.dim.Render(wrapText("Error: "+errorText(m.previewErr), inner)))
+	case !ok:
+		rows = append(rows, theme.dim.Render("Loading preview…"))
+	default:
+		rows = renderFilmPreview(film, inner, theme)
+	}
+	if len(rows) > height {
+		rows = rows[:height]
+	}
+	return lipgloss.NewStyle().
+		Width(width-1).
+		Height(height).
+		PaddingLeft(1).
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(theme.dim.GetForeground()).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+func renderFilmPreview(film letterboxd.Film, width int, theme themeStyles) []string {
+	rows := []string{theme.header.Render(truncate(filmLabel(film.Title, film.Year), width))}
+	if meta := filmMeta(film); meta != "" {
+		rows = append(rows, theme.subtle.Render(wrapText(meta, width)))
+	}
+	if film.UserStatus != "" || film.UserRating != "" {
+		you := strings.TrimSpace("You: " + film.UserStatus)
+		if film.UserRating != "" {
+			you += " " + styleRating(film.UserRating, theme)
+		}
+		rows = append(rows, theme.subtle.Render(you))
+	}
+	if film.Description != "" {
+		rows = append(rows, "")
+		rows = append(rows, strings.Split(wrapText(film.Description, width), "\n")...)
+	}
+	return rows
+}
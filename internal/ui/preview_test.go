@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+func splitModel(t *testing.T, width int) Model {
+	t.Helper()
+	updated, _ := NewModel("jane", nil).Update(tea.WindowSizeMsg{Width: width, Height: 20})
+	m := updated.(Model)
+	m.activeTab = tabDiary
+	m.lastTab = tabDiary
+	m.loading = false
+	m.diary = []letterboxd.DiaryEntry{
+		{Title: "Alien", FilmURL: "https://letterboxd.com/film/alien/"},
+		{Title: "Heat", FilmURL: "https://letterboxd.com/film/heat/"},
+	}
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	return m
+}
+
+func TestPreviewDebouncesSelection(t *testing.T) {
+	m := splitModel(t, 140)
+	if !m.splitActive() || m.viewport.Width >= m.width {
+		t.Fatalf("expected split view with a narrowed list, got width %d", m.viewport.Width)
+	}
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyDown})
+	if m.previewWant != "https://letterboxd.com/film/heat/" {
+		t.Fatalf("expected preview to follow selection, got %q", m.previewWant)
+	}
+
+	// A tick for an older selection is dropped without fetching.
+	updated, cmd := m.Update(previewTickMsg{seq: m.previewSeq - 1, url: "https://letterboxd.com/film/alien/"})
+	m = updated.(Model)
+	if cmd != nil {
+		t.Fatalf("expected stale tick to be ignored")
+	}
+	updated, cmd = m.Update(previewTickMsg{seq: m.previewSeq, url: m.previewWant})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatalf("expected current tick to fetch the preview")
+	}
+
+	updated, _ = m.Update(previewMsg{url: m.previewWant, film: letterboxd.Film{
+		Title:       "Heat",
+		Year:        "1995",
+		Director:    "Michael Mann",
+		Description: "A group of professional bank robbers start to feel the heat.",
+	}})
+	m = updated.(Model)
+	view := stripANSI(m.View())
+	for _, want := range []string{"Heat (1995)", "Dir. Michael Mann", "professional bank robbers"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected preview to show %q, got:\n%s", want, view)
+		}
+	}
+
+	// Coming back to a cached film needs no fetch.
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyUp})
+	m, cmd = press(m, tea.KeyMsg{Type: tea.KeyDown})
+	if cmd != nil {
+		t.Fatalf("expected cached preview to skip the fetch")
+	}
+}
+
+func TestPreviewNeedsWideTerminal(t *testing.T) {
+	m := splitModel(t, 90)
+	if !m.splitView || m.splitActive() {
+		t.Fatalf("expected split view to stay off on a narrow terminal")
+	}
+	if m.viewport.Width != m.width {
+		t.Fatalf("expected list to keep the full width, got %d", m.viewport.Width)
+	}
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 140, Height: 20})
+	m = updated.(Model)
+	if !m.splitActive() || m.previewWant == "" {
+		t.Fatalf("expected widening the terminal to bring the preview back")
+	}
+}
@@ -0,0 +1,360 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+	"github.com/solean/letterboxd-tui/internal/store"
+)
+
+const (
+	queueRetryMin = 15 * time.Second
+	queueRetryMax = 10 * time.Minute
+
+	queueBusy = "Sending pending changes; try again when it's done."
+)
+
+var errQueueEdit = errors.New("use a YYYY-MM-DD date and a rating from 0 to 5")
+
+type queueSavedMsg struct {
+	err error
+}
+
+type queueRetryMsg struct{}
+
+type queueReplayedMsg struct {
+	result store.ReplayResult
+	err    error
+}
+
+// WithQueue attaches the pending-writes queue. Writes that fail because
+// Letterboxd is unreachable are kept there and replayed later.
+func (m Model) WithQueue(q *store.Queue) Model {
+	m.queue = q
+	return m
+}
+
+func (m Model) pendingOps() int {
+	if m.queue == nil {
+		return 0
+	}
+	return m.queue.Len()
+}
+
+func (m Model) queueOps() []store.Op {
+	if m.queue == nil {
+		return nil
+	}
+	return m.queue.Ops()
+}
+
+func saveQueueCmd(q *store.Queue) tea.Cmd {
+	return func() tea.Msg {
+		return queueSavedMsg{err: q.Save()}
+	}
+}
+
+func replayQueueCmd(client *letterboxd.Client, q *store.Queue, username string) tea.Cmd {
+	return func() tea.Msg {
+		result, err := store.Replay(client, q, username)
+		return queueReplayedMsg{result: result, err: err}
+	}
+}
+
+// queueWrite keeps a write that failed because Letterboxd was unreachable
+// and schedules a retry. It reports false for errors a retry won't fix.
+func (m *Model) queueWrite(op store.Op, err error) (tea.Cmd, bool) {
+	if m.queue == nil || !letterboxd.IsTransient(err) {
+		return nil, false
+	}
+	m.queue.Add(op)
+	return tea.Batch(saveQueueCmd(m.queue), m.scheduleReplayCmd()), true
+}
+
+func (m *Model) scheduleReplayCmd() tea.Cmd {
+	if m.queueRetryPending || m.pendingOps() == 0 {
+		return nil
+	}
+	m.queueRetryPending = true
+	if m.queueBackoff == 0 {
+		m.queueBackoff = queueRetryMin
+	}
+	return tea.Tick(m.queueBackoff, func(time.Time) tea.Msg { return queueRetryMsg{} })
+}
+
+func (m *Model) replayQueueCmd() tea.Cmd {
+	if m.queueReplaying || m.pendingOps() == 0 {
+		return nil
+	}
+	m.queueReplaying = true
+	return replayQueueCmd(m.client, m.queue, m.username)
+}
+
+func (m Model) finishReplay(ev queueReplayedMsg) (tea.Model, tea.Cmd) {
+	m.queueReplaying = false
+	m.queueStatus = ""
+	m.queueList.selected = clamp(m.queueList.selected, 0, max(0, m.pendingOps()-1))
+	var cmds []tea.Cmd
+	if done := ev.result.Sent + ev.result.Skipped; done > 0 {
+		m.queueStatus = fmt.Sprintf("Sent %d pending %s.", done, pluralChanges(done))
+		m.resetPagination()
+		cmds = append(cmds,
+			m.diaryFetchCmd(),
+			fetchWatchlistCmd(m.client, m.username, 1, m.watchlistQuery()),
+		)
+	}
+	switch {
+	case ev.err == nil:
+		m.queueBackoff = 0
+	case letterboxd.IsTransient(ev.err):
+		m.logAndSanitize("queue replay", ev.err)
+		m.queueBackoff = nextBackoff(m.queueBackoff)
+		m.queueStatus = fmt.Sprintf("Letterboxd unreachable; retrying in %s.", m.queueBackoff)
+		cmds = append(cmds, m.scheduleReplayCmd())
+	default:
+		err := m.logAndSanitize("queue replay", ev.err)
+		m.queueStatus = "Error: " + errorText(err)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func nextBackoff(current time.Duration) time.Duration {
+	next := current * 2
+	if next < queueRetryMin {
+		return queueRetryMin
+	}
+	if next > queueRetryMax {
+		return queueRetryMax
+	}
+	return next
+}
+
+func pluralChanges(n int) string {
+	if n == 1 {
+		return "change"
+	}
+	return "changes"
+}
+
+func (m Model) openQueue() Model {
+	if m.activeTab != tabQueue {
+		m.queueReturn = m.activeTab
+	}
+	m.profileModal = false
+	m.activeTab = tabQueue
+	m.resetTabPosition()
+	return m
+}
+
+func (m Model) closeQueue() Model {
+	m.activeTab = m.queueReturn
+	if m.activeTab == tabQueue || m.activeTab == tabFilm {
+		m.activeTab = tabProfile
+	}
+	m.resetTabPosition()
+	return m
+}
+
+func (m Model) selectedQueueOp() (store.Op, bool) {
+	ops := m.queueOps()
+	if len(ops) == 0 {
+		return store.Op{}, false
+	}
+	return ops[clamp(m.queueList.selected, 0, len(ops)-1)], true
+}
+
+func (m Model) retryQueue() (tea.Model, tea.Cmd) {
+	m.queueBackoff = 0
+	cmd := m.replayQueueCmd()
+	if cmd != nil {
+		m.queueStatus = "Retrying…"
+	}
+	return m, cmd
+}
+
+func (m *Model) handleQueueKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.activeTab != tabQueue || m.modalOpen() {
+		return nil, false
+	}
+	switch {
+	case key.Matches(msg, m.keys.Edit, m.keys.Discard) && m.queueReplaying:
+		// Replay sends the copy it read, so an edit now would be lost.
+		m.queueStatus = queueBusy
+	case key.Matches(msg, m.keys.Edit):
+		if op, ok := m.selectedQueueOp(); ok {
+			m.queueEditID = op.ID
+			*m = m.showFilterForm(newQueueEditForm(op))
+		}
+	case key.Matches(msg, m.keys.Discard):
+		if op, ok := m.selectedQueueOp(); ok && m.queue.Remove(op.ID) {
+			m.queueList.selected = clamp(m.queueList.selected, 0, max(0, m.pendingOps()-1))
+			m.queueStatus = "Discarded " + op.Title + "."
+			return saveQueueCmd(m.queue), true
+		}
+	case key.Matches(msg, m.keys.Back, m.keys.Cancel):
+		*m = m.closeQueue()
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+func newQueueEditForm(op store.Op) filterForm {
+	form := filterForm{id: filterQueueEdit, title: "Edit pending change"}
+	if op.Kind == store.OpWatchlist {
+		action := "remove"
+		if op.InWatchlist {
+			action = "add"
+		}
+		form.fields = []filterField{newFilterChoiceField("action", "Watchlist", []string{"add", "remove"}, action)}
+		form.focusField(0)
+		return form
+	}
+	req := letterboxd.DiaryEntryRequest{}
+	if op.Diary != nil {
+		req = *op.Diary
+	}
+	rating := ""
+	if req.RatingValue > 0 {
+		rating = fmtFloat(float64(req.RatingValue)/2, 1)
+	}
+	review := newFilterTextField("review", "Review", "", req.Review)
+	review.input.CharLimit = 0
+	form.fields = []filterField{
+		newFilterTextField("date", "Watched", "YYYY-MM-DD", req.WatchedDate),
+		newFilterTextField("rating", "Rating", "e.g. 4.5", rating),
+		newFilterToggleField("rewatch", "Rewatch", req.Rewatch),
+		newFilterToggleField("liked", "Liked", req.Liked),
+		newFilterTextField("tags", "Tags", "comma,separated", req.Tags),
+		review,
+	}
+	form.focusField(0)
+	return form
+}
+
+func (m Model) applyQueueEdit() (tea.Model, tea.Cmd) {
+	var op store.Op
+	found := false
+	for _, pending := range m.queueOps() {
+		if pending.ID == m.queueEditID {
+			op, found = pending, true
+		}
+	}
+	if !found {
+		m.queueStatus = "That change was already sent or discarded."
+		return m, nil
+	}
+	if m.queueReplaying {
+		m.filterForm.status = queueBusy
+		m = m.showFilterForm(m.filterForm)
+		return m, nil
+	}
+	if op.Kind == store.OpWatchlist {
+		op.InWatchlist = m.filterForm.value("action") == "add"
+	} else {
+		req, err := diaryRequestFromForm(op.Diary, m.filterForm)
+		if err != nil {
+			m.filterForm.status = err.Error()
+			m = m.showFilterForm(m.filterForm)
+			return m, nil
+		}
+		op.Diary = &req
+	}
+	op.LastError = ""
+	m.queue.Add(op)
+	m.queueStatus = "Updated " + op.Title + "."
+	m.queueBackoff = 0
+	return m, tea.Batch(saveQueueCmd(m.queue), m.replayQueueCmd())
+}
+
+func diaryRequestFromForm(base *letterboxd.DiaryEntryRequest, form filterForm) (letterboxd.DiaryEntryRequest, error) {
+	var req letterboxd.DiaryEntryRequest
+	if base != nil {
+		req = *base
+	}
+	date := form.value("date")
+	if _, err := time.Parse(time.DateOnly, date); err != nil {
+		return req, errQueueEdit
+	}
+	rating := 0
+	if value := form.value("rating"); value != "" {
+		stars, err := strconv.ParseFloat(value, 64)
+		if err != nil || stars < 0 || stars > 5 {
+			return req, errQueueEdit
+		}
+		rating = int(math.Round(stars * 2))
+	}
+	req.WatchedDate = date
+	req.RatingValue = rating
+	req.Rewatch = form.value("rewatch") == "yes"
+	req.Liked = form.value("liked") == "yes"
+	req.Tags = form.value("tags")
+	req.Review = form.value("review")
+	return req, nil
+}
+
+func describeQueueOp(op store.Op) string {
+	parts := []string{}
+	switch op.Kind {
+	case store.OpWatchlist:
+		if op.InWatchlist {
+			parts = append(parts, "Add to watchlist", op.Title)
+		} else {
+			parts = append(parts, "Remove from watchlist", op.Title)
+		}
+	default:
+		parts = append(parts, "Log", op.Title)
+		if op.Diary != nil {
+			if day, err := time.Parse(time.DateOnly, op.Diary.WatchedDate); err == nil {
+				parts = append(parts, formatDiaryDate(day))
+			}
+			if op.Diary.RatingValue > 0 {
+				parts = append(parts, starsFromHalves(op.Diary.RatingValue))
+			}
+		}
+	}
+	line := strings.Join(parts, " · ")
+	if op.Attempts > 0 {
+		line += fmt.Sprintf(" · %d tries", op.Attempts)
+	}
+	return line
+}
+
+func renderQueue(m Model, theme themeStyles) string {
+	ops := m.queueOps()
+	header := fmt.Sprintf("%d pending %s", len(ops), pluralChanges(len(ops)))
+	if m.queueReplaying {
+		header += " · sending…"
+	}
+	if m.queueStatus != "" {
+		header += " · " + m.queueStatus
+	}
+	rows := []string{theme.subtle.Render(header)}
+	if len(ops) == 0 {
+		rows = append(rows, theme.dim.Render("Nothing waiting to be sent."))
+		return lipgloss.JoinVertical(lipgloss.Left, rows...)
+	}
+	width := max(40, m.width-2)
+	for i, op := range ops {
+		line := describeQueueOp(op)
+		if op.LastError != "" {
+			line += " — " + firstLine(op.LastError)
+		}
+		rows = append(rows, renderSelectableLine(line, i == m.queueList.selected, width, theme))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
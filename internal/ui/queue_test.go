@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+	"github.com/solean/letterboxd-tui/internal/store"
+)
+
+func newQueueModel(t *testing.T) Model {
+	t.Helper()
+	q, err := store.OpenQueueFile(filepath.Join(t.TempDir(), "jane.json"))
+	if err != nil {
+		t.Fatalf("OpenQueueFile error: %v", err)
+	}
+	m := NewModel("jane", nil).WithQueue(q)
+	m.film = letterboxd.Film{Title: "Heat", URL: letterboxd.BaseURL + "/film/heat/", ViewingUID: "u1"}
+	return m
+}
+
+func TestFailedLogIsQueued(t *testing.T) {
+	m := newQueueModel(t)
+	m.logModal = true
+	req := letterboxd.DiaryEntryRequest{ViewingUID: "u1", WatchedDate: "2024-03-10", RatingValue: 7, Referer: m.film.URL}
+	offline := &net.OpError{Op: "dial", Err: errors.New("network is unreachable")}
+	m.film = letterboxd.Film{Title: "Alien", URL: letterboxd.BaseURL + "/film/alien/"}
+	model, cmd := m.Update(logResultMsg{err: offline, req: req, title: "Heat", filmURL: letterboxd.BaseURL + "/film/heat/"})
+	out := model.(Model)
+	if cmd == nil || out.pendingOps() != 1 || !out.queueRetryPending {
+		t.Fatalf("expected queued write with a retry scheduled, got %d pending", out.pendingOps())
+	}
+	if op := out.queueOps()[0]; op.Title != "Heat" || op.FilmURL != letterboxd.BaseURL+"/film/heat/" {
+		t.Fatalf("expected the op queued for the film it was written for, got %+v", op)
+	}
+	if !strings.Contains(out.logForm.status, "queued") {
+		t.Fatalf("unexpected status %q", out.logForm.status)
+	}
+	if !strings.Contains(stripANSI(renderHeader(out, newTheme())), "1 pending") {
+		t.Fatalf("expected pending count in header")
+	}
+
+	model, _ = out.Update(logResultMsg{err: errors.New("unexpected status 400"), req: req})
+	if out = model.(Model); out.pendingOps() != 1 || !strings.HasPrefix(out.logForm.status, "Error:") {
+		t.Fatalf("expected permanent errors to stay errors, got %q", out.logForm.status)
+	}
+}
+
+func TestReplayBacksOffWhileOffline(t *testing.T) {
+	m := newQueueModel(t)
+	m.queue.Add(store.NewWatchlistOp("Heat", m.film.URL, letterboxd.WatchlistRequest{WatchlistID: "lid1"}, true))
+	m.queueReplaying = true
+	m.queueBackoff = queueRetryMin
+	offline := &net.OpError{Op: "dial", Err: errors.New("network is unreachable")}
+	model, cmd := m.Update(queueReplayedMsg{err: offline})
+	out := model.(Model)
+	if cmd == nil || out.queueReplaying || !out.queueRetryPending || out.queueBackoff != 2*queueRetryMin {
+		t.Fatalf("expected doubled backoff, got %s", out.queueBackoff)
+	}
+	if nextBackoff(queueRetryMax) != queueRetryMax {
+		t.Fatalf("expected backoff to be capped")
+	}
+}
+
+func TestQueueTabEditAndDiscard(t *testing.T) {
+	m := newQueueModel(t)
+	m.queue.Add(store.NewDiaryOp("Heat", m.film.URL, letterboxd.DiaryEntryRequest{ViewingUID: "u1", WatchedDate: "2024-03-10", RatingValue: 7}))
+	m.queue.Add(store.NewWatchlistOp("Alien", "/film/alien/", letterboxd.WatchlistRequest{WatchlistID: "lid2"}, true))
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	m = model.(Model)
+	if m.activeTab != tabQueue || !strings.Contains(stripANSI(renderQueue(m, newTheme())), "Log · Heat · Mar 10 2024") {
+		t.Fatalf("expected queue tab, got tab %d: %q", m.activeTab, stripANSI(renderQueue(m, newTheme())))
+	}
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	m = model.(Model)
+	if !m.filterModal || m.filterForm.id != filterQueueEdit || m.filterForm.value("rating") != "3.5" {
+		t.Fatalf("expected edit form with the queued rating, got %q", m.filterForm.value("rating"))
+	}
+	m.filterForm.fields[0].input.SetValue("March 10")
+	model, _ = m.applyFilterForm()
+	if m = model.(Model); !m.filterModal || m.filterForm.status == "" {
+		t.Fatalf("expected invalid date to keep the form open")
+	}
+	m.filterForm.fields[0].input.SetValue("2024-03-11")
+	m.filterForm.fields[1].input.SetValue("4")
+	model, _ = m.applyFilterForm()
+	m = model.(Model)
+	if op := m.queueOps()[0]; op.Diary.WatchedDate != "2024-03-11" || op.Diary.RatingValue != 8 {
+		t.Fatalf("expected edited op, got %+v", op.Diary)
+	}
+
+	m.queueReplaying = false
+	m.queueList.selected = 1
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if m = model.(Model); m.pendingOps() != 1 || m.queueOps()[0].Title != "Heat" {
+		t.Fatalf("expected discard to drop Alien, got %+v", m.queueOps())
+	}
+}
+
+func TestQueueEditWaitsForReplay(t *testing.T) {
+	m := newQueueModel(t)
+	m.queue.Add(store.NewDiaryOp("Heat", m.film.URL, letterboxd.DiaryEntryRequest{ViewingUID: "u1", WatchedDate: "2024-03-10", RatingValue: 7}))
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	m = model.(Model)
+
+	m.queueReplaying = true
+	for _, r := range "ed" {
+		model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		if m = model.(Model); m.filterModal || m.pendingOps() != 1 || m.queueStatus != queueBusy {
+			t.Fatalf("expected %q to wait for the replay, got status %q", r, m.queueStatus)
+		}
+	}
+
+	m.queueReplaying = false
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	m = model.(Model)
+	m.queueReplaying = true
+	m.filterForm.fields[1].input.SetValue("4")
+	model, _ = m.applyFilterForm()
+	m = model.(Model)
+	if !m.filterModal || m.filterForm.status != queueBusy || m.queueOps()[0].Diary.RatingValue != 7 {
+		t.Fatalf("expected the edit held back while sending, got %q", m.filterForm.status)
+	}
+	model, _ = m.Update(queueReplayedMsg{})
+	m = model.(Model)
+	model, _ = m.applyFilterForm()
+	if m = model.(Model); m.queueOps()[0].Diary.RatingValue != 8 {
+		t.Fatalf("expected the edit saved once sending finished, got %+v", m.queueOps()[0].Diary)
+	}
+}
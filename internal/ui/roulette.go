@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+	"github.com/solean/letterboxd-tui/internal/store"
+)
+
+// rouletteBatch is how many candidates a roll checks between progress
+// updates when it has to look up runtimes, and rouletteMaxFetches how many
+// film pages one roll loads at most.
+const (
+	rouletteBatch      = 10
+	rouletteMaxFetches = 50
+)
+
+type rouletteOptions struct {
+	filter     letterboxd.WatchlistFilter
+	maxRuntime int
+}
+
+// rouletteMsg is a finished roll, or with more set, progress through the
+// shuffled candidates still to be checked against the runtime limit.
+type rouletteMsg struct {
+	opts    rouletteOptions
+	pool    []letterboxd.WatchlistItem
+	poolCut bool
+	order   []letterboxd.WatchlistItem
+	checked int
+	fetched int
+	failed  int
+	lastErr error
+	more    bool
+	item    letterboxd.WatchlistItem
+	film    letterboxd.Film
+	err     error
+}
+
+// rouletteCutNote says a roll drew from a watchlist too long to read whole.
+const rouletteCutNote = "(from part of the watchlist; it's too long to read in full)"
+
+var rouletteShuffle = rand.Perm
+
+func rouletteCmd(client *letterboxd.Client, s *store.Store, username string, opts rouletteOptions, pool []letterboxd.WatchlistItem, poolCut bool, exclude string) tea.Cmd {
+	return func() tea.Msg {
+		msg := rouletteMsg{opts: opts, pool: pool, poolCut: poolCut}
+		if msg.pool == nil {
+			items, err := client.AllWatchlist(username, opts.filter)
+			msg.poolCut = errors.Is(err, letterboxd.ErrListTruncated)
+			if err != nil && !msg.poolCut {
+				msg.err = err
+				return msg
+			}
+			msg.pool = items
+		}
+		candidates := rouletteCandidates(msg.pool, exclude)
+		if len(candidates) == 0 {
+			msg.err = errors.New("no watchlist films match")
+			return msg
+		}
+		for _, idx := range rouletteShuffle(len(candidates)) {
+			msg.order = append(msg.order, candidates[idx])
+		}
+		if opts.maxRuntime <= 0 {
+			msg.item = msg.order[0]
+			msg.order = nil
+			return msg
+		}
+		return rouletteCheck(client, s, username, msg)
+	}
+}
+
+func rouletteContinueCmd(client *letterboxd.Client, s *store.Store, username string, msg rouletteMsg) tea.Cmd {
+	return func() tea.Msg {
+		return rouletteCheck(client, s, username, msg)
+	}
+}
+
+// rouletteCheck walks the shuffled candidates in order, so the first one
+// within the runtime limit is a uniform pick among those that are. Runtimes
+// the store already has save a fetch; a film that can't be fetched is
+// skipped rather than ending the roll. A roll gives up after
+// rouletteMaxFetches fetches; the next one starts from a fresh shuffle.
+func rouletteCheck(client *letterboxd.Client, s *store.Store, username string, msg rouletteMsg) rouletteMsg {
+	msg.more = false
+	fetched := 0
+	for len(msg.order) > 0 {
+		item := msg.order[0]
+		if s != nil {
+			if film, ok := s.Film(item.FilmURL); ok && film.Runtime != "" {
+				msg.order = msg.order[1:]
+				msg.checked++
+				if mins := letterboxd.RuntimeMinutes(film.Runtime); mins > 0 && mins <= msg.opts.maxRuntime {
+					msg.item = item
+					return msg
+				}
+				continue
+			}
+		}
+		if msg.fetched == rouletteMaxFetches {
+			break
+		}
+		if fetched == rouletteBatch {
+			msg.more = true
+			return msg
+		}
+		fetched++
+		msg.fetched++
+		msg.order = msg.order[1:]
+		msg.checked++
+		film, err := client.Film(item.FilmURL, username)
+		if err != nil {
+			msg.failed++
+			msg.lastErr = err
+			continue
+		}
+		if s != nil {
+			s.PutFilm(film)
+		}
+		if mins := letterboxd.RuntimeMinutes(film.Runtime); mins > 0 && mins <= msg.opts.maxRuntime {
+			msg.item = item
+			msg.film = film
+			return msg
+		}
+	}
+	reason := fmt.Sprintf("no film under %d mins among %d checked", msg.opts.maxRuntime, msg.checked)
+	if msg.failed > 0 {
+		reason += fmt.Sprintf(" (%d couldn't be checked)", msg.failed)
+	}
+	if len(msg.order) > 0 {
+		reason += "; roll again to check others"
+	}
+	switch {
+	case msg.failed > 0 && msg.failed == msg.checked:
+		msg.err = msg.lastErr
+	default:
+		msg.err = errors.New(reason)
+	}
+	msg.order = nil
+	return msg
+}
+
+func rouletteCandidates(pool []letterboxd.WatchlistItem, exclude string) []letterboxd.WatchlistItem {
+	exclude = letterboxd.NormalizeFilmURL(exclude)
+	if exclude == "" || len(pool) < 2 {
+		return pool
+	}
+	candidates := make([]letterboxd.WatchlistItem, 0, len(pool))
+	for _, item := range pool {
+		if letterboxd.NormalizeFilmURL(item.FilmURL) == exclude {
+			continue
+		}
+		candidates = append(candidates, item)
+	}
+	return candidates
+}
+
+func newRouletteForm(opts rouletteOptions) filterForm {
+	runtime := ""
+	if opts.maxRuntime > 0 {
+		runtime = strconv.Itoa(opts.maxRuntime)
+	}
+	form := filterForm{
+		id:    filterRoulette,
+		title: "Watchlist roulette",
+		fields: []filterField{
+			newFilterTextField("runtime", "Max runtime (mins)", "e.g. 120", runtime),
+			newFilterTextField("genre", "Genre", "e.g. horror", opts.filter.Genre),
+			newFilterTextField("decade", "Decade", "e.g. 1990s", opts.filter.Decade),
+			newFilterTextField("service", "Service", "e.g. netflix-us", opts.filter.Service),
+		},
+	}
+	form.focusField(0)
+	return form
+}
+
+func rouletteOptionsFromForm(form filterForm) rouletteOptions {
+	return rouletteOptions{
+		filter: letterboxd.WatchlistFilter{
+			Genre:   form.value("genre"),
+			Decade:  form.value("decade"),
+			Service: form.value("service"),
+		},
+		maxRuntime: letterboxd.RuntimeMinutes(form.value("runtime")),
+	}
+}
+
+func (m Model) startRoulette(opts rouletteOptions) (tea.Model, tea.Cmd) {
+	if opts != m.rouletteOpts {
+		m.roulettePool, m.roulettePoolCut = nil, false
+	}
+	m.rouletteOpts = opts
+	m.roulettePending = true
+	m.rouletteStatus = "Rolling..."
+	exclude := ""
+	if m.activeTab == tabFilm {
+		exclude = m.film.URL
+		m.watchlistStatus = m.rouletteStatus
+		m.refreshModalViewport()
+	}
+	return m, rouletteCmd(m.client, m.store, m.username, opts, m.roulettePool, m.roulettePoolCut, exclude)
+}
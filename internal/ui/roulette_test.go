@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+	"github.com/solean/letterboxd-tui/internal/store"
+)
+
+func watchlistPageHTML(slugs ...string) string {
+	html := `<div class="js-watchlist-main-content">`
+	for _, slug := range slugs {
+		html += fmt.Sprintf(`<div class="react-component" data-item-name="%s (2000)" data-item-link="/film/%s/"></div>`, slug, slug)
+	}
+	return html + `</div>`
+}
+
+func TestRouletteCmdUsesWholeWatchlist(t *testing.T) {
+	oldShuffle := rouletteShuffle
+	defer func() { rouletteShuffle = oldShuffle }()
+	rouletteShuffle = func(n int) []int {
+		order := make([]int, n)
+		for i := range order {
+			order[i] = n - 1 - i
+		}
+		return order
+	}
+
+	var paths []string
+	client := newStubClient(func(req *http.Request) (*http.Response, error) {
+		paths = append(paths, req.URL.Path)
+		switch req.URL.Path {
+		case "/jane/watchlist/genre/horror/":
+			return newHTTPResponse(http.StatusOK, watchlistPageHTML("alien", "the-thing")), nil
+		case "/jane/watchlist/genre/horror/page/2/":
+			return newHTTPResponse(http.StatusOK, watchlistPageHTML("halloween")), nil
+		case "/film/halloween/":
+			return newHTTPResponse(http.StatusOK, `<meta property="og:title" content="Halloween (1978)"><p class="text-link text-footer">91 mins</p>`), nil
+		case "/film/the-thing/":
+			return newHTTPResponse(http.StatusOK, `<meta property="og:title" content="The Thing (1982)"><p class="text-link text-footer">109 mins</p>`), nil
+		default:
+			return newHTTPResponse(http.StatusOK, watchlistPageHTML()), nil
+		}
+	})
+	opts := rouletteOptions{filter: letterboxd.WatchlistFilter{Genre: "horror"}}
+	msg := rouletteCmd(client, nil, "jane", opts, nil, false, "")().(rouletteMsg)
+	if msg.err != nil {
+		t.Fatalf("unexpected roulette error: %v", msg.err)
+	}
+	if len(msg.pool) != 3 || msg.item.Title != "halloween" {
+		t.Fatalf("unexpected roulette result: %+v", msg)
+	}
+
+	opts.maxRuntime = 100
+	paths = nil
+	msg = rouletteCmd(client, nil, "jane", opts, msg.pool, false, letterboxd.BaseURL+"/film/halloween/")().(rouletteMsg)
+	if msg.err == nil {
+		t.Fatalf("expected no film within runtime once halloween is excluded")
+	}
+	for _, path := range paths {
+		if path == "/jane/watchlist/genre/horror/" {
+			t.Fatalf("expected cached pool to be reused")
+		}
+	}
+
+	msg = rouletteCmd(client, nil, "jane", opts, msg.pool, false, "")().(rouletteMsg)
+	if msg.err != nil || msg.film.Title != "Halloween" {
+		t.Fatalf("expected runtime-constrained pick, got %+v", msg)
+	}
+}
+
+func TestUpdateRouletteMsgOpensFilm(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.activeTab = tabWatchlist
+	m.roulettePending = true
+	item := letterboxd.WatchlistItem{Title: "Alien", FilmURL: letterboxd.BaseURL + "/film/alien/"}
+	model, cmd := m.Update(rouletteMsg{pool: []letterboxd.WatchlistItem{item}, item: item})
+	out := model.(Model)
+	if out.activeTab != tabFilm || out.location().base != tabWatchlist || !out.rouletteActive || cmd == nil {
+		t.Fatalf("expected roulette pick to open film view")
+	}
+	if len(out.roulettePool) != 1 {
+		t.Fatalf("expected pool to be cached")
+	}
+}
+
+func TestRouletteChecksEveryCandidate(t *testing.T) {
+	oldShuffle := rouletteShuffle
+	defer func() { rouletteShuffle = oldShuffle }()
+	rouletteShuffle = func(n int) []int {
+		order := make([]int, n)
+		for i := range order {
+			order[i] = i
+		}
+		return order
+	}
+	var pool []letterboxd.WatchlistItem
+	for i := 0; i < 25; i++ {
+		pool = append(pool, letterboxd.WatchlistItem{Title: fmt.Sprintf("f%d", i), FilmURL: fmt.Sprintf("%s/film/f%d/", letterboxd.BaseURL, i)})
+	}
+	s, err := store.OpenFile(filepath.Join(t.TempDir(), "jane.json"), "jane")
+	if err != nil {
+		t.Fatalf("OpenFile error: %v", err)
+	}
+	s.PutFilm(letterboxd.Film{Title: "F3", URL: letterboxd.BaseURL + "/film/f3/", Runtime: "200 mins"})
+	var fetched []string
+	client := newStubClient(func(req *http.Request) (*http.Response, error) {
+		fetched = append(fetched, req.URL.Path)
+		switch req.URL.Path {
+		case "/film/f1/":
+			return newHTTPResponse(http.StatusInternalServerError, ""), nil
+		case "/film/f22/":
+			return newHTTPResponse(http.StatusOK, `<meta property="og:title" content="F22 (2000)"><p class="text-link text-footer">85 mins</p>`), nil
+		}
+		return newHTTPResponse(http.StatusOK, `<meta property="og:title" content="Long (2000)"><p class="text-link text-footer">150 mins</p>`), nil
+	})
+
+	m := NewModel("jane", client).WithStore(s)
+	m.activeTab = tabWatchlist
+	opts := rouletteOptions{maxRuntime: 90}
+	m.rouletteOpts, m.roulettePool = opts, pool
+	updated, cmd := m.startRoulette(opts)
+	m = updated.(Model)
+	rolls := 0
+	for m.roulettePending {
+		if rolls++; rolls > 5 {
+			t.Fatalf("roll never finished")
+		}
+		updated, cmd = m.Update(cmd())
+		m = updated.(Model)
+		if m.roulettePending && !strings.Contains(m.rouletteStatus, "checked") {
+			t.Fatalf("expected progress while rolling, got %q", m.rouletteStatus)
+		}
+	}
+	if m.activeTab != tabFilm || m.film.URL != letterboxd.BaseURL+"/film/f22/" {
+		t.Fatalf("expected f22 picked past a failed fetch, got %q (%q)", m.film.URL, m.rouletteStatus)
+	}
+	for _, path := range fetched {
+		if path == "/film/f3/" {
+			t.Fatalf("expected the stored runtime to be used for f3")
+		}
+	}
+}
+
+func TestRouletteCapsFetchesPerRoll(t *testing.T) {
+	var pool []letterboxd.WatchlistItem
+	for i := 0; i < 3*rouletteMaxFetches; i++ {
+		pool = append(pool, letterboxd.WatchlistItem{Title: fmt.Sprintf("f%d", i), FilmURL: fmt.Sprintf("%s/film/f%d/", letterboxd.BaseURL, i)})
+	}
+	films := map[string]bool{}
+	client := newStubClient(func(req *http.Request) (*http.Response, error) {
+		if strings.HasPrefix(req.URL.Path, "/film/") && strings.HasSuffix(req.URL.Path, "/") {
+			films[req.URL.Path] = true
+		}
+		return newHTTPResponse(http.StatusOK, `<meta property="og:title" content="Long (2000)"><p class="text-link text-footer">150 mins</p>`), nil
+	})
+	msg := rouletteCmd(client, nil, "jane", rouletteOptions{maxRuntime: 90}, pool, true, "")().(rouletteMsg)
+	for msg.more {
+		msg = rouletteCheck(client, nil, "jane", msg)
+	}
+	if fetches := len(films); fetches != rouletteMaxFetches || msg.err == nil || !strings.Contains(msg.err.Error(), fmt.Sprintf("among %d checked", rouletteMaxFetches)) {
+		t.Fatalf("expected the roll to stop after %d fetches, got %d: %v", rouletteMaxFetches, fetches, msg.err)
+	}
+
+	m := NewModel("jane", client)
+	m.activeTab = tabWatchlist
+	m.roulettePending, m.rouletteOpts = true, msg.opts
+	model, _ := m.Update(msg)
+	if m = model.(Model); !m.roulettePoolCut || !strings.Contains(m.rouletteStatus, rouletteCutNote) {
+		t.Fatalf("expected the status to say the watchlist was cut short, got %q", m.rouletteStatus)
+	}
+}
@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+// sessionMsg is the startup check of the cookie. staleClearance is the age
+// of a cf_clearance old enough to be challenged soon, zero otherwise.
+type sessionMsg struct {
+	err            error
+	staleClearance time.Duration
+}
+
+// clearanceWarnAge is how old cf_clearance gets before the header suggests
+// replacing it, so a challenge doesn't arrive mid-edit.
+const clearanceWarnAge = 7 * 24 * time.Hour
+
+// cookieCheckedMsg is the check of a cookie typed into the cookie modal.
+type cookieCheckedMsg struct {
+	cookie string
+	err    error
+}
+
+func checkSessionCmd(client *letterboxd.Client, username string) tea.Cmd {
+	return func() tea.Msg {
+		return sessionMsg{err: client.CheckSession(username), staleClearance: clearanceAge(client.Cookie, time.Now())}
+	}
+}
+
+// clearanceAge is how old the cf_clearance in cookie is once it passes
+// clearanceWarnAge, and zero before then or without one.
+func clearanceAge(cookie string, now time.Time) time.Duration {
+	issued, ok := letterboxd.ClearanceIssued(cookie)
+	if !ok || now.Sub(issued) < clearanceWarnAge {
+		return 0
+	}
+	return now.Sub(issued)
+}
+
+// checkCookieCmd tries cookie on a client of its own, so the one in use
+// keeps its cookie until the new one is known to work.
+func checkCookieCmd(client *letterboxd.Client, cookie, username string) tea.Cmd {
+	trial := letterboxd.NewClient(client.HTTP, cookie)
+	trial.Debug = client.Debug
+	trial.OnEvent = client.OnEvent
+	return func() tea.Msg {
+		return cookieCheckedMsg{cookie: cookie, err: trial.CheckSession(username)}
+	}
+}
+
+// sessionInvalid reports whether err says the cookie itself is bad, rather
+// than that Letterboxd couldn't be reached.
+func sessionInvalid(err error) bool {
+	return errors.Is(err, letterboxd.ErrSignedOut) || errors.Is(err, letterboxd.ErrWrongUser) || letterboxd.ClearanceExpired(err)
+}
+
+// sessionProblem says what is wrong with the cookie in a sentence.
+func sessionProblem(err error) string {
+	switch {
+	case errors.Is(err, letterboxd.ErrWrongUser):
+		return "Error: " + errorText(err) + "."
+	case letterboxd.ClearanceExpired(err):
+		return "Error: Cloudflare challenge; cf_clearance is missing or expired."
+	case errors.Is(err, letterboxd.ErrSignedOut):
+		return "Error: This cookie is signed out or has expired."
+	}
+	return "Error: " + errorText(err)
+}
+
+func (m *Model) applySession(msg sessionMsg) {
+	m.staleClearance = msg.staleClearance
+	if msg.err != nil && !sessionInvalid(msg.err) {
+		// Offline or a Letterboxd hiccup says nothing about the cookie.
+		m.logError("session check", msg.err)
+		return
+	}
+	m.sessionErr = msg.err
+}
+
+// sessionBanner is the header's warning about a cookie that stopped
+// working, empty when it works.
+func (m Model) sessionBanner() string {
+	if m.sessionErr == nil {
+		if m.staleClearance > 0 {
+			return fmt.Sprintf("· cf_clearance is %d days old, %s to refresh the cookie", int(m.staleClearance.Hours()/24), keyHint(m.keys.Cookie))
+		}
+		return ""
+	}
+	reason := "session expired"
+	switch {
+	case errors.Is(m.sessionErr, letterboxd.ErrWrongUser):
+		reason = "cookie is for another member"
+	case letterboxd.ClearanceExpired(m.sessionErr):
+		reason = "cf_clearance expired"
+	}
+	return fmt.Sprintf("· %s, %s to update the cookie", reason, keyHint(m.keys.Cookie))
+}
+
+// clearanceNote says how old the cf_clearance in cookie is, for the cookie
+// modal.
+func clearanceNote(cookie string, now time.Time) string {
+	issued, ok := letterboxd.ClearanceIssued(cookie)
+	if !ok {
+		return ""
+	}
+	age := now.Sub(issued)
+	switch {
+	case age < time.Hour:
+		return "cf_clearance issued in the last hour."
+	case age < 48*time.Hour:
+		return fmt.Sprintf("cf_clearance issued %d hours ago.", int(age.Hours()))
+	}
+	return fmt.Sprintf("cf_clearance issued %d days ago.", int(age.Hours()/24))
+}
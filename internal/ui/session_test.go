@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+func TestSessionBannerOnlyForBadCookies(t *testing.T) {
+	m := NewModel("jane", nil)
+	updated, _ := m.update(sessionMsg{err: errors.New("dial tcp: no route to host")})
+	m = updated.(Model)
+	if m.sessionBanner() != "" {
+		t.Fatalf("expected no banner when offline, got %q", m.sessionBanner())
+	}
+	updated, _ = m.update(sessionMsg{err: letterboxd.ErrSignedOut})
+	m = updated.(Model)
+	if !strings.Contains(m.sessionBanner(), "session expired") || !strings.Contains(renderHeader(m, m.theme), "update the cookie") {
+		t.Fatalf("expected the expired session in the header, got %q", renderHeader(m, m.theme))
+	}
+}
+
+func TestCookieModalChecksBeforeSaving(t *testing.T) {
+	signedIn := true
+	client := newStubClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/settings/" {
+			t.Fatalf("unexpected request %s", req.URL.Path)
+		}
+		if !signedIn {
+			return newHTTPResponse(http.StatusOK, `<form id="signin-form"></form>`), nil
+		}
+		return newHTTPResponse(http.StatusOK, `<input id="frm-username" value="jane">`), nil
+	})
+	m := NewModel("jane", client)
+	m.promptCookieUpdate("")
+	m.cookieInput.SetValue("com.xk72.webparts.csrf=new; cf_clearance=abc")
+
+	updated, cmd := m.update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd == nil || !strings.Contains(m.cookieStatus, "Checking") {
+		t.Fatalf("expected a check first, got %q", m.cookieStatus)
+	}
+	updated, _ = m.update(cmd())
+	m = updated.(Model)
+	if m.cookiePending == "" || !strings.Contains(m.cookieStatus, "Saving") {
+		t.Fatalf("expected a working cookie to be saved, got %q", m.cookieStatus)
+	}
+
+	signedIn = false
+	m.cookieSaving = false
+	updated, cmd = m.update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	updated, _ = m.update(cmd())
+	m = updated.(Model)
+	if m.cookieSaving || !strings.Contains(m.cookieStatus, "signed out") || client.Cookie != "com.xk72.webparts.csrf=csrf123" {
+		t.Fatalf("expected a signed-out cookie to be refused, got %q", m.cookieStatus)
+	}
+}
+
+func TestSessionBannerWarnsOfAnAgingClearance(t *testing.T) {
+	issued := time.Now().Add(-10 * 24 * time.Hour).Unix()
+	client := newStubClient(func(req *http.Request) (*http.Response, error) {
+		return newHTTPResponse(http.StatusOK, `<input id="frm-username" value="jane">`), nil
+	})
+	client.Cookie = fmt.Sprintf("com.xk72.webparts.csrf=csrf123; cf_clearance=abc-%d-1.2.1.1-xyz", issued)
+	m := NewModel("jane", client)
+	updated, _ := m.update(checkSessionCmd(client, "jane")())
+	m = updated.(Model)
+	if m.sessionErr != nil || !strings.Contains(m.sessionBanner(), "cf_clearance is 10 days old") {
+		t.Fatalf("expected a warning while the cookie still works, got %q", m.sessionBanner())
+	}
+
+	client.Cookie = fmt.Sprintf("com.xk72.webparts.csrf=csrf123; cf_clearance=abc-%d-1.2.1.1-xyz", time.Now().Unix())
+	updated, _ = m.update(checkSessionCmd(client, "jane")())
+	m = updated.(Model)
+	if m.sessionBanner() != "" {
+		t.Fatalf("expected no warning for a fresh clearance, got %q", m.sessionBanner())
+	}
+}
@@ -1,6 +1,12 @@
 package ui
 
-import "github.com/solean/letterboxd-tui/internal/letterboxd"
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
 
 type diarySort int
 
@@ -21,6 +27,25 @@ const (
 	watchlistSortCount
 )
 
+type filmsSort int
+
+const (
+	filmsSortWatched filmsSort = iota
+	filmsSortYourRating
+	filmsSortAvgRating
+	filmsSortTitle
+	filmsSortYearNewest
+	filmsSortShortest
+	filmsSortCount
+)
+
+func (s filmsSort) next() filmsSort {
+	if s+1 >= filmsSortCount {
+		return filmsSortWatched
+	}
+	return s + 1
+}
+
 func (s diarySort) next() diarySort {
 	if s+1 >= diarySortCount {
 		return diarySortRecent
@@ -35,6 +60,23 @@ func (s watchlistSort) next() watchlistSort {
 	return s + 1
 }
 
+// resortList reloads the current tab's list after its sort mode changed.
+func (m Model) resortList() (tea.Model, tea.Cmd) {
+	switch m.activeTab {
+	case tabDiary:
+		m.resetDiaryList()
+		m.diaryFrom, m.diaryTo = time.Time{}, time.Time{}
+		return m, fetchDiaryCmd(m.client, m.username, 1, m.diarySortParam())
+	case tabWatchlist:
+		m.resetWatchlist()
+		return m, fetchWatchlistCmd(m.client, m.username, 1, m.watchlistQuery())
+	case tabFilms:
+		m.resetFilmsList()
+		return m, fetchUserFilmsCmd(m.client, m.username, m.filmsQuery(), 1)
+	}
+	return m, nil
+}
+
 func (m Model) diarySortLabel() string {
 	switch m.diarySort {
 	case diarySortOldest:
@@ -59,6 +101,52 @@ func (m Model) watchlistSortLabel() string {
 	}
 }
 
+func (m Model) filmsSortLabel() string {
+	switch m.filmsSort {
+	case filmsSortYourRating:
+		return "Your rating"
+	case filmsSortAvgRating:
+		return "Rating (avg)"
+	case filmsSortTitle:
+		return "Title"
+	case filmsSortYearNewest:
+		return "Year (newest)"
+	case filmsSortShortest:
+		return "Shortest"
+	default:
+		return "Watched"
+	}
+}
+
+func (m Model) filmsSortParam() letterboxd.FilmsSort {
+	switch m.filmsSort {
+	case filmsSortYourRating:
+		return letterboxd.FilmsSortEntryRating
+	case filmsSortAvgRating:
+		return letterboxd.FilmsSortRating
+	case filmsSortTitle:
+		return letterboxd.FilmsSortName
+	case filmsSortYearNewest:
+		return letterboxd.FilmsSortRelease
+	case filmsSortShortest:
+		return letterboxd.FilmsSortShortest
+	default:
+		return letterboxd.FilmsSortDefault
+	}
+}
+
+func (m Model) watchlistQuery() letterboxd.WatchlistFilter {
+	query := m.watchlistFilter
+	query.Sort = m.watchlistSortParam()
+	return query
+}
+
+func (m Model) filmsQuery() letterboxd.FilmsFilter {
+	query := m.filmsFilter
+	query.Sort = m.filmsSortParam()
+	return query
+}
+
 func (m Model) diarySortParam() letterboxd.DiarySort {
 	switch m.diarySort {
 	case diarySortOldest:
@@ -0,0 +1,519 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+)
+
+const (
+	statsEnrichBatch = 6
+	// statsEnrichLimit caps the film pages fetched for stats in one session.
+	// What is fetched is kept in the store, so a large diary fills in over a
+	// few sessions instead of all at once.
+	statsEnrichLimit = 300
+	statsTopLimit    = 5
+)
+
+// statsEnrichPause spaces out the film page fetches.
+var statsEnrichPause = 300 * time.Millisecond
+
+type statsFilmsMsg struct {
+	urls  []string
+	films []letterboxd.Film
+	err   error
+}
+
+type nameCount struct {
+	name  string
+	count int
+}
+
+type statsPeriod struct {
+	label string
+	count int
+	avg   float64
+}
+
+type diaryStats struct {
+	year        int
+	entries     int
+	uniqueFilms int
+	rewatches   int
+	rated       int
+	avgRating   float64
+	ratings     [10]int
+	periods     []statsPeriod
+	streak      int
+	streakStart time.Time
+	streakEnd   time.Time
+	busiest     statsPeriod
+	topRated    []letterboxd.DiaryEntry
+	first       letterboxd.DiaryEntry
+	last        letterboxd.DiaryEntry
+	directors   []nameCount
+	actors      []nameCount
+	runtimeMins int
+	enriched    int
+}
+
+func fetchStatsFilmsCmd(client *letterboxd.Client, username string, urls []string) tea.Cmd {
+	return func() tea.Msg {
+		msg := statsFilmsMsg{urls: urls}
+		failed := 0
+		for i, filmURL := range urls {
+			if i > 0 {
+				time.Sleep(statsEnrichPause)
+			}
+			film, err := client.Film(filmURL, username)
+			if err != nil {
+				failed++
+				msg.err = err
+				film = letterboxd.Film{URL: filmURL}
+			}
+			msg.films = append(msg.films, film)
+		}
+		if failed < len(urls) {
+			msg.err = nil
+		}
+		return msg
+	}
+}
+
+func (m Model) statsYears() []int {
+	seen := map[int]struct{}{}
+	var years []int
+	for _, entry := range m.allDiary {
+		t := entry.Date
+		if t.IsZero() {
+			continue
+		}
+		if _, dup := seen[t.Year()]; dup {
+			continue
+		}
+		seen[t.Year()] = struct{}{}
+		years = append(years, t.Year())
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(years)))
+	return years
+}
+
+func (m Model) statsScope() []letterboxd.DiaryEntry {
+	if m.statsYear == 0 {
+		return m.allDiary
+	}
+	var scoped []letterboxd.DiaryEntry
+	for _, entry := range m.allDiary {
+		if !entry.Date.IsZero() && entry.Date.Year() == m.statsYear {
+			scoped = append(scoped, entry)
+		}
+	}
+	return scoped
+}
+
+// cycleStatsYear steps through "all years" followed by each diary year, newest first.
+func (m *Model) cycleStatsYear(dir int) {
+	options := append([]int{0}, m.statsYears()...)
+	idx := 0
+	for i, year := range options {
+		if year == m.statsYear {
+			idx = i
+		}
+	}
+	idx = (idx + dir + len(options)) % len(options)
+	m.statsYear = options[idx]
+	if m.statsYear == 0 {
+		m.statsReview = false
+	}
+	m.viewport.YOffset = 0
+}
+
+func (m *Model) toggleStatsReview() {
+	if !m.statsReview && m.statsYear == 0 {
+		years := m.statsYears()
+		if len(years) == 0 {
+			return
+		}
+		m.statsYear = years[0]
+	}
+	m.statsReview = !m.statsReview
+	m.viewport.YOffset = 0
+}
+
+func (m *Model) statsEnrichCmd() tea.Cmd {
+	if m.statsEnriching || m.statsEnrichErr != nil {
+		return nil
+	}
+	limit := min(statsEnrichBatch, statsEnrichLimit-m.statsFetched)
+	var batch []string
+	seen := map[string]struct{}{}
+	for _, entry := range m.statsScope() {
+		filmURL := letterboxd.NormalizeFilmURL(entry.FilmURL)
+		if filmURL == "" {
+			continue
+		}
+		if _, ok := m.statsFilms[filmURL]; ok {
+			continue
+		}
+		if film, ok := m.storedFilm(filmURL); ok {
+			m.statsFilms[filmURL] = film
+			continue
+		}
+		if _, ok := seen[filmURL]; ok {
+			continue
+		}
+		seen[filmURL] = struct{}{}
+		if len(batch) == limit {
+			m.statsCapped = true
+			break
+		}
+		batch = append(batch, filmURL)
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+	m.statsCapped = false
+	m.statsFetched += len(batch)
+	m.statsEnriching = true
+	return fetchStatsFilmsCmd(m.client, m.username, batch)
+}
+
+func computeDiaryStats(entries []letterboxd.DiaryEntry, films map[string]letterboxd.Film, year int) diaryStats {
+	stats := diaryStats{year: year, entries: len(entries)}
+	unique := map[string]struct{}{}
+	directors := map[string]int{}
+	actors := map[string]int{}
+	days := map[time.Time]struct{}{}
+	var ratingSum float64
+	periodIndex := map[string]int{}
+	periodSums := map[string]float64{}
+	periodRated := map[string]int{}
+	if year != 0 {
+		for month := time.January; month <= time.December; month++ {
+			label := month.String()[:3]
+			periodIndex[label] = len(stats.periods)
+			stats.periods = append(stats.periods, statsPeriod{label: label})
+		}
+	}
+	var firstTime, lastTime time.Time
+	for _, entry := range entries {
+		filmURL := letterboxd.NormalizeFilmURL(entry.FilmURL)
+		if filmURL != "" {
+			unique[filmURL] = struct{}{}
+		}
+		if entry.Rewatch {
+			stats.rewatches++
+		}
+		rating := starsToValue(entry.Rating)
+		if rating > 0 {
+			stats.rated++
+			ratingSum += rating
+			stats.ratings[clamp(int(math.Round(rating*2))-1, 0, 9)]++
+			if rating >= 4.5 {
+				stats.topRated = append(stats.topRated, entry)
+			}
+		}
+		if t := entry.Date; !t.IsZero() {
+			days[t] = struct{}{}
+			if firstTime.IsZero() || t.Before(firstTime) {
+				firstTime = t
+				stats.first = entry
+			}
+			if lastTime.IsZero() || !t.Before(lastTime) {
+				lastTime = t
+				stats.last = entry
+			}
+			label := strconv.Itoa(t.Year())
+			if year != 0 {
+				label = t.Month().String()[:3]
+			}
+			idx, ok := periodIndex[label]
+			if !ok {
+				idx = len(stats.periods)
+				periodIndex[label] = idx
+				stats.periods = append(stats.periods, statsPeriod{label: label})
+			}
+			stats.periods[idx].count++
+			if rating > 0 {
+				periodSums[label] += rating
+				periodRated[label]++
+			}
+		}
+		film, ok := films[filmURL]
+		if !ok || film.Title == "" {
+			continue
+		}
+		stats.enriched++
+		stats.runtimeMins += letterboxd.RuntimeMinutes(film.Runtime)
+		for _, director := range strings.Split(film.Director, ",") {
+			if director = strings.TrimSpace(director); director != "" {
+				directors[director]++
+			}
+		}
+		for _, actor := range film.Cast {
+			actors[actor]++
+		}
+	}
+	stats.uniqueFilms = len(unique)
+	if stats.rated > 0 {
+		stats.avgRating = ratingSum / float64(stats.rated)
+	}
+	if year == 0 {
+		sort.Slice(stats.periods, func(i, j int) bool { return stats.periods[i].label < stats.periods[j].label })
+	}
+	for i := range stats.periods {
+		label := stats.periods[i].label
+		if periodRated[label] > 0 {
+			stats.periods[i].avg = periodSums[label] / float64(periodRated[label])
+		}
+		if stats.periods[i].count > stats.busiest.count {
+			stats.busiest = stats.periods[i]
+		}
+	}
+	stats.streak, stats.streakStart, stats.streakEnd = longestStreak(days)
+	sort.SliceStable(stats.topRated, func(i, j int) bool {
+		return starsToValue(stats.topRated[i].Rating) > starsToValue(stats.topRated[j].Rating)
+	})
+	stats.directors = topCounts(directors, statsTopLimit)
+	stats.actors = topCounts(actors, statsTopLimit)
+	return stats
+}
+
+func longestStreak(days map[time.Time]struct{}) (int, time.Time, time.Time) {
+	if len(days) == 0 {
+		return 0, time.Time{}, time.Time{}
+	}
+	sorted := make([]time.Time, 0, len(days))
+	for day := range days {
+		sorted = append(sorted, day)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+	best, bestStart, bestEnd := 1, sorted[0], sorted[0]
+	run, runStart := 1, sorted[0]
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Sub(sorted[i-1]) == 24*time.Hour {
+			run++
+		} else {
+			run, runStart = 1, sorted[i]
+		}
+		if run > best {
+			best, bestStart, bestEnd = run, runStart, sorted[i]
+		}
+	}
+	return best, bestStart, bestEnd
+}
+
+func topCounts(counts map[string]int, limit int) []nameCount {
+	out := make([]nameCount, 0, len(counts))
+	for name, count := range counts {
+		out = append(out, nameCount{name: name, count: count})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].count != out[j].count {
+			return out[i].count > out[j].count
+		}
+		return out[i].name < out[j].name
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func renderStats(m Model, theme themeStyles) string {
+	if m.allDiaryErr != nil {
+		return theme.dim.Render("Error: " + errorText(m.allDiaryErr))
+	}
+	if !m.allDiaryLoaded {
+		return theme.dim.Render("Loading full diary…")
+	}
+	if len(m.allDiary) == 0 {
+		return theme.dim.Render("No diary entries found.")
+	}
+	stats := computeDiaryStats(m.statsScope(), m.statsFilms, m.statsYear)
+	width := max(40, m.width-2)
+	scope := "All years"
+	if m.statsYear != 0 {
+		scope = strconv.Itoa(m.statsYear)
+	}
+	rows := []string{theme.header.Render(scope) + " " + theme.subtle.Render(fmt.Sprintf("· %d entries · %d films", stats.entries, stats.uniqueFilms))}
+	if m.allDiaryTruncated {
+		rows = append(rows, theme.dim.Render("The diary is too long to read in full; these stats cover the newest entries."))
+	}
+	if m.statsReview {
+		rows = append(rows, renderYearInReview(stats, theme))
+	} else {
+		rows = append(rows, renderStatsDashboard(stats, width, theme))
+	}
+	rows = append(rows, "", renderEnrichStatus(m, stats, theme))
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func renderStatsDashboard(stats diaryStats, width int, theme themeStyles) string {
+	barWidth := max(10, min(40, width-24))
+	var rows []string
+
+	title := "Films per year"
+	if stats.year != 0 {
+		title = "Films per month"
+	}
+	rows = append(rows, "", theme.header.Render(title))
+	maxCount := 0
+	for _, period := range stats.periods {
+		maxCount = max(maxCount, period.count)
+	}
+	for _, period := range stats.periods {
+		rows = append(rows, renderStatsBar(period.label, period.count, maxCount, barWidth, theme))
+	}
+
+	rows = append(rows, "", theme.header.Render("Ratings"))
+	maxRating := 0
+	for _, count := range stats.ratings {
+		maxRating = max(maxRating, count)
+	}
+	for i := len(stats.ratings) - 1; i >= 0; i-- {
+		label := starsFromHalves(i + 1)
+		rows = append(rows, renderStatsBar(label, stats.ratings[i], maxRating, barWidth, theme))
+	}
+	if stats.rated > 0 {
+		rows = append(rows, theme.subtle.Render(fmt.Sprintf("Average %.2f from %d rated entries", stats.avgRating, stats.rated)))
+	}
+
+	var avgs []float64
+	for _, period := range stats.periods {
+		avgs = append(avgs, period.avg)
+	}
+	rows = append(rows, "", theme.header.Render("Average rating over time"))
+	rows = append(rows, theme.rateHigh.Render(sparkline(avgs, 5))+" "+theme.subtle.Render(periodRange(stats.periods)))
+
+	rows = append(rows, "", theme.header.Render("Habits"))
+	rewatchRatio := 0.0
+	if stats.entries > 0 {
+		rewatchRatio = float64(stats.rewatches) / float64(stats.entries) * 100
+	}
+	rows = append(rows, fmt.Sprintf("Rewatches: %d (%.0f%%)", stats.rewatches, rewatchRatio))
+	rows = append(rows, fmt.Sprintf("Longest streak: %s", describeStreak(stats)))
+	rows = append(rows, fmt.Sprintf("Runtime: %s", formatRuntime(stats.runtimeMins)))
+
+	rows = append(rows, "", theme.header.Render("Most-watched directors"))
+	rows = append(rows, renderNameCounts(stats.directors, barWidth, theme)...)
+	rows = append(rows, "", theme.header.Render("Most-watched actors"))
+	rows = append(rows, renderNameCounts(stats.actors, barWidth, theme)...)
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func renderYearInReview(stats diaryStats, theme themeStyles) string {
+	rows := []string{"", theme.header.Render(fmt.Sprintf("%d in review", stats.year))}
+	rows = append(rows, fmt.Sprintf("You logged %d films (%d unique), %d of them rewatches.", stats.entries, stats.uniqueFilms, stats.rewatches))
+	if stats.runtimeMins > 0 {
+		rows = append(rows, fmt.Sprintf("That's %s in front of the screen.", formatRuntime(stats.runtimeMins)))
+	}
+	if stats.busiest.count > 0 {
+		rows = append(rows, fmt.Sprintf("Busiest month: %s with %d films.", stats.busiest.label, stats.busiest.count))
+	}
+	if stats.streak > 1 {
+		rows = append(rows, fmt.Sprintf("Longest streak: %s.", describeStreak(stats)))
+	}
+	if stats.rated > 0 {
+		rows = append(rows, fmt.Sprintf("Average rating: %.2f.", stats.avgRating))
+	}
+	if stats.first.Title != "" {
+		rows = append(rows, fmt.Sprintf("First film: %s (%s) · Last film: %s (%s)", theme.movie.Render(stats.first.Title), formatDiaryDate(stats.first.Date), theme.movie.Render(stats.last.Title), formatDiaryDate(stats.last.Date)))
+	}
+	if len(stats.directors) > 0 {
+		rows = append(rows, fmt.Sprintf("Director of the year: %s (%d films)", theme.user.Render(stats.directors[0].name), stats.directors[0].count))
+	}
+	if len(stats.topRated) > 0 {
+		rows = append(rows, "", theme.header.Render("Favourites"))
+		for _, entry := range stats.topRated[:min(10, len(stats.topRated))] {
+			rows = append(rows, fmt.Sprintf("%s %s", entry.Title, styleRating(entry.Rating, theme)))
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func renderEnrichStatus(m Model, stats diaryStats, theme themeStyles) string {
+	status := fmt.Sprintf("Directors, actors and runtime from %d of %d entries.", stats.enriched, stats.entries)
+	if m.statsEnrichErr != nil {
+		return theme.rateLow.Render(status + " Error: " + errorText(m.statsEnrichErr))
+	}
+	if m.statsEnriching {
+		status += " Fetching film details…"
+	} else if m.statsCapped && m.statsFetched >= statsEnrichLimit {
+		status += " The rest are fetched next session."
+	}
+	return theme.dim.Render(status)
+}
+
+func renderStatsBar(label string, value, maxValue, width int, theme themeStyles) string {
+	filled := 0
+	if maxValue > 0 {
+		filled = int(math.Round(float64(value) / float64(maxValue) * float64(width)))
+	}
+	if value > 0 {
+		filled = max(1, filled)
+	}
+	bar := theme.rateHigh.Render(strings.Repeat("█", filled)) + theme.dim.Render(strings.Repeat("░", width-filled))
+	return fmt.Sprintf("%-10s %s %d", truncate(label, 10), bar, value)
+}
+
+func renderNameCounts(counts []nameCount, width int, theme themeStyles) []string {
+	if len(counts) == 0 {
+		return []string{theme.dim.Render("No film details yet.")}
+	}
+	var rows []string
+	for _, entry := range counts {
+		rows = append(rows, renderStatsBar(entry.name, entry.count, counts[0].count, width, theme))
+	}
+	return rows
+}
+
+func sparkline(values []float64, maxValue float64) string {
+	levels := []rune("▁▂▃▄▅▆▇█")
+	var out strings.Builder
+	for _, value := range values {
+		if value <= 0 {
+			out.WriteRune(' ')
+			continue
+		}
+		idx := int(math.Round(value / maxValue * float64(len(levels)-1)))
+		out.WriteRune(levels[clamp(idx, 0, len(levels)-1)])
+	}
+	return out.String()
+}
+
+func periodRange(periods []statsPeriod) string {
+	if len(periods) == 0 {
+		return ""
+	}
+	return periods[0].label + "–" + periods[len(periods)-1].label
+}
+
+func starsFromHalves(halves int) string {
+	return strings.Repeat("★", halves/2) + strings.Repeat("½", halves%2)
+}
+
+func describeStreak(stats diaryStats) string {
+	if stats.streak == 0 {
+		return "none"
+	}
+	if stats.streak == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days (%s – %s)", stats.streak, stats.streakStart.Format("Jan 2 2006"), stats.streakEnd.Format("Jan 2 2006"))
+}
+
+func formatRuntime(mins int) string {
+	if mins <= 0 {
+		return "unknown"
+	}
+	return fmt.Sprintf("%dh %02dm", mins/60, mins%60)
+}
@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+	"github.com/solean/letterboxd-tui/internal/store"
+)
+
+func statsFixture() []letterboxd.DiaryEntry {
+	return []letterboxd.DiaryEntry{
+		{Date: time.Date(2024, time.March, 3, 0, 0, 0, 0, time.UTC), Title: "Alien", FilmURL: "https://letterboxd.com/film/alien/", Rating: "★★★★★"},
+		{Date: time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC), Title: "Heat", FilmURL: "https://letterboxd.com/film/heat/", Rating: "★★★½"},
+		{Date: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), Title: "Alien", FilmURL: "https://letterboxd.com/film/alien/", Rewatch: true},
+		{Date: time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC), Title: "Aliens", FilmURL: "https://letterboxd.com/film/aliens/", Rating: "★★★★"},
+		{Date: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC), Title: "Thief", FilmURL: "https://letterboxd.com/film/thief/", Rating: "★★"},
+	}
+}
+
+func TestComputeDiaryStats(t *testing.T) {
+	films := map[string]letterboxd.Film{
+		"https://letterboxd.com/film/alien/": {Title: "Alien", Director: "Ridley Scott", Runtime: "117 mins", Cast: []string{"Sigourney Weaver"}},
+		"https://letterboxd.com/film/heat/":  {Title: "Heat", Director: "Michael Mann", Runtime: "170 mins", Cast: []string{"Al Pacino"}},
+		"https://letterboxd.com/film/thief/": {URL: "https://letterboxd.com/film/thief/"},
+	}
+	stats := computeDiaryStats(statsFixture(), films, 0)
+	if stats.entries != 5 || stats.uniqueFilms != 4 || stats.rewatches != 1 {
+		t.Fatalf("unexpected counts: %+v", stats)
+	}
+	if stats.rated != 4 || stats.avgRating != 3.625 {
+		t.Fatalf("unexpected ratings: rated=%d avg=%v", stats.rated, stats.avgRating)
+	}
+	if stats.ratings[9] != 1 || stats.ratings[6] != 1 || stats.ratings[3] != 1 {
+		t.Fatalf("unexpected rating buckets: %v", stats.ratings)
+	}
+	if len(stats.periods) != 2 || stats.periods[0].label != "2023" || stats.periods[1].count != 4 {
+		t.Fatalf("unexpected periods: %+v", stats.periods)
+	}
+	if stats.streak != 3 || stats.streakStart.Day() != 1 || stats.streakEnd.Day() != 3 {
+		t.Fatalf("unexpected streak: %d %v %v", stats.streak, stats.streakStart, stats.streakEnd)
+	}
+	if stats.enriched != 3 || stats.runtimeMins != 404 {
+		t.Fatalf("unexpected enrichment: enriched=%d runtime=%d", stats.enriched, stats.runtimeMins)
+	}
+	if len(stats.directors) != 2 || stats.directors[0].name != "Ridley Scott" || stats.directors[0].count != 2 {
+		t.Fatalf("unexpected directors: %+v", stats.directors)
+	}
+	if stats.first.Title != "Thief" || stats.last.Title != "Alien" {
+		t.Fatalf("unexpected first/last: %q %q", stats.first.Title, stats.last.Title)
+	}
+}
+
+func TestComputeDiaryStatsYearUsesMonths(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.allDiary = statsFixture()
+	m.statsYear = 2024
+	stats := computeDiaryStats(m.statsScope(), nil, m.statsYear)
+	if stats.entries != 4 || len(stats.periods) != 12 {
+		t.Fatalf("unexpected year stats: entries=%d periods=%d", stats.entries, len(stats.periods))
+	}
+	if stats.busiest.label != "Mar" || stats.busiest.count != 3 {
+		t.Fatalf("unexpected busiest month: %+v", stats.busiest)
+	}
+}
+
+func TestStatsYearCycleAndReview(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.activeTab = tabStats
+	m.allDiary = statsFixture()
+	m.allDiaryLoaded = true
+	m.statsFilms = map[string]letterboxd.Film{}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	m = updated.(Model)
+	if m.statsYear != 2024 {
+		t.Fatalf("expected 2024, got %d", m.statsYear)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	m = updated.(Model)
+	if m.statsYear != 2023 {
+		t.Fatalf("expected 2023, got %d", m.statsYear)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("[")})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("[")})
+	m = updated.(Model)
+	if m.statsYear != 0 {
+		t.Fatalf("expected all years, got %d", m.statsYear)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(Model)
+	if !m.statsReview || m.statsYear != 2024 {
+		t.Fatalf("expected review of latest year, got review=%v year=%d", m.statsReview, m.statsYear)
+	}
+	m.width = 80
+	view := stripANSI(renderStats(m, newTheme()))
+	if !strings.Contains(view, "2024 in review") || !strings.Contains(view, "Busiest month: Mar with 3 films.") {
+		t.Fatalf("unexpected review view: %q", view)
+	}
+}
+
+func TestStatsDiaryMsgStartsEnrichment(t *testing.T) {
+	m := NewModel("jane", letterboxd.NewClient(nil, ""))
+	m.activeTab = tabStats
+	m.allDiaryLoading = true
+	updated, cmd := m.Update(allDiaryMsg{entries: statsFixture()})
+	m = updated.(Model)
+	if !m.allDiaryLoaded || m.allDiaryLoading || !m.statsEnriching || cmd == nil {
+		t.Fatalf("expected enrichment to start: loaded=%v enriching=%v", m.allDiaryLoaded, m.statsEnriching)
+	}
+	updated, _ = m.Update(statsFilmsMsg{
+		urls:  []string{"https://letterboxd.com/film/alien/"},
+		films: []letterboxd.Film{{Title: "Alien"}},
+	})
+	m = updated.(Model)
+	if m.statsFilms["https://letterboxd.com/film/alien/"].Title != "Alien" {
+		t.Fatalf("expected enriched film to be stored")
+	}
+	m.width = 80
+	view := stripANSI(renderStats(m, newTheme()))
+	if !strings.Contains(view, "Films per year") || !strings.Contains(view, "Rewatches: 1 (20%)") {
+		t.Fatalf("unexpected dashboard view: %q", view)
+	}
+}
+
+func TestStatsEnrichUsesStoreAndStopsAtLimit(t *testing.T) {
+	s, err := store.OpenFile(filepath.Join(t.TempDir(), "jane.json"), "jane")
+	if err != nil {
+		t.Fatalf("OpenFile error: %v", err)
+	}
+	for _, slug := range []string{"alien", "heat", "aliens"} {
+		s.PutFilm(letterboxd.Film{Title: slug, URL: "https://letterboxd.com/film/" + slug + "/"})
+	}
+	m := NewModel("jane", letterboxd.NewClient(nil, "")).WithStore(s)
+	m.allDiary = statsFixture()
+	m.allDiaryLoaded = true
+	m.statsFilms = map[string]letterboxd.Film{}
+
+	m.statsFetched = statsEnrichLimit - 1
+	if cmd := m.statsEnrichCmd(); cmd == nil || m.statsFetched != statsEnrichLimit {
+		t.Fatalf("expected only the film missing from the store to be fetched, fetched=%d", m.statsFetched)
+	}
+	if len(m.statsFilms) != 3 {
+		t.Fatalf("expected stored films used, got %d", len(m.statsFilms))
+	}
+
+	capped := NewModel("jane", letterboxd.NewClient(nil, ""))
+	capped.allDiary = m.allDiary
+	capped.statsFilms = map[string]letterboxd.Film{}
+	capped.statsFetched = statsEnrichLimit
+	if cmd := capped.statsEnrichCmd(); cmd != nil || !capped.statsCapped {
+		t.Fatalf("expected no fetches past the session limit")
+	}
+	capped.width = 80
+	capped.activeTab = tabStats
+	capped.allDiaryLoaded = true
+	if view := stripANSI(renderStats(capped, newTheme())); !strings.Contains(view, "fetched next session") {
+		t.Fatalf("expected the limit explained, got %q", view)
+	}
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+	"github.com/solean/letterboxd-tui/internal/store"
+)
+
+const storeSearchLimit = 20
+
+type storeSavedMsg struct {
+	err error
+}
+
+// WithStore attaches the local store and shows what it already holds while
+// the first fetches run, so the app opens with data even when offline.
+func (m Model) WithStore(s *store.Store) Model {
+	m.store = s
+	if s == nil {
+		return m
+	}
+	snap := s.Snapshot()
+	m.diary = snap.Diary
+	m.watchlist = snap.Watchlist
+	m.watchlistLoaded = len(snap.Watchlist) > 0
+	m.films = snap.Films
+	return m
+}
+
+// The store holds each list in its default order, so only unfiltered,
+// default-sorted pages are cached or used as an offline fallback.
+func (m Model) storesDiary() bool {
+	return m.store != nil && m.diarySort == diarySortRecent && !m.diaryRangeActive()
+}
+
+func (m Model) storesWatchlist() bool {
+	return m.store != nil && m.watchlistSort == watchlistSortAdded && m.watchlistFilter == (letterboxd.WatchlistFilter{})
+}
+
+func (m Model) storesFilms() bool {
+	return m.store != nil && m.filmsSort == filmsSortWatched && m.filmsFilter == (letterboxd.FilmsFilter{})
+}
+
+func saveStoreCmd(s *store.Store, update func(*store.Store)) tea.Cmd {
+	return func() tea.Msg {
+		update(s)
+		return storeSavedMsg{err: s.Save()}
+	}
+}
+
+// syncAllDiaryCmd reads only the diary pages logged since the last sync and
+// falls back to the saved diary when Letterboxd can't be reached.
+func syncAllDiaryCmd(client *letterboxd.Client, s *store.Store) tea.Cmd {
+	return func() tea.Msg {
+		_, err := store.SyncDiary(client, s, store.SyncOptions{})
+		if err == nil {
+			err = s.Save()
+		}
+		snap := s.Snapshot()
+		if err != nil && len(snap.Diary) > 0 {
+			return allDiaryMsg{entries: snap.Diary, offline: true}
+		}
+		return allDiaryMsg{entries: snap.Diary, err: err}
+	}
+}
+
+func (m Model) storedSearch(query string) []letterboxd.SearchResult {
+	if m.store == nil {
+		return nil
+	}
+	return m.store.Search(query, storeSearchLimit)
+}
+
+// useOffline reports whether saved data should stand in for a failed fetch,
+// logging the error and marking the session offline when it does.
+func (m *Model) useOffline(context string, err error, cached int) bool {
+	if cached == 0 {
+		return false
+	}
+	m.logAndSanitize(context, err)
+	m.offline = true
+	return true
+}
+
+func (m Model) storedFilm(filmURL string) (letterboxd.Film, bool) {
+	if m.store == nil || m.statsFilms == nil {
+		return letterboxd.Film{}, false
+	}
+	return m.store.Film(filmURL)
+}
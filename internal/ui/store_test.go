@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/solean/letterboxd-tui/internal/letterboxd"
+	"github.com/solean/letterboxd-tui/internal/store"
+)
+
+func newTestStore(t *testing.T) *store.Store {
+	t.Helper()
+	s, err := store.OpenFile(filepath.Join(t.TempDir(), "jane.json"), "jane")
+	if err != nil {
+		t.Fatalf("OpenFile error: %v", err)
+	}
+	s.MergeDiary([]letterboxd.DiaryEntry{{Date: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), Title: "Heat", FilmURL: "/film/heat/"}})
+	s.MergeWatchlist([]letterboxd.WatchlistItem{{Title: "Alien", FilmURL: "/film/alien/"}})
+	return s
+}
+
+func TestWithStorePrefillsLists(t *testing.T) {
+	m := NewModel("jane", nil).WithStore(newTestStore(t))
+	if len(m.diary) != 1 || len(m.watchlist) != 1 || !m.watchlistLoaded {
+		t.Fatalf("expected cached lists, got diary=%d watchlist=%d", len(m.diary), len(m.watchlist))
+	}
+}
+
+func TestFailedFetchFallsBackToStore(t *testing.T) {
+	m := NewModel("jane", nil).WithStore(newTestStore(t))
+	model, _ := m.Update(diaryMsg{err: errors.New("offline"), page: 1, sort: letterboxd.DiarySortDefault})
+	out := model.(Model)
+	if out.diaryErr != nil || len(out.diary) != 1 || !out.diaryDone {
+		t.Fatalf("expected saved diary, got err=%v diary=%d done=%v", out.diaryErr, len(out.diary), out.diaryDone)
+	}
+	if !out.offline || !strings.Contains(stripANSI(renderHeader(out, newTheme())), "offline") {
+		t.Fatalf("expected offline header")
+	}
+
+	model, _ = out.Update(diaryMsg{items: []letterboxd.DiaryEntry{{Title: "Alien", FilmURL: "/film/alien/"}}, page: 1, sort: letterboxd.DiarySortDefault})
+	if out = model.(Model); out.offline {
+		t.Fatalf("expected successful fetch to clear offline")
+	}
+
+	out.diarySort = diarySortRating
+	model, _ = out.Update(diaryMsg{err: errors.New("offline"), page: 1, sort: letterboxd.DiarySortRating})
+	if out = model.(Model); out.diaryErr == nil {
+		t.Fatalf("expected error for a sort the store does not keep")
+	}
+}
+
+func TestSearchShowsStoredMatches(t *testing.T) {
+	m := NewModel("jane", nil).WithStore(newTestStore(t))
+	m.activeTab = tabSearch
+	m.searchInput.SetValue("ali")
+	m.searchFocusInput = true
+	m.handleSearchKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.searchResults) != 1 || m.searchResults[0].Title != "Alien" {
+		t.Fatalf("expected stored match, got %+v", m.searchResults)
+	}
+	model, _ := m.Update(searchMsg{err: errors.New("offline")})
+	if out := model.(Model); out.searchErr != nil || len(out.searchResults) != 1 {
+		t.Fatalf("expected stored results to survive a failed search")
+	}
+}
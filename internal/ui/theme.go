@@ -1,12 +1,15 @@
 package ui
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 )
 
 type themeStyles struct {
+	name      string
 	header    lipgloss.Style
 	subtle    lipgloss.Style
 	tab       lipgloss.Style
@@ -20,35 +23,300 @@ type themeStyles struct {
 	rateHigh  lipgloss.Style
 	rateMid   lipgloss.Style
 	rateLow   lipgloss.Style
+	match     lipgloss.Style
+	panel     lipgloss.Style
+	shade     lipgloss.Style
+	heat      [5]lipgloss.Style
+	glow      []string
 }
 
+func (t *themeStyles) named() map[string]*lipgloss.Style {
+	return map[string]*lipgloss.Style{
+		"header":     &t.header,
+		"subtle":     &t.subtle,
+		"tab":        &t.tab,
+		"tab_active": &t.tabActive,
+		"item":       &t.item,
+		"item_sel":   &t.itemSel,
+		"badge":      &t.badge,
+		"dim":        &t.dim,
+		"user":       &t.user,
+		"movie":      &t.movie,
+		"rate_high":  &t.rateHigh,
+		"rate_mid":   &t.rateMid,
+		"rate_low":   &t.rateLow,
+		"match":      &t.match,
+		"panel":      &t.panel,
+		"shade":      &t.shade,
+	}
+}
+
+// themeColors is the palette a theme's styles are built from. An empty color
+// leaves the terminal's own color in place.
+type themeColors struct {
+	Background    string   `json:"background"`
+	Panel         string   `json:"panel"`
+	PanelBorder   string   `json:"panel_border"`
+	Text          string   `json:"text"`
+	Subtle        string   `json:"subtle"`
+	Dim           string   `json:"dim"`
+	Shade         string   `json:"shade"`
+	Tab           string   `json:"tab"`
+	Accent        string   `json:"accent"`
+	AccentText    string   `json:"accent_text"`
+	Selection     string   `json:"selection"`
+	SelectionText string   `json:"selection_text"`
+	Badge         string   `json:"badge"`
+	BadgeText     string   `json:"badge_text"`
+	User          string   `json:"user"`
+	Movie         string   `json:"movie"`
+	RateHigh      string   `json:"rate_high"`
+	RateMid       string   `json:"rate_mid"`
+	RateLow       string   `json:"rate_low"`
+	Match         string   `json:"match"`
+	Warning       string   `json:"warning"`
+	Glow          []string `json:"glow"`
+}
+
+// styleSpec overrides parts of one style. Unset fields keep the style's
+// value from the palette.
+type styleSpec struct {
+	Foreground *string `json:"foreground"`
+	Background *string `json:"background"`
+	Border     *string `json:"border"`
+	Bold       *bool   `json:"bold"`
+	Italic     *bool   `json:"italic"`
+	Underline  *bool   `json:"underline"`
+	Faint      *bool   `json:"faint"`
+	Reverse    *bool   `json:"reverse"`
+}
+
+func (s styleSpec) apply(style lipgloss.Style) lipgloss.Style {
+	if s.Foreground != nil {
+		style = style.Foreground(themeColor(*s.Foreground))
+	}
+	if s.Background != nil {
+		style = style.Background(themeColor(*s.Background))
+	}
+	if s.Border != nil {
+		style = style.BorderForeground(themeColor(*s.Border))
+	}
+	if s.Bold != nil {
+		style = style.Bold(*s.Bold)
+	}
+	if s.Italic != nil {
+		style = style.Italic(*s.Italic)
+	}
+	if s.Underline != nil {
+		style = style.Underline(*s.Underline)
+	}
+	if s.Faint != nil {
+		style = style.Faint(*s.Faint)
+	}
+	if s.Reverse != nil {
+		style = style.Reverse(*s.Reverse)
+	}
+	return style
+}
+
+type themeDef struct {
+	name   string
+	colors themeColors
+	// styles holds per-style overrides by key, including "onboarding.*"
+	// keys for the setup screens.
+	styles map[string]styleSpec
+}
+
+var (
+	darkTheme = themeDef{name: "dark", colors: themeColors{
+		Background:    "#0E1114",
+		Panel:         "#14181C",
+		PanelBorder:   "#3A4A55",
+		Text:          "#E6F0F2",
+		Subtle:        "#9BB0B8",
+		Dim:           "#7F8D96",
+		Shade:         "#5E6A72",
+		Tab:           "#C9D1D5",
+		Accent:        "#00E054",
+		AccentText:    "#14181C",
+		Selection:     "#1F2A33",
+		SelectionText: "#E6F0F2",
+		Badge:         "#2B3B45",
+		BadgeText:     "#E6F0F2",
+		User:          "#FF8C3A",
+		Movie:         "#FFFFFF",
+		RateHigh:      "#00E054",
+		RateMid:       "#F2C94C",
+		RateLow:       "#E25555",
+		Match:         "#40BCF4",
+		Warning:       "#FF8C3A",
+		Glow:          []string{"#6BFF6A", "#7BFF5A", "#8CFF4A", "#9EFF3A", "#B0FF2A"},
+	}}
+	lightTheme = themeDef{name: "light", colors: themeColors{
+		Background:    "#FFFFFF",
+		Panel:         "#F4F6F7",
+		PanelBorder:   "#B8C4CC",
+		Text:          "#14181C",
+		Subtle:        "#4F6470",
+		Dim:           "#6B7B85",
+		Shade:         "#A0ABB2",
+		Tab:           "#2B3B45",
+		Accent:        "#00A03C",
+		AccentText:    "#FFFFFF",
+		Selection:     "#DDE7EC",
+		SelectionText: "#14181C",
+		Badge:         "#E3E9ED",
+		BadgeText:     "#14181C",
+		User:          "#C85A00",
+		Movie:         "#14181C",
+		RateHigh:      "#00873A",
+		RateMid:       "#A67C00",
+		RateLow:       "#C62828",
+		Match:         "#0066CC",
+		Warning:       "#C85A00",
+		Glow:          []string{"#00873A", "#0A9440", "#14A046", "#1EAC4C", "#28B852"},
+	}}
+	highContrastTheme = themeDef{name: "high-contrast", colors: themeColors{
+		Background:    "#000000",
+		Panel:         "#000000",
+		PanelBorder:   "#FFFFFF",
+		Text:          "#FFFFFF",
+		Subtle:        "#FFFFFF",
+		Dim:           "#C0C0C0",
+		Shade:         "#808080",
+		Tab:           "#FFFFFF",
+		Accent:        "#00FF00",
+		AccentText:    "#000000",
+		Selection:     "#FFFF00",
+		SelectionText: "#000000",
+		Badge:         "#FFFFFF",
+		BadgeText:     "#000000",
+		User:          "#00FFFF",
+		Movie:         "#FFFFFF",
+		RateHigh:      "#00FF00",
+		RateMid:       "#FFFF00",
+		RateLow:       "#FF5555",
+		Match:         "#FF00FF",
+		Warning:       "#FFFF00",
+		Glow:          []string{"#00FF00"},
+	}}
+	// monoTheme sets no colors at all and leans on bold, underline and
+	// reverse video instead. It is the default when NO_COLOR is set.
+	monoTheme = themeDef{name: "mono"}
+
+	builtinThemes = []themeDef{darkTheme, lightTheme, highContrastTheme, monoTheme}
+)
+
 func newTheme() themeStyles {
-	return themeStyles{
-		header: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00E054")),
-		subtle: lipgloss.NewStyle().Foreground(lipgloss.Color("#9BB0B8")),
-		tab:    lipgloss.NewStyle().Padding(0, 1).Foreground(lipgloss.Color("#C9D1D5")),
+	return buildTheme(darkTheme)
+}
+
+func buildTheme(def themeDef) themeStyles {
+	c := def.colors
+	theme := themeStyles{
+		name:   def.name,
+		header: lipgloss.NewStyle().Bold(true).Foreground(themeColor(c.Accent)),
+		subtle: lipgloss.NewStyle().Foreground(themeColor(c.Subtle)),
+		tab:    lipgloss.NewStyle().Padding(0, 1).Foreground(themeColor(c.Tab)),
 		tabActive: lipgloss.NewStyle().
 			Padding(0, 1).
-			Foreground(lipgloss.Color("#14181C")).
-			Background(lipgloss.Color("#00E054")).
+			Foreground(themeColor(c.AccentText)).
+			Background(themeColor(c.Accent)).
 			Bold(true),
 		item:     lipgloss.NewStyle().Padding(0, 1),
-		itemSel:  lipgloss.NewStyle().Padding(0, 1).Background(lipgloss.Color("#1F2A33")).Foreground(lipgloss.Color("#E6F0F2")),
-		badge:    lipgloss.NewStyle().Padding(0, 1).Foreground(lipgloss.Color("#E6F0F2")).Background(lipgloss.Color("#2B3B45")),
-		dim:      lipgloss.NewStyle().Foreground(lipgloss.Color("#7F8D96")),
-		user:     lipgloss.NewStyle().Foreground(lipgloss.Color("#FF8C3A")).Bold(true),
-		movie:    lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true),
-		rateHigh: lipgloss.NewStyle().Foreground(lipgloss.Color("#00E054")).Bold(true),
-		rateMid:  lipgloss.NewStyle().Foreground(lipgloss.Color("#F2C94C")).Bold(true),
-		rateLow:  lipgloss.NewStyle().Foreground(lipgloss.Color("#E25555")).Bold(true),
+		itemSel:  lipgloss.NewStyle().Padding(0, 1).Background(themeColor(c.Selection)).Foreground(themeColor(c.SelectionText)),
+		badge:    lipgloss.NewStyle().Padding(0, 1).Foreground(themeColor(c.BadgeText)).Background(themeColor(c.Badge)),
+		dim:      lipgloss.NewStyle().Foreground(themeColor(c.Dim)),
+		user:     lipgloss.NewStyle().Foreground(themeColor(c.User)).Bold(true),
+		movie:    lipgloss.NewStyle().Foreground(themeColor(c.Movie)).Bold(true),
+		rateHigh: lipgloss.NewStyle().Foreground(themeColor(c.RateHigh)).Bold(true),
+		rateMid:  lipgloss.NewStyle().Foreground(themeColor(c.RateMid)).Bold(true),
+		rateLow:  lipgloss.NewStyle().Foreground(themeColor(c.RateLow)).Bold(true),
+		match:    lipgloss.NewStyle().Foreground(themeColor(c.Match)).Bold(true).Underline(true),
+		panel: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(themeColor(c.PanelBorder)).
+			Background(themeColor(c.Panel)).
+			Foreground(themeColor(c.Text)),
+		shade: lipgloss.NewStyle().Background(themeColor(c.Background)).Foreground(themeColor(c.Shade)),
+		glow:  c.Glow,
+	}
+	// Without colors, the active tab and selection would look like any other.
+	if c.Accent == "" {
+		theme.tabActive = theme.tabActive.Reverse(true)
+	}
+	if c.Selection == "" {
+		theme.itemSel = theme.itemSel.Reverse(true)
+	}
+	theme.heat = heatStyles(c.Panel, c.Badge, c.Accent)
+	named := theme.named()
+	for name, spec := range def.styles {
+		if style, ok := named[name]; ok {
+			*style = spec.apply(*style)
+		}
+	}
+	return theme
+}
+
+func themeColor(color string) lipgloss.TerminalColor {
+	if color == "" {
+		return lipgloss.NoColor{}
+	}
+	return lipgloss.Color(color)
+}
+
+// heatStyles scales accent down towards bg for the calendar heatmap levels;
+// level 0 uses empty for days with nothing logged.
+// Without hex colors to blend it falls back to faint and bold.
+func heatStyles(bg, empty, accent string) [5]lipgloss.Style {
+	if !isHex(bg) || !isHex(empty) || !isHex(accent) {
+		plain := lipgloss.NewStyle()
+		return [5]lipgloss.Style{plain.Faint(true), plain, plain, plain.Bold(true), plain.Bold(true)}
+	}
+	levels := [5]lipgloss.Style{lipgloss.NewStyle().Foreground(lipgloss.Color(empty))}
+	for i := 1; i < len(levels); i++ {
+		color := blendHex(bg, accent, 0.25+0.75*float64(i-1)/float64(len(levels)-2))
+		levels[i] = lipgloss.NewStyle().Foreground(lipgloss.Color(color))
 	}
+	return levels
+}
+
+func blendHex(from, to string, t float64) string {
+	a, b := parseHex(from), parseHex(to)
+	var out [3]int
+	for i := range out {
+		out[i] = int(float64(a[i]) + (float64(b[i])-float64(a[i]))*t + 0.5)
+	}
+	return fmt.Sprintf("#%02X%02X%02X", out[0], out[1], out[2])
+}
+
+func isHex(color string) bool {
+	color = strings.TrimPrefix(color, "#")
+	if len(color) != 6 {
+		return false
+	}
+	_, err := strconv.ParseUint(color, 16, 32)
+	return err == nil
+}
+
+func parseHex(color string) [3]int {
+	color = strings.TrimPrefix(color, "#")
+	var rgb [3]int
+	if len(color) != 6 {
+		return rgb
+	}
+	for i := range rgb {
+		v, _ := strconv.ParseUint(color[i*2:i*2+2], 16, 8)
+		rgb[i] = int(v)
+	}
+	return rgb
 }
 
 func styleRating(rating string, theme themeStyles) string {
 	value := starsToValue(rating)
 	switch {
 	case value >= 5.0:
-		return glowStars(rating)
+		return glowStars(rating, theme)
 	case value >= 4.0:
 		return theme.rateHigh.Render(rating)
 	case value >= 2.5:
@@ -77,21 +345,17 @@ func starsToValue(rating string) float64 {
 	return value
 }
 
-func glowStars(rating string) string {
-	gradient := []string{
-		"#6BFF6A",
-		"#7BFF5A",
-		"#8CFF4A",
-		"#9EFF3A",
-		"#B0FF2A",
+func glowStars(rating string, theme themeStyles) string {
+	if len(theme.glow) == 0 {
+		return theme.rateHigh.Render(rating)
 	}
 	var out strings.Builder
 	colorIndex := 0
 	for _, r := range rating {
 		switch r {
 		case '★', '½':
-			color := gradient[min(colorIndex, len(gradient)-1)]
-			out.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true).Render(string(r)))
+			color := theme.glow[min(colorIndex, len(theme.glow)-1)]
+			out.WriteString(lipgloss.NewStyle().Foreground(themeColor(color)).Bold(true).Render(string(r)))
 			colorIndex++
 		default:
 			out.WriteRune(r)
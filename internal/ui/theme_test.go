@@ -1,6 +1,14 @@
 package ui
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 func TestStarsToValue(t *testing.T) {
 	if starsToValue("") != 0 {
@@ -12,7 +20,7 @@ func TestStarsToValue(t *testing.T) {
 }
 
 func TestGlowStars(t *testing.T) {
-	out := glowStars("★★½")
+	out := glowStars("★★½", newTheme())
 	if stripANSI(out) != "★★½" {
 		t.Fatalf("unexpected glowStars output: %q", stripANSI(out))
 	}
@@ -28,3 +36,103 @@ func TestStyleRating(t *testing.T) {
 		t.Fatalf("expected empty output for empty rating")
 	}
 }
+
+func TestBlendHex(t *testing.T) {
+	if got := blendHex("#000000", "#00E054", 1); got != "#00E054" {
+		t.Fatalf("unexpected full blend: %s", got)
+	}
+	if got := blendHex("#000000", "#FF8040", 0.5); got != "#804020" {
+		t.Fatalf("unexpected half blend: %s", got)
+	}
+}
+
+func TestLoadThemesReadsUserThemes(t *testing.T) {
+	dir := t.TempDir()
+	data := `{"base": "light", "colors": {"accent": "#123456", "glow": ["#ABCDEF"]}, "styles": {"movie": {"foreground": "#654321"}, "onboarding.title": {"underline": true}}}`
+	if err := os.WriteFile(filepath.Join(dir, "Mine.json"), []byte(data), 0600); err != nil {
+		t.Fatalf("write theme: %v", err)
+	}
+	themes, err := LoadThemes(dir, "mine")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if themes.Name() != "mine" || !slices.Contains(themes.names(), "high-contrast") {
+		t.Fatalf("unexpected themes %q selected %q", themes.names(), themes.Name())
+	}
+	def := themes.selected()
+	if def.colors.Accent != "#123456" || def.colors.Text != lightTheme.colors.Text || !slices.Equal(def.colors.Glow, []string{"#ABCDEF"}) {
+		t.Fatalf("expected colors over the light base, got %+v", def.colors)
+	}
+	theme := buildTheme(def)
+	if theme.movie.GetForeground() != lipgloss.Color("#654321") || !theme.movie.GetBold() {
+		t.Fatalf("expected movie override to keep bold")
+	}
+	if !newOnboardingStyles(def).title.GetUnderline() {
+		t.Fatalf("expected onboarding override")
+	}
+	if slices.Equal(lightTheme.colors.Glow, def.colors.Glow) {
+		t.Fatalf("expected the built-in theme to be left alone")
+	}
+}
+
+func TestLoadThemesRejectsBadThemes(t *testing.T) {
+	if _, err := LoadThemes("", "neon"); err == nil || !strings.Contains(err.Error(), "dark, light, high-contrast, mono") {
+		t.Fatalf("expected unknown theme error, got %v", err)
+	}
+	for _, data := range []string{`{"styles": {"title": {}}}`, `{"colors": {"accnet": "#000000"}}`, `{"base": "neon"}`} {
+		if _, err := parseThemeFile("bad", []byte(data)); err == nil {
+			t.Fatalf("expected %s to be rejected", data)
+		}
+	}
+}
+
+func TestAutoThemeFollowsBackgroundAndNoColor(t *testing.T) {
+	dark := false
+	restore := hasDarkBackground
+	hasDarkBackground = func() bool { return dark }
+	defer func() { hasDarkBackground = restore }()
+
+	t.Setenv("NO_COLOR", "")
+	if themes, _ := LoadThemes("", "auto"); themes.Name() != "light" {
+		t.Fatalf("expected light on a light terminal, got %q", themes.Name())
+	}
+	dark = true
+	if themes, _ := LoadThemes("", ""); themes.Name() != "dark" {
+		t.Fatalf("expected dark on a dark terminal, got %q", themes.Name())
+	}
+	t.Setenv("NO_COLOR", "1")
+	if themes, _ := LoadThemes("", ""); themes.Name() != "mono" {
+		t.Fatalf("expected mono with NO_COLOR, got %q", themes.Name())
+	}
+}
+
+func TestMonoThemeHasNoColors(t *testing.T) {
+	theme := buildTheme(monoTheme)
+	out := styleRating("★★★★★", theme) + theme.heat[4].Render("■") + theme.tabActive.Render("Diary")
+	if strings.Contains(out, "38;") || strings.Contains(out, "48;") {
+		t.Fatalf("expected no colors in mono output: %q", out)
+	}
+	if !theme.tabActive.GetReverse() {
+		t.Fatalf("expected the active tab to stand out without colors")
+	}
+}
+
+func TestThemeKeyCyclesThemes(t *testing.T) {
+	themes, err := LoadThemes("", "dark")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m := NewModel("jane", nil).WithThemes(themes)
+	m = typeKeys(m, "T")
+	if m.theme.name != "light" {
+		t.Fatalf("expected T to switch to light, got %q", m.theme.name)
+	}
+	titles := strings.Join(paletteTitles(m), "\n")
+	if !strings.Contains(titles, "Theme: dark") || strings.Contains(titles, "Theme: light") {
+		t.Fatalf("expected palette to offer the other themes:\n%s", titles)
+	}
+	m = typeKeys(m, "TTT")
+	if m.theme.name != "dark" {
+		t.Fatalf("expected themes to wrap, got %q", m.theme.name)
+	}
+}
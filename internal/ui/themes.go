@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Themes holds the built-in themes, any user themes, and which one is in use.
+// The zero value is the built-in themes with dark selected.
+type Themes struct {
+	list    []themeDef
+	current int
+}
+
+// hasDarkBackground is swapped out in tests.
+var hasDarkBackground = lipgloss.HasDarkBackground
+
+// themeFile is the JSON layout of a user theme. Colors and styles are
+// applied on top of the base theme, dark unless set.
+type themeFile struct {
+	Base   string               `json:"base"`
+	Colors json.RawMessage      `json:"colors"`
+	Styles map[string]styleSpec `json:"styles"`
+}
+
+// LoadThemes reads user themes from the *.json files in dir, each named after
+// its file, and selects name among them and the built-in themes. A user theme
+// named like a built-in one replaces it. An empty name or "auto" picks mono
+// when NO_COLOR is set, otherwise dark or light to suit the terminal.
+func LoadThemes(dir, name string) (Themes, error) {
+	themes := Themes{list: slices.Clone(builtinThemes)}
+	if dir != "" {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			return themes, err
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			def, err := loadThemeFile(path)
+			if err != nil {
+				return themes, err
+			}
+			themes.add(def)
+		}
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == "auto" {
+		name = autoTheme()
+	}
+	index := themes.index(name)
+	if index < 0 {
+		return themes, fmt.Errorf("theme: unknown theme %q (have %s)", name, strings.Join(themes.names(), ", "))
+	}
+	themes.current = index
+	return themes, nil
+}
+
+func autoTheme() string {
+	if os.Getenv("NO_COLOR") != "" {
+		return monoTheme.name
+	}
+	if hasDarkBackground() {
+		return darkTheme.name
+	}
+	return lightTheme.name
+}
+
+func loadThemeFile(path string) (themeDef, error) {
+	name := strings.ToLower(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return themeDef{}, err
+	}
+	def, err := parseThemeFile(name, data)
+	if err != nil {
+		return themeDef{}, fmt.Errorf("theme %s: %w", path, err)
+	}
+	return def, nil
+}
+
+func parseThemeFile(name string, data []byte) (themeDef, error) {
+	var file themeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return themeDef{}, err
+	}
+	baseName := strings.ToLower(strings.TrimSpace(file.Base))
+	if baseName == "" {
+		baseName = darkTheme.name
+	}
+	base := Themes{list: builtinThemes}.index(baseName)
+	if base < 0 {
+		return themeDef{}, fmt.Errorf("unknown base theme %q", file.Base)
+	}
+	def := builtinThemes[base]
+	def.name = name
+	def.colors.Glow = slices.Clone(def.colors.Glow)
+	if len(file.Colors) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(file.Colors))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&def.colors); err != nil {
+			return themeDef{}, fmt.Errorf("colors: %w", err)
+		}
+	}
+	def.styles = maps.Clone(def.styles)
+	if def.styles == nil {
+		def.styles = map[string]styleSpec{}
+	}
+	for key, spec := range file.Styles {
+		if !knownStyleKey(key) {
+			return themeDef{}, fmt.Errorf("styles: unknown style %q", key)
+		}
+		def.styles[key] = spec
+	}
+	return def, nil
+}
+
+func knownStyleKey(key string) bool {
+	if rest, ok := strings.CutPrefix(key, "onboarding."); ok {
+		var styles onboardingStyles
+		_, found := styles.named()[rest]
+		return found
+	}
+	var theme themeStyles
+	_, found := theme.named()[key]
+	return found
+}
+
+func (t *Themes) add(def themeDef) {
+	if index := t.index(def.name); index >= 0 {
+		t.list[index] = def
+		return
+	}
+	t.list = append(t.list, def)
+}
+
+func (t Themes) index(name string) int {
+	return slices.IndexFunc(t.all(), func(def themeDef) bool { return def.name == name })
+}
+
+func (t Themes) all() []themeDef {
+	if len(t.list) == 0 {
+		return builtinThemes
+	}
+	return t.list
+}
+
+func (t Themes) names() []string {
+	var names []string
+	for _, def := range t.all() {
+		names = append(names, def.name)
+	}
+	return names
+}
+
+func (t Themes) selected() themeDef {
+	return t.all()[t.current]
+}
+
+func (t Themes) Name() string {
+	return t.selected().name
+}
+
+func (m Model) WithThemes(themes Themes) Model {
+	m.themes = themes
+	m.theme = buildTheme(themes.selected())
+	return m
+}
+
+func (m *Model) setTheme(index int) {
+	m.themes.list = m.themes.all()
+	m.themes.current = index
+	m.theme = buildTheme(m.themes.selected())
+	m.resizeViewport()
+	m.refreshViewport()
+	if m.modalOpen() {
+		m.refreshModalViewport()
+	}
+}
+
+func (m *Model) cycleTheme() {
+	m.setTheme((m.themes.current + 1) % len(m.themes.all()))
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -10,11 +11,12 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/solean/letterboxd-tui/internal/config"
 	"github.com/solean/letterboxd-tui/internal/letterboxd"
-	"github.com/solean/letterboxd-tui/internal/logging"
+	"github.com/solean/letterboxd-tui/internal/store"
 )
 
-func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if ws, ok := msg.(tea.WindowSizeMsg); ok {
 		m.width = ws.Width
 		m.height = ws.Height
@@ -40,6 +42,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 		return m, nil
+	case sessionMsg:
+		m.applySession(sm)
+		return m, nil
+	case exportedMsg:
+		m.applyExported(sm)
+		return m, nil
+	case debugEventMsg:
+		m.recordDebugEvent(letterboxd.Event(sm))
+		return m, waitDebugEventCmd(m.debugEvents, m.debugStop)
 	}
 
 	if rm, ok := msg.(reviewsMsg); ok {
@@ -99,63 +110,73 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.maybeLoadMoreReviewsCmd()
 	}
 
+	if mouse, ok := msg.(tea.MouseMsg); ok {
+		return m.updateMouse(mouse)
+	}
+
 	if m.cookieModal {
 		return m.updateCookieModal(msg)
 	}
 
+	if km, ok := msg.(tea.KeyMsg); ok && m.debugPanel {
+		return m.updateDebugPanel(km)
+	}
+
 	if m.logModal {
 		return m.updateLogModal(msg)
 	}
 
+	// Only keys go to the form and the palette; results that arrive
+	// meanwhile, like a finished replay, are handled as usual.
+	if km, ok := msg.(tea.KeyMsg); ok && m.filterModal {
+		return m.updateFilterModal(km)
+	}
+	if km, ok := msg.(tea.KeyMsg); ok && m.paletteModal {
+		return m.updatePalette(km)
+	}
+
 	switch ev := msg.(type) {
 	case tea.KeyMsg:
+		if cmd, handled := m.handleFindKey(ev); handled {
+			return m, cmd
+		}
 		if cmd, handled := m.handleSearchKey(ev); handled {
 			return m, cmd
 		}
+		if key.Matches(ev, m.keys.Palette) {
+			return m.openPalette()
+		}
+		if cmd, handled := m.handleHeatmapKey(ev); handled {
+			return m, cmd
+		}
+		if cmd, handled := m.handleQueueKey(ev); handled {
+			return m, cmd
+		}
+		if model, cmd, ok := m.runKeyCommand(ev); ok {
+			return model, cmd
+		}
 		switch {
 		case key.Matches(ev, m.keys.QuitAll):
 			return m, tea.Quit
-		case key.Matches(ev, m.keys.Help):
-			m.help.ShowAll = !m.help.ShowAll
-			m.resizeViewport()
-			return m, nil
 		case m.handleJumpKeys(ev):
 			return m, nil
 		case m.modalOpen() && key.Matches(ev, m.keys.ModalBack):
 			if m.activeTab == tabFilm {
-				m.activeTab = m.filmReturn
-				m.resetTabPosition()
-				if m.filmReturnProfileModal {
-					m.profileModal = true
-					m.modalVP.YOffset = m.modalReturnYOffset
-					m.filmReturnProfileModal = false
-					m.refreshModalViewport()
-					m.resizeViewport()
-				}
+				return m.closeFilm()
 			} else if m.profileModal {
 				m.profileModal = false
 				m.resizeViewport()
 			}
 			return m, nil
-		case key.Matches(ev, m.keys.SearchTab):
-			if m.profileModal {
-				m.profileModal = false
-				m.resizeViewport()
-			}
-			if m.activeTab != tabSearch {
-				m.activeTab = tabSearch
-				m.resetTabPosition()
-				return m, nil
-			}
-			m.searchFocusInput = true
-			m.searchInput.Focus()
-			m.resizeViewport()
-			return m, nil
-		case key.Matches(ev, m.keys.Quit):
-			return m, tea.Quit
 		case key.Matches(ev, m.keys.NextTab):
 			if m.activeTab == tabFilm {
-				m.activeTab = m.filmReturn
+				m.activeTab = m.filmBase()
+			} else if m.activeTab == tabPeople {
+				m.activeTab = m.peopleReturn
+			} else if m.activeTab == tabCompare {
+				m.activeTab = m.compareReturn
+			} else if m.activeTab == tabQueue {
+				m.activeTab = m.queueReturn
 			} else {
 				m.activeTab = nextTab(m, m.activeTab)
 			}
@@ -163,7 +184,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.maybeFillCmd()
 		case key.Matches(ev, m.keys.PrevTab):
 			if m.activeTab == tabFilm {
-				m.activeTab = m.filmReturn
+				m.activeTab = m.filmBase()
+			} else if m.activeTab == tabPeople {
+				m.activeTab = m.peopleReturn
+			} else if m.activeTab == tabCompare {
+				m.activeTab = m.compareReturn
+			} else if m.activeTab == tabQueue {
+				m.activeTab = m.queueReturn
 			} else {
 				m.activeTab = prevTab(m, m.activeTab)
 			}
@@ -267,42 +294,45 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.syncViewportToSelection()
 				return m, m.maybeLoadMoreCmd()
 			}
-		case key.Matches(ev, m.keys.Refresh):
-			m.loading = true
-			m.resetPagination()
-			cmds := []tea.Cmd{
-				fetchProfileCmd(m.client, m.profileUser),
-				fetchDiaryCmd(m.client, m.username, 1, m.diarySortParam()),
-				fetchWatchlistCmd(m.client, m.username, 1, m.watchlistSortParam()),
-				fetchActivityCmd(m.client, m.username, tabActivity, ""),
-			}
-			if m.hasCookie() {
-				cmds = append(cmds, fetchActivityCmd(m.client, m.username, tabFollowing, ""))
-			}
-			return m, tea.Batch(cmds...)
 		case key.Matches(ev, m.keys.Sort):
 			switch m.activeTab {
 			case tabDiary:
 				m.diarySort = m.diarySort.next()
-				m.resetDiaryList()
-				return m, fetchDiaryCmd(m.client, m.username, 1, m.diarySortParam())
 			case tabWatchlist:
 				m.watchlistSort = m.watchlistSort.next()
-				m.resetWatchlist()
-				return m, fetchWatchlistCmd(m.client, m.username, 1, m.watchlistSortParam())
+			case tabFilms:
+				m.filmsSort = m.filmsSort.next()
+			default:
+				return m, nil
 			}
+			return m.resortList()
 		case key.Matches(ev, m.keys.Select):
 			if m.profileModal {
+				if entry, ok := m.selectedModalProfileEntry(); ok && entry.peopleKind != "" {
+					m.profileModal = false
+					m = m.openPeople(entry.peopleUser, entry.peopleKind)
+					return m, fetchPeopleCmd(m.client, m.peopleUser, m.peopleKind, 1)
+				}
 				m = m.openSelectedModalFilm()
 				if m.activeTab == tabFilm {
 					return m, fetchFilmCmd(m.client, m.film.URL, m.username)
 				}
 				return m, nil
 			}
-			if m.activeTab == tabFollowing {
+			if m.activeTab == tabFollowing || m.activeTab == tabPeople {
 				m = m.openSelectedProfile()
+				if !m.profileModal {
+					return m, nil
+				}
 				return m, fetchProfileModalCmd(m.client, m.modalUser)
-			} else if m.activeTab == tabProfile || m.activeTab == tabDiary || m.activeTab == tabWatchlist || m.activeTab == tabActivity {
+			}
+			if m.activeTab == tabProfile {
+				if entry, ok := m.selectedProfileEntry(); ok && entry.peopleKind != "" {
+					m = m.openPeople(entry.peopleUser, entry.peopleKind)
+					return m, fetchPeopleCmd(m.client, m.peopleUser, m.peopleKind, 1)
+				}
+			}
+			if m.activeTab == tabProfile || m.activeTab == tabDiary || m.activeTab == tabFilms || m.activeTab == tabWatchlist || m.activeTab == tabActivity {
 				m = m.openSelectedFilm()
 				if m.activeTab == tabFilm {
 					return m, fetchFilmCmd(m.client, m.film.URL, m.username)
@@ -311,73 +341,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(ev, m.keys.Back):
 			if m.profileModal {
 				m.profileModal = false
-			} else if m.activeTab == tabProfile {
-				m = m.goBackProfile()
-				if m.activeTab == tabProfile {
-					return m, fetchProfileCmd(m.client, m.profileUser)
-				}
-			}
-		case key.Matches(ev, m.keys.Log):
-			if m.activeTab == tabFilm && m.hasCookie() {
-				m = m.startLogModal()
-				return m, m.logSpinner.Tick
-			}
-		case key.Matches(ev, m.keys.WatchlistAdd):
-			if m.activeTab == tabFilm && m.hasCookie() {
-				if m.watchlistPending {
-					return m, nil
-				}
-				if inWatchlist, ok := m.watchlistState(); ok && inWatchlist {
-					return m, nil
-				}
-				req, err := m.buildWatchlistRequest()
-				if err != nil {
-					logging.LogError("watchlist request add", err)
-					m.watchlistStatus = "Error: " + err.Error()
-					return m, nil
-				}
-				m.watchlistPending = true
-				m.watchlistStatus = "Adding to watchlist..."
-				return m, setWatchlistCmd(m.client, req, true)
-			}
-		case key.Matches(ev, m.keys.WatchlistRemove):
-			if m.activeTab == tabFilm && m.hasCookie() {
-				if m.watchlistPending {
-					return m, nil
-				}
-				inWatchlist, ok := m.watchlistState()
-				if !ok || !inWatchlist {
-					return m, nil
-				}
-				req, err := m.buildWatchlistRequest()
-				if err != nil {
-					logging.LogError("watchlist request remove", err)
-					m.watchlistStatus = "Error: " + err.Error()
-					return m, nil
-				}
-				m.watchlistPending = true
-				m.watchlistStatus = "Removing from watchlist..."
-				return m, setWatchlistCmd(m.client, req, false)
-			}
-		case key.Matches(ev, m.keys.Open):
-			if m.profileModal {
-				return m, openBrowserCmd(letterboxd.ProfileURL(m.modalUser))
-			} else if m.activeTab == tabProfile {
-				return m, openBrowserCmd(letterboxd.ProfileURL(m.profileUser))
-			} else if m.activeTab == tabFilm {
-				return m, openBrowserCmd(m.film.URL)
+			} else if m.activeTab == tabPeople {
+				m = m.closePeople()
+			} else if m.activeTab == tabCompare {
+				m = m.closeCompare()
 			}
 		case key.Matches(ev, m.keys.Cancel):
-			if m.activeTab == tabFilm {
-				m.activeTab = m.filmReturn
-				m.resetTabPosition()
-				if m.filmReturnProfileModal {
-					m.profileModal = true
-					m.modalVP.YOffset = m.modalReturnYOffset
-					m.filmReturnProfileModal = false
-					m.refreshModalViewport()
-					m.resizeViewport()
-				}
+			if m.activeTab == tabPeople && !m.profileModal {
+				m = m.closePeople()
+			} else if m.activeTab == tabCompare {
+				m = m.closeCompare()
+			} else if m.activeTab == tabFilm {
+				return m.closeFilm()
 			} else if m.profileModal {
 				m.profileModal = false
 				m.resizeViewport()
@@ -398,14 +373,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.profileList.selected = 0
 		}
 	case diaryMsg:
-		if ev.sort != m.diarySortParam() {
+		var cmd tea.Cmd
+		if ev.sort != m.diarySortParam() || m.diaryRangeActive() {
 			return m, nil
 		}
 		if ev.page <= 1 {
+			if ev.err == nil && m.storesDiary() {
+				m.offline = false
+				cmd = saveStoreCmd(m.store, func(s *store.Store) { s.MergeDiary(ev.items) })
+			}
+			cached := ev.err != nil && m.storesDiary() && m.useOffline("diary fetch", ev.err, len(m.store.Snapshot().Diary))
+			if cached {
+				ev.items, ev.err = m.store.Snapshot().Diary, nil
+			}
 			m.diary = ev.items
 			m.diaryErr = m.logAndSanitize("diary fetch", ev.err)
 			m.diaryPage = max(1, ev.page)
-			m.diaryDone = ev.err == nil && len(ev.items) == 0
+			m.diaryDone = cached || (ev.err == nil && len(ev.items) == 0)
 			m.diaryLoadingMore = false
 			m.diaryMoreErr = nil
 			m.loading = false
@@ -428,17 +412,40 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.diaryPage = ev.page
 			}
 		}
-		return m, m.maybeFillCmd()
+		return m, tea.Batch(cmd, m.maybeFillCmd())
+	case diaryRangeMsg:
+		if !ev.from.Equal(m.diaryFrom) || !ev.to.Equal(m.diaryTo) {
+			return m, nil
+		}
+		m.diary = ev.items
+		m.diaryErr = m.logAndSanitize("diary range fetch", ev.err)
+		m.diaryPage = 1
+		m.diaryDone = true
+		m.loading = false
+		m.diaryList.selected = 0
+		if m.activeTab == tabDiary {
+			m.viewport.YOffset = 0
+		}
+		return m, nil
 	case watchlistMsg:
-		if ev.sort != m.watchlistSortParam() {
+		var cmd tea.Cmd
+		if ev.filter != m.watchlistQuery() {
 			return m, nil
 		}
 		if ev.page <= 1 {
+			if ev.err == nil && m.storesWatchlist() {
+				m.offline = false
+				cmd = saveStoreCmd(m.store, func(s *store.Store) { s.MergeWatchlist(ev.items) })
+			}
+			cached := ev.err != nil && m.storesWatchlist() && m.useOffline("watchlist fetch", ev.err, len(m.store.Snapshot().Watchlist))
+			if cached {
+				ev.items, ev.err = m.store.Snapshot().Watchlist, nil
+			}
 			m.watchlist = ev.items
 			m.watchErr = m.logAndSanitize("watchlist fetch", ev.err)
 			m.watchlistLoaded = true
 			m.watchPage = max(1, ev.page)
-			m.watchDone = ev.err == nil && len(ev.items) == 0
+			m.watchDone = cached || (ev.err == nil && len(ev.items) == 0)
 			m.watchLoadingMore = false
 			m.watchMoreErr = nil
 			m.loading = false
@@ -461,20 +468,180 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.watchPage = ev.page
 			}
 		}
-		return m, m.maybeFillCmd()
+		return m, tea.Batch(cmd, m.maybeFillCmd())
+	case userFilmsMsg:
+		var cmd tea.Cmd
+		if ev.filter != m.filmsQuery() {
+			return m, nil
+		}
+		if ev.page <= 1 {
+			if ev.err == nil && m.storesFilms() {
+				m.offline = false
+				cmd = saveStoreCmd(m.store, func(s *store.Store) { s.MergeFilms(ev.items) })
+			}
+			cached := ev.err != nil && m.storesFilms() && m.useOffline("films fetch", ev.err, len(m.store.Snapshot().Films))
+			if cached {
+				ev.items, ev.err = m.store.Snapshot().Films, nil
+			}
+			m.films = ev.items
+			m.filmsErr = m.logAndSanitize("films fetch", ev.err)
+			m.filmsPage = max(1, ev.page)
+			m.filmsDone = cached || (ev.err == nil && len(ev.items) == 0)
+			m.filmsLoadingMore = false
+			m.filmsMoreErr = nil
+			m.loading = false
+			m.filmsList.selected = 0
+			if m.activeTab == tabFilms {
+				m.viewport.YOffset = 0
+			}
+		} else {
+			m.filmsLoadingMore = false
+			if ev.err != nil {
+				m.filmsMoreErr = m.logAndSanitize("films fetch more", ev.err)
+				return m, nil
+			}
+			m.filmsMoreErr = nil
+			var added int
+			m.films, added = appendUserFilms(m.films, ev.items)
+			if added == 0 {
+				m.filmsDone = true
+			} else {
+				m.filmsPage = ev.page
+			}
+		}
+		return m, tea.Batch(cmd, m.maybeFillCmd())
 	case filmMsg:
 		m.film = ev.film
 		m.filmErr = m.logAndSanitize("film fetch", ev.err)
+		if ev.err == nil && ev.film.URL != "" {
+			m.rememberTarget(jumpTarget{title: filmLabel(ev.film.Title, ev.film.Year), filmURL: ev.film.URL})
+		}
 		m.loading = false
 		m.refreshModalViewport()
+		cmds := []tea.Cmd{m.posterCmd()}
+		if ev.err == nil && m.store != nil {
+			// Saved details let the list filter match the film's cast.
+			film := ev.film
+			cmds = append(cmds, saveStoreCmd(m.store, func(s *store.Store) { s.PutFilm(film) }))
+		}
 		if ev.film.Slug != "" {
-			cmds := []tea.Cmd{fetchReviewsCmd(m.client, ev.film.Slug, m.username, "popular", 1)}
+			cmds = append(cmds, fetchReviewsCmd(m.client, ev.film.Slug, m.username, "popular", 1))
 			if m.hasCookie() {
 				cmds = append(cmds, fetchReviewsCmd(m.client, ev.film.Slug, m.username, "friends", 1))
 			}
-			return m, tea.Batch(cmds...)
 		}
+		return m, tea.Batch(cmds...)
+	case posterMsg:
+		if ev.err != nil {
+			m.logAndSanitize("poster fetch", ev.err)
+			return m, nil
+		}
+		m.poster = posterState{url: ev.url, img: ev.img}
+		m.refreshModalViewport()
+	case allDiaryMsg:
+		m.allDiaryLoading = false
+		m.allDiaryErr = m.logAndSanitize("full diary", ev.err)
+		if ev.err != nil {
+			return m, nil
+		}
+		m.allDiary = ev.entries
+		m.allDiaryLoaded = true
+		m.allDiaryTruncated = ev.truncated
+		if ev.offline {
+			m.offline = true
+		}
+		m.statsFilms = map[string]letterboxd.Film{}
+		m.statsEnrichErr = nil
+		if m.activeTab != tabStats {
+			return m, nil
+		}
+		return m, m.statsEnrichCmd()
+	case statsFilmsMsg:
+		m.statsEnriching = false
+		if m.statsFilms == nil {
+			return m, nil
+		}
+		if ev.err != nil {
+			m.statsEnrichErr = m.logAndSanitize("stats films", ev.err)
+			return m, nil
+		}
+		for i, filmURL := range ev.urls {
+			m.statsFilms[filmURL] = ev.films[i]
+		}
+		if m.store != nil {
+			films := ev.films
+			return m, tea.Batch(saveStoreCmd(m.store, func(s *store.Store) {
+				for _, film := range films {
+					s.PutFilm(film)
+				}
+			}), m.statsEnrichCmd())
+		}
+		return m, m.statsEnrichCmd()
+	case queueSavedMsg:
+		if ev.err != nil {
+			m.logError("queue save", ev.err)
+		}
+		return m, nil
+	case queueRetryMsg:
+		m.queueRetryPending = false
+		return m, m.replayQueueCmd()
+	case queueReplayedMsg:
+		return m.finishReplay(ev)
+	case storeSavedMsg:
+		if ev.err != nil {
+			m.logError("store save", ev.err)
+		}
+		return m, nil
+	case compareMsg:
+		if ev.userA != m.compareA || ev.userB != m.compareB {
+			return m, nil
+		}
+		m.comparison = ev.cmp
+		m.compareErr = m.logAndSanitize("compare", ev.err)
+		m.compareLoading = false
+		return m, nil
+	case rouletteMsg:
+		if !m.roulettePending || ev.opts != m.rouletteOpts {
+			return m, nil
+		}
+		if ev.more {
+			m.rouletteStatus = fmt.Sprintf("Rolling... checked %d of %d", ev.checked, ev.checked+len(ev.order))
+			if m.activeTab == tabFilm {
+				m.watchlistStatus = m.rouletteStatus
+				m.refreshModalViewport()
+			}
+			return m, rouletteContinueCmd(m.client, m.store, m.username, ev)
+		}
+		m.roulettePending = false
+		m.roulettePool, m.roulettePoolCut = ev.pool, ev.poolCut
+		if ev.err != nil {
+			err := m.logAndSanitize("watchlist roulette", ev.err)
+			m.rouletteStatus = "Error: " + errorText(err)
+			if ev.poolCut {
+				m.rouletteStatus += " " + rouletteCutNote
+			}
+			if m.activeTab == tabFilm {
+				m.watchlistStatus = m.rouletteStatus
+				m.refreshModalViewport()
+			}
+			return m, nil
+		}
+		m.rouletteStatus = ""
+		m = m.openFilm(ev.item.FilmURL)
+		m.rouletteActive = true
+		if ev.poolCut {
+			m.rouletteStatus = "Picked " + rouletteCutNote
+			m.watchlistStatus = m.rouletteStatus
+		}
+		if ev.film.URL != "" {
+			film := ev.film
+			return m, func() tea.Msg { return filmMsg{film: film} }
+		}
+		return m, fetchFilmCmd(m.client, m.film.URL, m.username)
 	case searchMsg:
+		if ev.err != nil && m.useOffline("search", ev.err, len(m.searchResults)) {
+			ev.results, ev.err = m.searchResults, nil
+		}
 		m.searchResults = ev.results
 		m.searchErr = m.logAndSanitize("search", ev.err)
 		m.searchLoading = false
@@ -525,21 +692,77 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		return m, m.maybeFillCmd()
+	case peopleMsg:
+		if ev.user != m.peopleUser || ev.kind != m.peopleKind {
+			return m, nil
+		}
+		if ev.page <= 1 {
+			m.people = ev.items
+			m.peopleErr = m.logAndSanitize("people fetch", ev.err)
+			m.peoplePage = max(1, ev.page)
+			m.peopleDone = ev.err == nil && len(ev.items) == 0
+			m.peopleLoadingMore = false
+			m.peopleMoreErr = nil
+			m.loading = false
+			m.peopleList.selected = 0
+			if m.activeTab == tabPeople {
+				m.viewport.YOffset = 0
+			}
+		} else {
+			m.peopleLoadingMore = false
+			if ev.err != nil {
+				m.peopleMoreErr = m.logAndSanitize("people fetch more", ev.err)
+				return m, nil
+			}
+			m.peopleMoreErr = nil
+			var added int
+			m.people, added = appendMembers(m.people, ev.items)
+			if added == 0 {
+				m.peopleDone = true
+			} else {
+				m.peoplePage = ev.page
+			}
+		}
+		return m, m.maybeFillCmd()
+	case followResultMsg:
+		m.followPending = false
+		if ev.err != nil {
+			err := m.logAndSanitize("follow update", ev.err)
+			m.followStatus = "Error: " + errorText(err)
+			m.refreshModalViewport()
+			return m, nil
+		}
+		m.applyFollowState(ev.username, ev.follow)
+		if ev.follow {
+			m.followStatus = "Followed @" + ev.username + "."
+		} else {
+			m.followStatus = "Unfollowed @" + ev.username + "."
+		}
+		m.refreshModalViewport()
 	case errMsg:
 		m.diaryErr = m.logAndSanitize("activity tab", ev.err)
 		m.loading = false
 	case openMsg:
 		if ev.err != nil {
-			logging.LogError("open browser", ev.err)
+			m.logError("open browser", ev.err)
 			m.profileErr = ev.err
 		}
 	case watchlistResultMsg:
 		m.watchlistPending = false
 		if ev.err != nil {
 			err := m.logAndSanitize("watchlist update", ev.err)
-			m.watchlistStatus = "Error: " + err.Error()
+			if cmd, ok := m.queueWrite(store.NewWatchlistOp(ev.title, ev.filmURL, ev.req, ev.inWatchlist), ev.err); ok {
+				m.watchlistStatus = "Letterboxd unreachable; queued and will retry automatically."
+				m.refreshModalViewport()
+				return m, cmd
+			}
+			m.watchlistStatus = "Error: " + errorText(err)
 			return m, nil
 		}
+		m.roulettePool = nil
+		if letterboxd.NormalizeFilmURL(ev.filmURL) != letterboxd.NormalizeFilmURL(m.film.URL) {
+			return m, fetchWatchlistCmd(m.client, m.username, 1, m.watchlistQuery())
+		}
 		m.film.InWatchlist = ev.inWatchlist
 		m.film.WatchlistOK = true
 		if ev.inWatchlist {
@@ -551,7 +774,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = true
 		return m, tea.Batch(
 			fetchFilmCmd(m.client, m.film.URL, m.username),
-			fetchWatchlistCmd(m.client, m.username, 1, m.watchlistSortParam()),
+			fetchWatchlistCmd(m.client, m.username, 1, m.watchlistQuery()),
 		)
 	}
 	return m, nil
@@ -567,7 +790,7 @@ func (m *Model) refreshModalViewport() {
 	if !m.profileModal && m.activeTab != tabFilm {
 		return
 	}
-	theme := newTheme()
+	theme := m.theme
 	width, height := modalDimensions(m.width, m.height)
 	innerWidth := width - 4
 	innerHeight := height - 2
@@ -579,17 +802,18 @@ func (m *Model) refreshModalViewport() {
 
 	if m.profileModal {
 		selected := m.modalProfileSelectedIndex()
-		content := renderProfileContent(m.modalProfile, m.modalProfileErr, m.modalLoading, m.modalUser, nil, selected, innerWidth, theme)
+		content := renderProfileContent(m.modalProfile, m.modalProfileErr, m.modalLoading, m.modalUser, nil, m.modalProfileNote(), selected, innerWidth, theme)
 		m.modalVP.SetContent(content)
 		return
 	}
+	m.layoutPoster()
 	content := renderFilm(*m, theme)
 	m.modalVP.SetContent(content)
 }
 
 func (m *Model) logAndSanitize(context string, err error) error {
 	if err != nil {
-		logging.LogError(context, err)
+		m.logError(context, err)
 	}
 	return m.sanitizeCloudflare(err)
 }
@@ -609,7 +833,8 @@ func (m *Model) handleSearchKey(msg tea.KeyMsg) (tea.Cmd, bool) {
 			}
 			m.searchLoading = true
 			m.searchErr = nil
-			m.searchResults = nil
+			m.searchResults = m.storedSearch(query)
+			m.searchList.selected = 0
 			m.searchFocusInput = false
 			m.searchInput.Blur()
 			m.resizeViewport()
@@ -621,7 +846,7 @@ func (m *Model) handleSearchKey(msg tea.KeyMsg) (tea.Cmd, bool) {
 			return fetchFilmCmd(m.client, m.film.URL, m.username), true
 		}
 		return nil, true
-	case key.Matches(msg, m.keys.SearchTab):
+	case !m.searchFocusInput && key.Matches(msg, m.keys.SearchTab):
 		m.searchFocusInput = true
 		m.searchInput.Focus()
 		m.resizeViewport()
@@ -669,6 +894,87 @@ func (m *Model) handleSearchKey(msg tea.KeyMsg) (tea.Cmd, bool) {
 	return nil, false
 }
 
+func (m Model) refreshAll() (tea.Model, tea.Cmd) {
+	m.loading = true
+	m.resetPagination()
+	cmds := []tea.Cmd{
+		fetchProfileCmd(m.client, m.profileUser),
+		m.diaryFetchCmd(),
+		fetchWatchlistCmd(m.client, m.username, 1, m.watchlistQuery()),
+		fetchUserFilmsCmd(m.client, m.username, m.filmsQuery(), 1),
+		fetchActivityCmd(m.client, m.username, tabActivity, ""),
+	}
+	if m.hasCookie() {
+		cmds = append(cmds, fetchActivityCmd(m.client, m.username, tabFollowing, ""))
+	}
+	if m.allDiaryLoaded && !m.allDiaryLoading {
+		m.allDiaryLoaded = false
+		m.statsFilms = nil
+		m.statsEnrichErr = nil
+		cmds = append(cmds, m.loadAllDiaryCmd())
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) goToSearch() Model {
+	if m.profileModal {
+		m.profileModal = false
+		m.resizeViewport()
+	}
+	if m.activeTab != tabSearch {
+		m.activeTab = tabSearch
+		m.resetTabPosition()
+		return m
+	}
+	m.searchFocusInput = true
+	m.searchInput.Focus()
+	m.resizeViewport()
+	return m
+}
+
+func (m Model) setWatchlist(add bool) (tea.Model, tea.Cmd) {
+	action, status := "remove", "Removing from watchlist..."
+	if add {
+		action, status = "add", "Adding to watchlist..."
+	}
+	req, err := m.buildWatchlistRequest()
+	if err != nil {
+		m.logError("watchlist request "+action, err)
+		m.watchlistStatus = "Error: " + errorText(err)
+		return m, nil
+	}
+	m.watchlistPending = true
+	m.watchlistStatus = status
+	return m, setWatchlistCmd(m.client, m.film, req, add)
+}
+
+func (m Model) setFollowing(follow bool) (tea.Model, tea.Cmd) {
+	username, _, _ := m.followTarget()
+	m.followPending = true
+	if follow {
+		m.followStatus = "Following @" + username + "..."
+	} else {
+		m.followStatus = "Unfollowing @" + username + "..."
+	}
+	m.refreshModalViewport()
+	return m, setFollowingCmd(m.client, username, follow)
+}
+
+// browserURL is the page the open key shows, or "" when there is none.
+func (m Model) browserURL() string {
+	switch {
+	case m.profileModal:
+		return letterboxd.ProfileURL(m.modalUser)
+	case m.activeTab == tabProfile:
+		return letterboxd.ProfileURL(m.profileUser)
+	case m.activeTab == tabFilm:
+		return m.film.URL
+	case m.activeTab == tabPeople && len(m.people) > 0:
+		return m.people[clamp(m.peopleList.selected, 0, len(m.people)-1)].URL
+	}
+	return ""
+}
+
 func (m *Model) handleJumpKeys(msg tea.KeyMsg) bool {
 	if key.Matches(msg, m.keys.JumpBottom) {
 		m.pendingG = false
@@ -676,7 +982,7 @@ func (m *Model) handleJumpKeys(msg tea.KeyMsg) bool {
 		return true
 	}
 
-	if msg.String() != "g" {
+	if !key.Matches(msg, m.keys.JumpTop) {
 		m.pendingG = false
 		return false
 	}
@@ -715,13 +1021,13 @@ func (m Model) updateLogModal(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(typed, m.keys.Select, m.keys.Submit):
 			if m.logForm.focus == logFieldSubmit {
 				if m.film.ViewingUID == "" {
-					logging.LogError("log entry", errors.New("missing film id"))
+					m.logError("log entry", errors.New("missing film id"))
 					m.logForm.status = "Missing film id; cannot log."
 					return m, nil
 				}
 				req := m.buildDiaryRequest()
 				m.logForm.submitting = true
-				return m, saveDiaryEntryCmd(m.client, req)
+				return m, saveDiaryEntryCmd(m.client, m.film, req)
 			}
 			switch m.logForm.focus {
 			case logFieldRewatch:
@@ -763,7 +1069,11 @@ func (m Model) updateLogModal(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.logForm.submitting = false
 		if typed.err != nil {
 			err := m.logAndSanitize("save diary entry", typed.err)
-			m.logForm.status = "Error: " + err.Error()
+			if cmd, ok := m.queueWrite(store.NewDiaryOp(typed.title, typed.filmURL, typed.req), typed.err); ok {
+				m.logForm.status = "Letterboxd unreachable; queued and will retry automatically."
+				return m, cmd
+			}
+			m.logForm.status = "Error: " + errorText(err)
 			return m, nil
 		}
 		m.logForm.status = "Saved!"
@@ -790,6 +1100,71 @@ func (m Model) updateLogModal(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) updateFilterModal(msg tea.Msg) (tea.Model, tea.Cmd) {
+	typed, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch {
+	case key.Matches(typed, m.keys.QuitAll):
+		return m, tea.Quit
+	case key.Matches(typed, m.keys.Cancel):
+		m.filterModal = false
+		m.resizeViewport()
+		return m, nil
+	case key.Matches(typed, m.keys.NextTab):
+		m.filterForm.focusField(m.filterForm.focus + 1)
+		return m, nil
+	case key.Matches(typed, m.keys.PrevTab):
+		m.filterForm.focusField(m.filterForm.focus - 1)
+		return m, nil
+	case key.Matches(typed, m.keys.Submit):
+		return m.applyFilterForm()
+	case key.Matches(typed, m.keys.Select):
+		switch m.filterForm.focus {
+		case m.filterForm.clearIndex():
+			m.filterForm.clear()
+			m.filterForm.focusField(m.filterForm.applyIndex())
+			return m, nil
+		case m.filterForm.applyIndex():
+			return m.applyFilterForm()
+		}
+		if m.filterForm.cycle(1) {
+			return m, nil
+		}
+		return m.applyFilterForm()
+	case key.Matches(typed, m.keys.Toggle):
+		if m.filterForm.cycle(1) {
+			return m, nil
+		}
+	}
+	return m, m.filterForm.update(msg)
+}
+
+func (m Model) applyFilterForm() (tea.Model, tea.Cmd) {
+	m.filterModal = false
+	m.resizeViewport()
+	switch m.filterForm.id {
+	case filterFilms:
+		m.filmsFilter = filmsFilterFromForm(m.filterForm)
+		m.resetFilmsList()
+		return m, fetchUserFilmsCmd(m.client, m.username, m.filmsQuery(), 1)
+	case filterWatchlist:
+		m.watchlistFilter = watchlistFilterFromForm(m.filterForm)
+		m.resetWatchlist()
+		return m, fetchWatchlistCmd(m.client, m.username, 1, m.watchlistQuery())
+	case filterRoulette:
+		return m.startRoulette(rouletteOptionsFromForm(m.filterForm))
+	case filterDiaryJump:
+		return m.applyDiaryJump()
+	case filterQueueEdit:
+		return m.applyQueueEdit()
+	case filterExport:
+		return m.applyExport()
+	}
+	return m, nil
+}
+
 func (m Model) updateCookieModal(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch typed := msg.(type) {
 	case tea.KeyMsg:
@@ -823,21 +1198,48 @@ func (m Model) updateCookieModal(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.cookieStatus = "Error: Cookie missing cf_clearance."
 				return m, nil
 			}
-			m.cookiePending = value
 			m.cookieSaving = true
-			m.cookieStatus = "Saving cookie..."
-			return m, saveCookieCmd(m.username, value)
+			// A cookie that couldn't be checked is saved when sent again.
+			if value == m.cookieUnchecked || m.client == nil {
+				m.cookieUnchecked = ""
+				m.cookiePending = value
+				m.cookieStatus = "Saving cookie..."
+				return m, saveCookieCmd(m.secrets, config.Account{Name: m.accountName(), Username: m.username, Cookie: value})
+			}
+			m.cookieStatus = "Checking cookie with Letterboxd..."
+			return m, checkCookieCmd(m.client, value, m.username)
 		}
+	case cookieCheckedMsg:
+		if typed.cookie != strings.TrimSpace(m.cookieInput.Value()) {
+			m.cookieSaving = false
+			return m, nil
+		}
+		if typed.err != nil {
+			m.cookieSaving = false
+			if sessionInvalid(typed.err) {
+				m.cookieStatus = sessionProblem(typed.err)
+				return m, nil
+			}
+			m.logError("cookie check", typed.err)
+			m.cookieUnchecked = typed.cookie
+			m.cookieStatus = "Error: Couldn't reach Letterboxd to check the cookie. Press Enter again to save it anyway."
+			return m, nil
+		}
+		m.cookiePending = typed.cookie
+		m.cookieStatus = "Saving cookie..."
+		return m, saveCookieCmd(m.secrets, config.Account{Name: m.accountName(), Username: m.username, Cookie: typed.cookie})
 	case cookieSavedMsg:
 		m.cookieSaving = false
 		if typed.err != nil {
-			logging.LogError("cookie save", typed.err)
-			m.cookieStatus = "Error: " + typed.err.Error()
+			m.logError("cookie save", typed.err)
+			m.cookieStatus = "Error: " + errorText(typed.err)
 			return m, nil
 		}
 		if m.client != nil {
 			m.client.Cookie = m.cookiePending
 		}
+		m.sessionErr = nil
+		m.staleClearance = clearanceAge(m.cookiePending, time.Now())
 		m.cookiePending = ""
 		m.cookieModal = false
 		m.cookieStatus = ""
@@ -846,7 +1248,8 @@ func (m Model) updateCookieModal(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds := []tea.Cmd{
 			fetchProfileCmd(m.client, m.profileUser),
 			fetchDiaryCmd(m.client, m.username, 1, m.diarySortParam()),
-			fetchWatchlistCmd(m.client, m.username, 1, m.watchlistSortParam()),
+			fetchWatchlistCmd(m.client, m.username, 1, m.watchlistQuery()),
+			fetchUserFilmsCmd(m.client, m.username, m.filmsQuery(), 1),
 			fetchActivityCmd(m.client, m.username, tabActivity, ""),
 		}
 		if m.hasCookie() {
@@ -888,10 +1291,10 @@ func appendDiaryEntries(existing, incoming []letterboxd.DiaryEntry) ([]letterbox
 
 func diaryKey(entry letterboxd.DiaryEntry) string {
 	if entry.FilmURL != "" {
-		return entry.FilmURL + "|" + entry.Date
+		return entry.FilmURL + "|" + entry.Date.Format(time.DateOnly)
 	}
 	if entry.Title != "" {
-		return entry.Title + "|" + entry.Date
+		return entry.Title + "|" + entry.Date.Format(time.DateOnly)
 	}
 	return ""
 }
@@ -929,6 +1332,48 @@ func watchlistKey(item letterboxd.WatchlistItem) string {
 	return ""
 }
 
+func appendMembers(existing, incoming []letterboxd.Member) ([]letterboxd.Member, int) {
+	seen := make(map[string]struct{}, len(existing))
+	for _, member := range existing {
+		if member.Username != "" {
+			seen[member.Username] = struct{}{}
+		}
+	}
+	added := 0
+	for _, member := range incoming {
+		if member.Username != "" {
+			if _, ok := seen[member.Username]; ok {
+				continue
+			}
+			seen[member.Username] = struct{}{}
+		}
+		existing = append(existing, member)
+		added++
+	}
+	return existing, added
+}
+
+func appendUserFilms(existing, incoming []letterboxd.UserFilm) ([]letterboxd.UserFilm, int) {
+	seen := make(map[string]struct{}, len(existing))
+	for _, film := range existing {
+		if film.FilmURL != "" {
+			seen[film.FilmURL] = struct{}{}
+		}
+	}
+	added := 0
+	for _, film := range incoming {
+		if film.FilmURL != "" {
+			if _, ok := seen[film.FilmURL]; ok {
+				continue
+			}
+			seen[film.FilmURL] = struct{}{}
+		}
+		existing = append(existing, film)
+		added++
+	}
+	return existing, added
+}
+
 func appendActivityItems(existing, incoming []letterboxd.ActivityItem) ([]letterboxd.ActivityItem, int) {
 	seen := make(map[string]struct{}, len(existing))
 	for _, item := range existing {
@@ -59,7 +59,7 @@ func TestUpdateLogModalToggle(t *testing.T) {
 func TestUpdateWatchlistResult(t *testing.T) {
 	m := NewModel("jane", nil)
 	m.film.URL = letterboxd.BaseURL + "/film/inception/"
-	model, _ := m.Update(watchlistResultMsg{inWatchlist: true})
+	model, _ := m.Update(watchlistResultMsg{inWatchlist: true, filmURL: m.film.URL})
 	out := model.(Model)
 	if !out.film.InWatchlist || out.watchlistStatus == "" {
 		t.Fatalf("expected watchlist status update")
@@ -85,3 +85,114 @@ func TestUpdateActivityMsg(t *testing.T) {
 		t.Fatalf("unexpected activity state")
 	}
 }
+
+func TestUpdatePeopleMsg(t *testing.T) {
+	m := NewModel("jane", nil)
+	m = m.openPeople("jane", letterboxd.PeopleFollowing)
+	model, _ := m.Update(peopleMsg{items: []letterboxd.Member{{Username: "alice"}}, page: 1, user: "jane", kind: letterboxd.PeopleFollowing})
+	out := model.(Model)
+	if len(out.people) != 1 || out.peoplePage != 1 || out.loading {
+		t.Fatalf("unexpected people state: %+v", out.people)
+	}
+	model, _ = out.Update(peopleMsg{items: []letterboxd.Member{{Username: "bob"}}, page: 1, user: "other", kind: letterboxd.PeopleFollowing})
+	if len(model.(Model).people) != 1 {
+		t.Fatalf("expected stale people result to be ignored")
+	}
+}
+
+func TestUpdateFollowResult(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.activeTab = tabPeople
+	m.people = []letterboxd.Member{{Username: "alice"}}
+	m.followPending = true
+	model, _ := m.Update(followResultMsg{username: "alice", follow: true})
+	out := model.(Model)
+	if out.followPending || !out.people[0].Followed || out.followStatus == "" {
+		t.Fatalf("unexpected follow state: %+v", out.people[0])
+	}
+}
+
+func TestUpdateUserFilmsMsg(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.activeTab = tabFilms
+	stale := letterboxd.FilmsFilter{Genre: "horror"}
+	model, _ := m.Update(userFilmsMsg{items: []letterboxd.UserFilm{{Title: "Stale"}}, page: 1, filter: stale})
+	out := model.(Model)
+	if len(out.films) != 0 {
+		t.Fatalf("expected stale films to be ignored")
+	}
+	model, _ = out.Update(userFilmsMsg{items: []letterboxd.UserFilm{{Title: "Alien", FilmURL: "/film/alien/"}}, page: 1, filter: out.filmsQuery()})
+	out = model.(Model)
+	if len(out.films) != 1 || out.filmsPage != 1 {
+		t.Fatalf("unexpected films state: %+v", out.films)
+	}
+	out.filmsLoadingMore = true
+	model, _ = out.Update(userFilmsMsg{items: []letterboxd.UserFilm{{Title: "Alien", FilmURL: "/film/alien/"}}, page: 2, filter: out.filmsQuery()})
+	out = model.(Model)
+	if len(out.films) != 1 || !out.filmsDone {
+		t.Fatalf("expected duplicate page to finish list")
+	}
+}
+
+func TestUpdateFilterModalApply(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.activeTab = tabFilms
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	out := model.(Model)
+	if !out.filterModal {
+		t.Fatalf("expected filter modal open")
+	}
+	out.filterForm.focusField(1)
+	for _, r := range "1994" {
+		model, _ = out.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		out = model.(Model)
+	}
+	model, cmd := out.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	out = model.(Model)
+	if out.filterModal || cmd == nil {
+		t.Fatalf("expected filter modal to close with fetch")
+	}
+	if out.filmsFilter.Year != "1994" {
+		t.Fatalf("unexpected films filter: %+v", out.filmsFilter)
+	}
+}
+
+func TestUpdateWatchlistMsgIgnoresStaleFilter(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.watchlistFilter = letterboxd.WatchlistFilter{Genre: "horror"}
+	model, _ := m.Update(watchlistMsg{items: []letterboxd.WatchlistItem{{Title: "Old"}}, page: 1})
+	out := model.(Model)
+	if len(out.watchlist) != 0 {
+		t.Fatalf("expected stale watchlist to be ignored")
+	}
+	model, _ = out.Update(watchlistMsg{items: []letterboxd.WatchlistItem{{Title: "Alien"}}, page: 1, filter: out.watchlistQuery()})
+	out = model.(Model)
+	if len(out.watchlist) != 1 {
+		t.Fatalf("expected filtered watchlist items")
+	}
+}
+
+func TestCompareFromProfileModal(t *testing.T) {
+	m := NewModel("jane", nil)
+	m.profileModal = true
+	m.modalUser = "joe"
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	out := model.(Model)
+	if out.activeTab != tabCompare || out.profileModal || cmd == nil {
+		t.Fatalf("expected compare view to open")
+	}
+	model, _ = out.Update(compareMsg{userA: "jane", userB: "other", cmp: letterboxd.Comparison{WatchedA: 3}})
+	out = model.(Model)
+	if !out.compareLoading {
+		t.Fatalf("expected stale compare result to be ignored")
+	}
+	model, _ = out.Update(compareMsg{userA: "jane", userB: "joe", cmp: letterboxd.Comparison{WatchedA: 3}})
+	out = model.(Model)
+	if out.compareLoading || out.comparison.WatchedA != 3 {
+		t.Fatalf("expected comparison to load")
+	}
+	model, _ = out.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.(Model).activeTab != tabProfile {
+		t.Fatalf("expected esc to close compare view")
+	}
+}
@@ -104,3 +104,10 @@ func formatWhen(when string) string {
 	}
 	return t.Format("Jan 02 2006")
 }
+
+func formatDiaryDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format("Jan 2 2006")
+}
@@ -2,7 +2,10 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
 
@@ -10,9 +13,18 @@ import (
 )
 
 func (m Model) View() string {
-	theme := newTheme()
-	header := theme.header.Render("Letterboxd TUI") + " " + theme.subtle.Render("@"+m.username)
+	theme := m.theme
+	header := renderHeader(m, theme)
 	tabLine := renderTabs(m, theme)
+	footer := renderHelp(m, theme, m.width)
+
+	// The list renders as if the terminal ended where the preview starts.
+	split := m.splitActive()
+	full := m
+	var paneWidth int
+	if split {
+		m.width, paneWidth = m.splitWidths()
+	}
 
 	var body string
 	switch m.activeTab {
@@ -22,33 +34,71 @@ func (m Model) View() string {
 		body = renderDiary(m, theme)
 	case tabWatchlist:
 		body = renderWatchlist(m, theme)
+	case tabFilms:
+		body = renderFilms(m, theme)
 	case tabFilm:
 		body = renderFilm(m, theme)
 	case tabActivity:
-		body = renderActivityWithStatus(m.activity, m.activityErr, m.activityMoreErr, m.actList.selected, m.width, m.activityLoadingMore, m.activityDone, theme)
+		items, selected := m.filterActivity(m.activity, m.actList.selected)
+		body = renderActivityWithStatus(items, m.activityErr, m.activityMoreErr, selected, m.width, m.activityLoadingMore, m.activityDone, m.findQuery(), theme)
 	case tabFollowing:
-		body = renderActivityWithStatus(m.following, m.followErr, m.followMoreErr, m.followList.selected, m.width, m.followLoadingMore, m.followDone, theme)
+		items, selected := m.filterActivity(m.following, m.followList.selected)
+		body = renderActivityWithStatus(items, m.followErr, m.followMoreErr, selected, m.width, m.followLoadingMore, m.followDone, m.findQuery(), theme)
 	case tabSearch:
 		body = renderSearch(m, theme)
+	case tabPeople:
+		body = renderPeople(m, theme)
+	case tabCompare:
+		body = renderCompare(m, theme)
+	case tabStats:
+		body = renderStats(m, theme)
+	case tabQueue:
+		body = renderQueue(m, theme)
+	}
+	if line := renderFindLine(m, theme); line != "" {
+		body = lipgloss.JoinVertical(lipgloss.Left, line, body)
 	}
 
-	footer := renderHelp(m, theme, m.width)
 	vp := m.viewport
 	vp.SetContent(body)
-	base := lipgloss.JoinVertical(lipgloss.Left, header, tabLine, vp.View(), footer)
-	if m.activeTab == tabFilm {
-		base = renderFilmModal(base, m, theme)
+	list := vp.View()
+	m = full
+	if split {
+		list = lipgloss.JoinHorizontal(lipgloss.Top, list, renderPreviewPane(m, paneWidth, vp.Height, theme))
+	}
+	base := lipgloss.JoinVertical(lipgloss.Left, header, tabLine, list, footer)
+	for _, layer := range modalLayers(m) {
+		if !layer.open {
+			continue
+		}
+		if panel := layer.render(m, theme); panel != "" {
+			base = overlayModal(base, panel, m, theme, layer.vertical)
+		}
+	}
+	return base
+}
+
+func renderHeader(m Model, theme themeStyles) string {
+	header := theme.header.Render("Letterboxd TUI") + " " + theme.subtle.Render("@"+m.username)
+	if m.canSwitchAccount() {
+		header += " " + theme.dim.Render("("+m.accountName()+", A to switch)")
 	}
-	if m.profileModal {
-		base = renderProfileModal(base, m, theme)
+	if m.accountStatus != "" {
+		header += " " + theme.dim.Render("· "+m.accountStatus)
 	}
-	if m.logModal {
-		base = renderLogModal(base, m, theme)
+	if m.exportStatus != "" {
+		header += " " + theme.dim.Render("· "+m.exportStatus)
 	}
-	if m.cookieModal {
-		base = renderCookieModal(base, m, theme)
+	if m.offline {
+		header += " " + theme.dim.Render("· offline, showing saved data")
 	}
-	return base
+	if n := m.pendingOps(); n > 0 {
+		header += " " + theme.dim.Render(fmt.Sprintf("· %d pending (P)", n))
+	}
+	if banner := m.sessionBanner(); banner != "" {
+		header += " " + theme.rateLow.Render(banner)
+	}
+	return header
 }
 
 func renderTabs(m Model, theme themeStyles) string {
@@ -60,6 +110,15 @@ func renderTabs(m Model, theme themeStyles) string {
 			out = append(out, theme.tab.Render(item.label))
 		}
 	}
+	if m.activeTab == tabPeople {
+		out = append(out, theme.tabActive.Render(peopleTabLabel(m.peopleKind, m.peopleUser)))
+	}
+	if m.activeTab == tabCompare {
+		out = append(out, theme.tabActive.Render(compareTabLabel(m.compareA, m.compareB)))
+	}
+	if m.activeTab == tabQueue {
+		out = append(out, theme.tabActive.Render("Pending changes"))
+	}
 	return lipgloss.JoinHorizontal(lipgloss.Top, out...)
 }
 
@@ -73,6 +132,8 @@ func visibleTabItems(m Model) []tabItem {
 	tabs := []tabItem{
 		{id: tabProfile, label: "Profile"},
 		{id: tabDiary, label: "Diary"},
+		{id: tabFilms, label: "Films"},
+		{id: tabStats, label: "Stats"},
 		{id: tabFollowing, label: "Friends", needsCookie: true},
 		{id: tabActivity, label: "My Activity"},
 		{id: tabWatchlist, label: "Watchlist"},
@@ -141,23 +202,42 @@ func renderProfile(m Model, theme themeStyles) string {
 	if count := m.profileSelectableCount(); count > 0 {
 		selected = clamp(m.profileList.selected, 0, count-1)
 	}
-	return renderProfileContent(m.profile, m.profileErr, m.loading, m.profileUser, m.profileStack, selected, m.width, theme)
+	profile := m.profileView()
+	if m.findQuery() != "" {
+		profile.Recent = slices.Clone(profile.Recent)
+		for i, recent := range profile.Recent {
+			text := profileRecentText(recent)
+			hits, _ := m.findHits(text)
+			profile.Recent[i].Summary = markMatches(text, hits, 0, lipgloss.NewStyle(), theme.match)
+		}
+	}
+	return renderProfileContent(profile, m.profileErr, m.loading, m.profileUser, nil, "", selected, m.width, theme)
 }
 
 func renderDiary(m Model, theme themeStyles) string {
+	if m.diaryHeatmap {
+		return renderDiaryHeatmap(m, theme)
+	}
+	var rows []string
+	if m.diaryRangeActive() {
+		rows = append(rows, theme.subtle.Render("Showing "+diaryPeriodLabel(m.diaryFrom, m.diaryTo, "Jan 2006")+" · "+keyHint(m.keys.DiaryJump)+" to change"))
+	}
 	if m.diaryErr != nil {
-		return theme.dim.Render("Error: " + m.diaryErr.Error())
+		return lipgloss.JoinVertical(lipgloss.Left, append(rows, theme.dim.Render("Error: "+errorText(m.diaryErr)))...)
 	}
 	if m.loading && len(m.diary) == 0 {
-		return theme.dim.Render("Loading diary…")
+		return lipgloss.JoinVertical(lipgloss.Left, append(rows, theme.dim.Render("Loading diary…"))...)
 	}
 	if len(m.diary) == 0 {
-		return theme.dim.Render("No diary entries found.")
+		return lipgloss.JoinVertical(lipgloss.Left, append(rows, theme.dim.Render("No diary entries found."))...)
 	}
-	var rows []string
 	width := max(40, m.width-2)
 	for i, entry := range m.diary {
-		date := theme.badge.Render(entry.Date)
+		hits, actor, ok := m.findFilm(entry.Title, entry.FilmURL)
+		if !ok {
+			continue
+		}
+		date := theme.badge.Render(formatDiaryDate(entry.Date))
 		rating := entry.Rating
 		if rating == "" {
 			rating = "—"
@@ -171,7 +251,8 @@ func renderDiary(m Model, theme themeStyles) string {
 		if entry.Review {
 			flags += " ✎"
 		}
-		line := fmt.Sprintf("%s %s %s%s", date, entry.Title, rating, flags)
+		title := markMatches(entry.Title, hits, 0, lipgloss.NewStyle(), theme.match)
+		line := fmt.Sprintf("%s %s %s%s", date, title, rating, flags) + castMatch(actor, theme)
 		rows = append(rows, renderSelectableLine(line, i == m.diaryList.selected, width, theme))
 	}
 	if status := renderListStatus(m.diaryLoadingMore, m.diaryMoreErr, m.diaryDone, theme); status != "" {
@@ -181,22 +262,28 @@ func renderDiary(m Model, theme themeStyles) string {
 }
 
 func renderWatchlist(m Model, theme themeStyles) string {
+	header := theme.subtle.Render(watchlistFilterSummary(m.watchlistFilter) + " · sort: " + m.watchlistSortLabel())
+	if m.rouletteStatus != "" {
+		header += theme.subtle.Render(" · ") + renderWatchlistStatus(m.rouletteStatus, theme)
+	}
 	if m.watchErr != nil {
-		return theme.dim.Render("Error: " + m.watchErr.Error())
+		return lipgloss.JoinVertical(lipgloss.Left, header, theme.dim.Render("Error: "+errorText(m.watchErr)))
 	}
 	if m.loading && len(m.watchlist) == 0 {
-		return theme.dim.Render("Loading watchlist…")
+		return lipgloss.JoinVertical(lipgloss.Left, header, theme.dim.Render("Loading watchlist…"))
 	}
 	if len(m.watchlist) == 0 {
-		return theme.dim.Render("No watchlist items found.")
+		return lipgloss.JoinVertical(lipgloss.Left, header, theme.dim.Render("No watchlist items found."))
 	}
-	var rows []string
+	rows := []string{header}
 	width := max(40, m.width-2)
 	for i, item := range m.watchlist {
-		title := item.Title
-		if item.Year != "" {
-			title = fmt.Sprintf("%s (%s)", item.Title, item.Year)
+		title := filmLabel(item.Title, item.Year)
+		hits, actor, ok := m.findFilm(title, item.FilmURL)
+		if !ok {
+			continue
 		}
+		title = markMatches(title, hits, 0, lipgloss.NewStyle(), theme.match) + castMatch(actor, theme)
 		rows = append(rows, renderSelectableLine(title, i == m.watchList.selected, width, theme))
 	}
 	if status := renderListStatus(m.watchLoadingMore, m.watchMoreErr, m.watchDone, theme); status != "" {
@@ -205,9 +292,104 @@ func renderWatchlist(m Model, theme themeStyles) string {
 	return lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
 
-func renderProfileContent(profile letterboxd.Profile, err error, loading bool, profileUser string, stack []string, selected int, width int, theme themeStyles) string {
+func renderFilms(m Model, theme themeStyles) string {
+	header := theme.subtle.Render(filmsFilterSummary(m.filmsFilter) + " · sort: " + m.filmsSortLabel())
+	if m.filmsErr != nil {
+		return lipgloss.JoinVertical(lipgloss.Left, header, theme.dim.Render("Error: "+errorText(m.filmsErr)))
+	}
+	if m.loading && len(m.films) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, header, theme.dim.Render("Loading films…"))
+	}
+	if len(m.films) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, header, theme.dim.Render("No films found."))
+	}
+	rows := []string{header}
+	width := max(40, m.width-2)
+	for i, film := range m.films {
+		line := filmLabel(film.Title, film.Year)
+		hits, actor, ok := m.findFilm(line, film.FilmURL)
+		if !ok {
+			continue
+		}
+		line = markMatches(line, hits, 0, lipgloss.NewStyle(), theme.match)
+		if film.Rating != "" {
+			line += " " + styleRating(film.Rating, theme)
+		}
+		if film.Liked {
+			line += " ♥"
+		}
+		if film.Reviewed {
+			line += " ✎"
+		}
+		line += castMatch(actor, theme)
+		rows = append(rows, renderSelectableLine(line, i == m.filmsList.selected, width, theme))
+	}
+	if status := renderListStatus(m.filmsLoadingMore, m.filmsMoreErr, m.filmsDone, theme); status != "" {
+		rows = append(rows, status)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// castMatch notes the actor a film row matched the filter through.
+func castMatch(actor string, theme themeStyles) string {
+	if actor == "" {
+		return ""
+	}
+	return theme.dim.Render(" · " + actor)
+}
+
+func peopleTabLabel(kind letterboxd.PeopleKind, username string) string {
+	label := "Following"
+	if kind == letterboxd.PeopleFollowers {
+		label = "Followers"
+	}
+	if username == "" {
+		return label
+	}
+	return fmt.Sprintf("%s: @%s", label, username)
+}
+
+func renderPeople(m Model, theme themeStyles) string {
+	if m.peopleErr != nil {
+		return theme.dim.Render("Error: " + errorText(m.peopleErr))
+	}
+	if m.loading && len(m.people) == 0 {
+		return theme.dim.Render("Loading members…")
+	}
+	if len(m.people) == 0 {
+		return theme.dim.Render("No members found.")
+	}
+	var rows []string
+	width := max(40, m.width-2)
+	for i, member := range m.people {
+		text := memberText(member)
+		hits, ok := m.findHits(text)
+		if !ok {
+			continue
+		}
+		name, username := text, ""
+		if at := strings.LastIndex(text, "@"); at >= 0 {
+			name, username = text[:at], text[at:]
+		}
+		line := markMatches(name, hits, 0, lipgloss.NewStyle(), theme.match) +
+			markMatches(username, hits, len([]rune(name)), theme.user, theme.match)
+		if m.hasCookie() && member.FollowOK && member.Followed {
+			line += " " + theme.rateHigh.Render("✓")
+		}
+		rows = append(rows, renderSelectableLine(line, i == m.peopleList.selected, width, theme))
+	}
+	if m.followStatus != "" {
+		rows = append(rows, renderWatchlistStatus(m.followStatus, theme))
+	}
+	if status := renderListStatus(m.peopleLoadingMore, m.peopleMoreErr, m.peopleDone, theme); status != "" {
+		rows = append(rows, status)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func renderProfileContent(profile letterboxd.Profile, err error, loading bool, profileUser string, stack []string, note string, selected int, width int, theme themeStyles) string {
 	if err != nil {
-		return theme.dim.Render("Error: " + err.Error())
+		return theme.dim.Render("Error: " + errorText(err))
 	}
 	if loading && len(profile.Stats) == 0 && len(profile.Favorites) == 0 {
 		return theme.dim.Render("Loading profile…")
@@ -216,12 +398,21 @@ func renderProfileContent(profile letterboxd.Profile, err error, loading bool, p
 	width = max(40, width-2)
 	var rows []string
 	selectableIndex := 0
-	rows = append(rows, theme.subtle.Render(renderBreadcrumbs(stack, profileUser, theme.user)))
+	crumbs := renderBreadcrumbs(stack, profileUser, theme.user)
+	if note != "" {
+		crumbs += " · " + note
+	}
+	rows = append(rows, theme.subtle.Render(crumbs))
 	if len(profile.Stats) > 0 {
 		rows = append(rows, "", theme.subtle.Render("Stats"))
 		for _, stat := range profile.Stats {
 			line := fmt.Sprintf("%s %s", theme.badge.Render(stat.Value), stat.Label)
-			rows = append(rows, theme.item.Render(line))
+			if _, ok := letterboxd.PeopleKindFromURL(stat.URL); ok && selectionEnabled {
+				rows = append(rows, renderSelectableLine(line, selectableIndex == selected, width, theme))
+				selectableIndex++
+			} else {
+				rows = append(rows, theme.item.Render(line))
+			}
 		}
 	}
 	if len(profile.Favorites) > 0 {
@@ -264,6 +455,21 @@ func renderProfileContent(profile letterboxd.Profile, err error, loading bool, p
 }
 
 func renderSearch(m Model, theme themeStyles) string {
+	rows := searchHeaderRows(m, theme)
+	width := max(40, m.width-2)
+	for i, r := range m.searchResults {
+		title := r.Title
+		if r.Year != "" {
+			title = fmt.Sprintf("%s (%s)", r.Title, r.Year)
+		}
+		selected := i == m.searchList.selected && !m.searchFocusInput
+		rows = append(rows, renderSelectableLine(title, selected, width, theme))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// searchHeaderRows are the lines renderSearch draws above the results.
+func searchHeaderRows(m Model, theme themeStyles) []string {
 	var rows []string
 	rows = append(rows, theme.subtle.Render("Search films"))
 
@@ -278,27 +484,17 @@ func renderSearch(m Model, theme themeStyles) string {
 		rows = append(rows, theme.dim.Render("Searching…"))
 	}
 	if m.searchErr != nil {
-		rows = append(rows, theme.dim.Render("Error: "+m.searchErr.Error()))
+		rows = append(rows, theme.dim.Render("Error: "+errorText(m.searchErr)))
 	}
 	if !m.searchLoading && m.searchErr == nil && len(m.searchResults) == 0 {
 		rows = append(rows, theme.dim.Render("No results yet."))
 	}
-
-	width := max(40, m.width-2)
-	for i, r := range m.searchResults {
-		title := r.Title
-		if r.Year != "" {
-			title = fmt.Sprintf("%s (%s)", r.Title, r.Year)
-		}
-		selected := i == m.searchList.selected && !m.searchFocusInput
-		rows = append(rows, renderSelectableLine(title, selected, width, theme))
-	}
-	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+	return rows
 }
 
 func renderFilm(m Model, theme themeStyles) string {
 	if m.filmErr != nil {
-		return theme.dim.Render("Error: " + m.filmErr.Error())
+		return theme.dim.Render("Error: " + errorText(m.filmErr))
 	}
 	if m.loading && m.film.Title == "" {
 		return theme.dim.Render("Loading film…")
@@ -307,24 +503,18 @@ func renderFilm(m Model, theme themeStyles) string {
 		return theme.dim.Render("No film details found.")
 	}
 	var rows []string
-	wrapWidth := max(40, modalContentWidth(m.width, m.height)-2)
+	posterCols, _ := m.posterSize()
+	if posterCols > 0 {
+		posterCols += posterGap
+	}
+	wrapWidth := max(40, modalContentWidth(m.width, m.height)-2-posterCols)
 	title := m.film.Title
 	if m.film.Year != "" {
 		title = fmt.Sprintf("%s (%s)", title, m.film.Year)
 	}
 	rows = append(rows, theme.header.Render(title))
-	meta := []string{}
-	if m.film.Director != "" {
-		meta = append(meta, "Dir. "+m.film.Director)
-	}
-	if m.film.Runtime != "" {
-		meta = append(meta, m.film.Runtime)
-	}
-	if m.film.AvgRating != "" {
-		meta = append(meta, "Avg "+m.film.AvgRating)
-	}
-	if len(meta) > 0 {
-		rows = append(rows, theme.subtle.Render(strings.Join(meta, " • ")))
+	if meta := filmMeta(m.film); meta != "" {
+		rows = append(rows, theme.subtle.Render(meta))
 	}
 	if m.film.UserStatus != "" || m.film.UserRating != "" {
 		userLine := "You: "
@@ -370,9 +560,24 @@ func renderFilm(m Model, theme themeStyles) string {
 	return lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
 
-func renderFilmModal(base string, m Model, theme themeStyles) string {
+// filmMeta is the director, runtime and average rating line under a title.
+func filmMeta(film letterboxd.Film) string {
+	var meta []string
+	if film.Director != "" {
+		meta = append(meta, "Dir. "+film.Director)
+	}
+	if film.Runtime != "" {
+		meta = append(meta, film.Runtime)
+	}
+	if film.AvgRating != "" {
+		meta = append(meta, "Avg "+film.AvgRating)
+	}
+	return strings.Join(meta, " • ")
+}
+
+func renderFilmPanel(m Model, theme themeStyles) string {
 	if m.modalVP.View() == "" && renderFilm(m, theme) == "" {
-		return base
+		return ""
 	}
 	width, height := modalDimensions(m.width, m.height)
 	innerWidth := width - 4
@@ -384,36 +589,59 @@ func renderFilmModal(base string, m Model, theme themeStyles) string {
 	vp := m.modalVP
 	vp.Width = innerWidth
 	vp.Height = bodyHeight
+	cols, _ := m.posterSize()
+	if cols > 0 && m.poster.art != "" {
+		vp.Width -= cols + posterGap
+	}
 	body := vp.View()
 	if body == "" {
 		vp.SetContent(renderFilm(m, theme))
 		body = vp.View()
 	}
+	if vp.Width < innerWidth {
+		body = lipgloss.JoinHorizontal(lipgloss.Top, m.poster.art, strings.Repeat(" ", posterGap), body)
+	}
 	content := lipgloss.JoinVertical(lipgloss.Left, body, "", legend)
-	panel := lipgloss.NewStyle().
+	panel := theme.panel.
 		Width(width).
 		Height(height).
-		Padding(1, 2).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#3A4A55")).
-		Background(lipgloss.Color("#14181C")).
-		Foreground(lipgloss.Color("#E6F0F2"))
+		Padding(1, 2)
 	panelContent := lipgloss.Place(innerWidth, innerHeight, lipgloss.Left, lipgloss.Top, content)
-	modal := panel.Render(panelContent)
+	return panel.Render(panelContent)
+}
+
+// modalLayer is a modal View can draw over the screen.
+type modalLayer struct {
+	open     bool
+	vertical lipgloss.Position
+	render   func(Model, themeStyles) string
+}
+
+// modalLayers lists the modals bottom to top, in the order View stacks them.
+func modalLayers(m Model) []modalLayer {
+	return []modalLayer{
+		{m.activeTab == tabFilm, lipgloss.Center, renderFilmPanel},
+		{m.profileModal, lipgloss.Center, renderProfilePanel},
+		{m.logModal, lipgloss.Center, renderLogPanel},
+		{m.filterModal, lipgloss.Center, renderFilterPanel},
+		{m.paletteModal, lipgloss.Top, renderPalettePanel},
+		{m.debugPanel, lipgloss.Center, renderDebugPanel},
+		{m.cookieModal, lipgloss.Center, renderCookiePanel},
+	}
+}
 
-	dim := lipgloss.NewStyle().
-		Background(lipgloss.Color("#0E1114")).
-		Foreground(lipgloss.Color("#5E6A72")).
-		Render(base)
-	return dim + "\n" + lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal, lipgloss.WithWhitespaceChars(" "), lipgloss.WithWhitespaceBackground(lipgloss.Color("#0E1114")))
+// overlayModal draws modal over a shaded copy of the screen behind it.
+func overlayModal(base, modal string, m Model, theme themeStyles, vertical lipgloss.Position) string {
+	dim := theme.shade.Render(base)
+	return dim + "\n" + lipgloss.Place(m.width, m.height, lipgloss.Center, vertical, modal, lipgloss.WithWhitespaceChars(" "), lipgloss.WithWhitespaceBackground(theme.shade.GetBackground()))
 }
 
-func renderProfileModal(base string, m Model, theme themeStyles) string {
+func renderProfilePanel(m Model, theme themeStyles) string {
 	width, height := modalDimensions(m.width, m.height)
 	innerWidth := width - 4
 	selected := m.modalProfileSelectedIndex()
-	if m.modalVP.View() == "" && renderProfileContent(m.modalProfile, m.modalProfileErr, m.modalLoading, m.modalUser, nil, selected, innerWidth, theme) == "" {
-		return base
+	if m.modalVP.View() == "" && renderProfileContent(m.modalProfile, m.modalProfileErr, m.modalLoading, m.modalUser, nil, m.modalProfileNote(), selected, innerWidth, theme) == "" {
+		return ""
 	}
 	innerHeight := height - 2
 	legend := renderHelp(m, theme, innerWidth)
@@ -425,29 +653,19 @@ func renderProfileModal(base string, m Model, theme themeStyles) string {
 	vp.Height = bodyHeight
 	body := vp.View()
 	if body == "" {
-		vp.SetContent(renderProfileContent(m.modalProfile, m.modalProfileErr, m.modalLoading, m.modalUser, nil, selected, innerWidth, theme))
+		vp.SetContent(renderProfileContent(m.modalProfile, m.modalProfileErr, m.modalLoading, m.modalUser, nil, m.modalProfileNote(), selected, innerWidth, theme))
 		body = vp.View()
 	}
 	content := lipgloss.JoinVertical(lipgloss.Left, body, "", legend)
-	panel := lipgloss.NewStyle().
+	panel := theme.panel.
 		Width(width).
 		Height(height).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#3A4A55")).
-		Background(lipgloss.Color("#14181C")).
-		Foreground(lipgloss.Color("#E6F0F2")).
 		Padding(1, 2)
 	panelContent := lipgloss.Place(innerWidth, innerHeight, lipgloss.Left, lipgloss.Top, content)
-	modal := panel.Render(panelContent)
-
-	dim := lipgloss.NewStyle().
-		Background(lipgloss.Color("#0E1114")).
-		Foreground(lipgloss.Color("#5E6A72")).
-		Render(base)
-	return dim + "\n" + lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal, lipgloss.WithWhitespaceChars(" "), lipgloss.WithWhitespaceBackground(lipgloss.Color("#0E1114")))
+	return panel.Render(panelContent)
 }
 
-func renderLogModal(base string, m Model, theme themeStyles) string {
+func renderLogPanel(m Model, theme themeStyles) string {
 	form := renderLogForm(m, theme)
 	width, height := modalDimensions(m.width, m.height)
 	innerWidth := width - 4
@@ -458,25 +676,57 @@ func renderLogModal(base string, m Model, theme themeStyles) string {
 	body := lipgloss.Place(innerWidth, bodyHeight, lipgloss.Left, lipgloss.Top, form)
 	content := lipgloss.JoinVertical(lipgloss.Left, body, "", legend)
 
-	panel := lipgloss.NewStyle().
+	panel := theme.panel.
+		Width(width).
+		Height(height).
+		Padding(1, 2)
+	panelContent := lipgloss.Place(innerWidth, innerHeight, lipgloss.Left, lipgloss.Top, content)
+	return panel.Render(panelContent)
+}
+
+func renderFilterPanel(m Model, theme themeStyles) string {
+	form := renderFilterForm(m.filterForm, theme)
+	width, height := modalDimensions(m.width, m.height)
+	innerWidth := width - 4
+	innerHeight := height - 2
+	legend := renderHelp(m, theme, innerWidth)
+	bodyHeight := max(1, innerHeight-lipgloss.Height(legend)-1)
+	body := lipgloss.Place(innerWidth, bodyHeight, lipgloss.Left, lipgloss.Top, form)
+	content := lipgloss.JoinVertical(lipgloss.Left, body, "", legend)
+
+	panel := theme.panel.
 		Width(width).
 		Height(height).
-		Padding(1, 2).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#3A4A55")).
-		Background(lipgloss.Color("#14181C")).
-		Foreground(lipgloss.Color("#E6F0F2"))
+		Padding(1, 2)
 	panelContent := lipgloss.Place(innerWidth, innerHeight, lipgloss.Left, lipgloss.Top, content)
-	modal := panel.Render(panelContent)
+	return panel.Render(panelContent)
+}
 
-	dim := lipgloss.NewStyle().
-		Background(lipgloss.Color("#0E1114")).
-		Foreground(lipgloss.Color("#5E6A72")).
-		Render(base)
-	return dim + "\n" + lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal, lipgloss.WithWhitespaceChars(" "), lipgloss.WithWhitespaceBackground(lipgloss.Color("#0E1114")))
+func renderFilterForm(form filterForm, theme themeStyles) string {
+	rows := []string{theme.header.Render(form.title)}
+	for i, field := range form.fields {
+		value := field.input.View()
+		if field.kind == filterFieldChoice {
+			value = form.value(field.key)
+		}
+		rows = append(rows, renderLogInput(field.label, value, form.focus == i, theme))
+	}
+	apply := theme.item.Render("Apply")
+	if form.focus == form.applyIndex() {
+		apply = theme.itemSel.Render("Apply")
+	}
+	clear := theme.item.Render("Clear")
+	if form.focus == form.clearIndex() {
+		clear = theme.itemSel.Render("Clear")
+	}
+	rows = append(rows, "", apply+"  "+clear)
+	if form.status != "" {
+		rows = append(rows, theme.rateLow.Render(form.status))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
 
-func renderCookieModal(base string, m Model, theme themeStyles) string {
+func renderCookiePanel(m Model, theme themeStyles) string {
 	width, height := modalDimensions(m.width, m.height)
 	innerWidth := width - 4
 	innerHeight := height - 2
@@ -484,8 +734,11 @@ func renderCookieModal(base string, m Model, theme themeStyles) string {
 
 	var rows []string
 	rows = append(rows, theme.header.Render("Update Letterboxd cookie"))
-	rows = append(rows, theme.subtle.Render(wrapText("Cloudflare challenge detected. Paste a fresh Cookie header from your browser.", wrapWidth)))
+	rows = append(rows, theme.subtle.Render(wrapText("Paste a fresh Cookie header from your browser, or run letterboxd auth import. It is checked with Letterboxd before it is saved.", wrapWidth)))
 	rows = append(rows, theme.subtle.Render(wrapText("Required: com.xk72.webparts.csrf=… and cf_clearance=…", wrapWidth)))
+	if note := clearanceNote(m.cookieInput.Value(), time.Now()); note != "" {
+		rows = append(rows, theme.dim.Render(note))
+	}
 	if status := renderCookieStatus(m, theme, wrapWidth); status != "" {
 		rows = append(rows, "", status)
 	}
@@ -499,22 +752,12 @@ func renderCookieModal(base string, m Model, theme themeStyles) string {
 	rows = append(rows, "", theme.subtle.Render("Enter to save · Esc to cancel"))
 
 	body := lipgloss.JoinVertical(lipgloss.Left, rows...)
-	panel := lipgloss.NewStyle().
+	panel := theme.panel.
 		Width(width).
 		Height(height).
-		Padding(1, 2).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#3A4A55")).
-		Background(lipgloss.Color("#14181C")).
-		Foreground(lipgloss.Color("#E6F0F2"))
+		Padding(1, 2)
 	panelContent := lipgloss.Place(innerWidth, innerHeight, lipgloss.Left, lipgloss.Top, body)
-	modal := panel.Render(panelContent)
-
-	dim := lipgloss.NewStyle().
-		Background(lipgloss.Color("#0E1114")).
-		Foreground(lipgloss.Color("#5E6A72")).
-		Render(base)
-	return dim + "\n" + lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal, lipgloss.WithWhitespaceChars(" "), lipgloss.WithWhitespaceBackground(lipgloss.Color("#0E1114")))
+	return panel.Render(panelContent)
 }
 
 func renderLogForm(m Model, theme themeStyles) string {
@@ -590,7 +833,7 @@ func renderWatchlistStatus(status string, theme themeStyles) string {
 	if strings.HasPrefix(status, "Error:") {
 		return theme.rateLow.Render(status)
 	}
-	if strings.HasPrefix(status, "Adding") || strings.HasPrefix(status, "Removing") {
+	if strings.HasPrefix(status, "Adding") || strings.HasPrefix(status, "Removing") || strings.HasSuffix(status, "...") {
 		return theme.subtle.Render(status)
 	}
 	return theme.rateHigh.Render(status)
@@ -632,7 +875,7 @@ func renderSelectableLine(line string, selected bool, width int, theme themeStyl
 
 func renderReviews(title string, reviews []letterboxd.Review, err error, width int, theme themeStyles) string {
 	if err != nil {
-		return theme.dim.Render("Error: " + err.Error())
+		return theme.dim.Render("Error: " + errorText(err))
 	}
 	if len(reviews) == 0 {
 		return theme.dim.Render("No reviews found.")
@@ -664,9 +907,9 @@ func renderReviewsWithStatus(title string, reviews []letterboxd.Review, err erro
 	return lipgloss.JoinVertical(lipgloss.Left, body, status)
 }
 
-func renderActivity(items []letterboxd.ActivityItem, err error, selected int, width int, theme themeStyles) string {
+func renderActivity(items []letterboxd.ActivityItem, err error, selected int, width int, query string, theme themeStyles) string {
 	if err != nil {
-		return theme.dim.Render("Error: " + err.Error())
+		return theme.dim.Render("Error: " + errorText(err))
 	}
 	if len(items) == 0 {
 		return theme.dim.Render("No activity found.")
@@ -678,7 +921,7 @@ func renderActivity(items []letterboxd.ActivityItem, err error, selected int, wi
 		if when == "" {
 			when = "—"
 		}
-		summary := renderSummary(item, theme)
+		summary := renderSummary(item, query, theme)
 		if summary == "" {
 			summary = item.Title
 		}
@@ -688,8 +931,8 @@ func renderActivity(items []letterboxd.ActivityItem, err error, selected int, wi
 	return lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
 
-func renderActivityWithStatus(items []letterboxd.ActivityItem, err, moreErr error, selected int, width int, loadingMore bool, done bool, theme themeStyles) string {
-	body := renderActivity(items, err, selected, width, theme)
+func renderActivityWithStatus(items []letterboxd.ActivityItem, err, moreErr error, selected int, width int, loadingMore bool, done bool, query string, theme themeStyles) string {
+	body := renderActivity(items, err, selected, width, query, theme)
 	if err != nil || len(items) == 0 {
 		return body
 	}
@@ -705,7 +948,7 @@ func renderListStatus(loading bool, moreErr error, done bool, theme themeStyles)
 		return theme.dim.Render("Loading more…")
 	}
 	if moreErr != nil {
-		return theme.rateLow.Render("Error loading more: " + moreErr.Error())
+		return theme.rateLow.Render("Error loading more: " + errorText(moreErr))
 	}
 	if done {
 		return theme.dim.Render("End of list.")
@@ -713,10 +956,16 @@ func renderListStatus(loading bool, moreErr error, done bool, theme themeStyles)
 	return ""
 }
 
-func renderSummary(item letterboxd.ActivityItem, theme themeStyles) string {
+// renderSummary styles an activity item's parts, picking out runes that match
+// query when it is set.
+func renderSummary(item letterboxd.ActivityItem, query string, theme themeStyles) string {
+	var hits []int
+	if query != "" {
+		hits, _ = fuzzyMatch(activityText(item), query)
+	}
 	if len(item.Parts) == 0 {
 		if s := compactSpaces(item.Summary); s != "" {
-			return s
+			return markMatches(s, hits, 0, lipgloss.NewStyle(), theme.match)
 		}
 		var parts []string
 		if item.Actor != "" {
@@ -733,19 +982,23 @@ func renderSummary(item letterboxd.ActivityItem, theme themeStyles) string {
 		}
 		return strings.Join(parts, " ")
 	}
-	var out strings.Builder
+	var out, plain strings.Builder
 	for _, part := range item.Parts {
 		text := compactSpaces(part.Text)
 		if text == "" {
 			continue
 		}
+		appendWithSpacing(&plain, text)
+		offset := utf8.RuneCountInString(plain.String()) - utf8.RuneCountInString(text)
 		switch part.Kind {
 		case "user":
-			text = theme.user.Render(text)
+			text = markMatches(text, hits, offset, theme.user, theme.match)
 		case "movie":
-			text = theme.movie.Render(text)
+			text = markMatches(text, hits, offset, theme.movie, theme.match)
 		case "rating":
 			text = styleRating(text, theme)
+		default:
+			text = markMatches(text, hits, offset, lipgloss.NewStyle(), theme.match)
 		}
 		appendWithSpacing(&out, text)
 	}
@@ -3,6 +3,7 @@ package ui
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 
@@ -84,7 +85,7 @@ func TestRenderDiary(t *testing.T) {
 	if out := stripANSI(renderDiary(m, theme)); !strings.Contains(out, "Loading diary") {
 		t.Fatalf("expected loading output, got %q", out)
 	}
-	m = Model{diary: []letterboxd.DiaryEntry{{Title: "Inception", Rating: "★★★★", Rewatch: true, Review: true, Date: "Jan 5 2024"}}}
+	m = Model{diary: []letterboxd.DiaryEntry{{Title: "Inception", Rating: "★★★★", Rewatch: true, Review: true, Date: time.Date(2024, time.January, 5, 0, 0, 0, 0, time.UTC)}}}
 	out := stripANSI(renderDiary(m, theme))
 	if !strings.Contains(out, "Inception") || !strings.Contains(out, "★★★★") {
 		t.Fatalf("unexpected diary output: %q", out)
@@ -107,6 +108,18 @@ func TestRenderWatchlist(t *testing.T) {
 	}
 }
 
+func TestRenderFilms(t *testing.T) {
+	theme := newTheme()
+	m := Model{filmsFilter: letterboxd.FilmsFilter{Genre: "horror"}, films: []letterboxd.UserFilm{{Title: "Alien", Year: "1979", Rating: "★★★★", Liked: true}}}
+	out := stripANSI(renderFilms(m, theme))
+	if !strings.Contains(out, "horror · sort: Watched") {
+		t.Fatalf("expected filter summary: %q", out)
+	}
+	if !strings.Contains(out, "Alien (1979) ★★★★ ♥") {
+		t.Fatalf("unexpected films output: %q", out)
+	}
+}
+
 func TestRenderProfileContent(t *testing.T) {
 	theme := newTheme()
 	profile := letterboxd.Profile{
@@ -114,7 +127,7 @@ func TestRenderProfileContent(t *testing.T) {
 		Favorites: []letterboxd.FavoriteFilm{{Title: "Inception", Year: "2010"}},
 		Recent:    []letterboxd.ProfileRecent{{Summary: "jane watched Inception", FilmURL: letterboxd.BaseURL + "/film/inception/"}},
 	}
-	out := stripANSI(renderProfileContent(profile, nil, false, "jane", nil, 0, 80, theme))
+	out := stripANSI(renderProfileContent(profile, nil, false, "jane", nil, "", 0, 80, theme))
 	if !strings.Contains(out, "Films") || !strings.Contains(out, "Inception") {
 		t.Fatalf("unexpected profile output: %q", out)
 	}
@@ -202,7 +215,7 @@ func TestRenderReviews(t *testing.T) {
 
 func TestRenderActivity(t *testing.T) {
 	theme := newTheme()
-	out := stripANSI(renderActivity(nil, errDummy{}, 0, 80, theme))
+	out := stripANSI(renderActivity(nil, errDummy{}, 0, 80, "", theme))
 	if !strings.Contains(out, "Error:") {
 		t.Fatalf("expected error output")
 	}
@@ -211,7 +224,7 @@ func TestRenderActivity(t *testing.T) {
 		Title: "Inception",
 		Parts: []letterboxd.SummaryPart{{Text: "Jane", Kind: "user"}, {Text: "watched", Kind: "text"}, {Text: "Inception", Kind: "movie"}},
 	}
-	out = stripANSI(renderActivity([]letterboxd.ActivityItem{item}, nil, 0, 80, theme))
+	out = stripANSI(renderActivity([]letterboxd.ActivityItem{item}, nil, 0, 80, "", theme))
 	if !strings.Contains(out, "Inception") {
 		t.Fatalf("unexpected activity output: %q", out)
 	}
@@ -226,12 +239,12 @@ func TestRenderSummary(t *testing.T) {
 		Rating:  "★★★★",
 		Kind:    "diary",
 	}
-	out := stripANSI(renderSummary(item, theme))
+	out := stripANSI(renderSummary(item, "", theme))
 	if !strings.Contains(out, "Jane") || !strings.Contains(out, "Inception") {
 		t.Fatalf("unexpected summary output: %q", out)
 	}
 	item.Parts = []letterboxd.SummaryPart{{Text: "Jane", Kind: "user"}, {Text: "Inception", Kind: "movie"}}
-	out = stripANSI(renderSummary(item, theme))
+	out = stripANSI(renderSummary(item, "", theme))
 	if !strings.Contains(out, "Jane") || !strings.Contains(out, "Inception") {
 		t.Fatalf("unexpected summary parts output: %q", out)
 	}
@@ -256,3 +269,22 @@ func TestRenderBreadcrumbs(t *testing.T) {
 type errDummy struct{}
 
 func (errDummy) Error() string { return "boom" }
+
+func TestRenderComparison(t *testing.T) {
+	cmp := letterboxd.Comparison{
+		UserA:         "jane",
+		UserB:         "joe",
+		WatchedA:      10,
+		WatchedB:      20,
+		Shared:        []letterboxd.SharedFilm{{Title: "Cats", Year: "2019", RatingA: "½", RatingB: "★★★★½"}},
+		BothRated:     1,
+		Disagreements: []letterboxd.SharedFilm{{Title: "Cats", Year: "2019", RatingA: "½", RatingB: "★★★★½"}},
+		LovedByBForA:  []letterboxd.UserFilm{{Title: "Ran", Year: "1985", Rating: "★★★★★"}},
+	}
+	out := stripANSI(ComparisonReport(cmp, 100))
+	for _, want := range []string{"@jane", "@joe", "10 films watched", "1 films in common", "not enough shared ratings", "Cats (2019) ½", "★★★★½", "Loved by @joe, on @jane's watchlist", "Ran (1985)"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in report: %q", want, out)
+		}
+	}
+}